@@ -5,11 +5,18 @@ import (
 	"github.com/mikhailbolshakov/decision"
 	domain "github.com/mikhailbolshakov/decision/domain/decision"
 	"github.com/mikhailbolshakov/decision/domain/decision/impl"
+	decisionGrpc "github.com/mikhailbolshakov/decision/grpc/decision"
 	"github.com/mikhailbolshakov/decision/http"
 	decisionHttp "github.com/mikhailbolshakov/decision/http/decision"
 	"github.com/mikhailbolshakov/decision/http/sys"
 	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/auth"
+	kitGrpc "github.com/mikhailbolshakov/decision/kit/grpc"
+	"github.com/mikhailbolshakov/decision/kit/health"
 	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"github.com/mikhailbolshakov/decision/kit/tracing"
+	pb "github.com/mikhailbolshakov/decision/proto/decision"
+	"github.com/mikhailbolshakov/decision/repository/storage"
 )
 
 // ServiceImpl implements a service bootstrapping
@@ -18,7 +25,16 @@ type ServiceImpl struct {
 	cfg             *decision.Config
 	loadCfgFn       func() (*decision.Config, error)
 	http            *kitHttp.Server
+	grpc            *kitGrpc.Server
+	storageAdapter  storage.Adapter
+	methodRegistry  domain.MethodRegistry
 	decisionService domain.DecisionService
+	problemService  domain.ProblemService
+	groupService    domain.GroupService
+	templateService domain.TemplateService
+	exchangeService domain.ExchangeService
+	codec           domain.ProblemCodec
+	health          health.Registry
 }
 
 // New creates a new instance of the service
@@ -26,7 +42,14 @@ func New() kit.Service {
 	s := &ServiceImpl{
 		loadCfgFn: decision.LoadConfig,
 	}
-	s.decisionService = impl.NewDecisionService()
+	s.storageAdapter = storage.NewAdapter()
+	s.methodRegistry = impl.NewMethodRegistry()
+	s.decisionService = impl.NewDecisionService(s.methodRegistry, s.storageAdapter.GetProblemStorage(), s.storageAdapter.GetDecisionStorage())
+	s.problemService = impl.NewProblemService(s.methodRegistry, s.storageAdapter.GetProblemStorage())
+	s.groupService = impl.NewGroupService(s.methodRegistry, s.storageAdapter.GetProblemStorage(), s.storageAdapter.GetGroupStorage(), s.storageAdapter.GetDecisionStorage())
+	s.templateService = impl.NewTemplateService(s.problemService, s.storageAdapter.GetTemplateStorage())
+	s.codec = impl.NewProblemCodec()
+	s.exchangeService = impl.NewExchangeService(s.problemService, s.codec)
 	return s
 }
 
@@ -38,22 +61,31 @@ func (s *ServiceImpl) GetCode() string {
 	return "decision"
 }
 
-func (s *ServiceImpl) initHttpServer(ctx context.Context) error {
+func (s *ServiceImpl) initHttpServer(ctx context.Context, verifier auth.Verifier) error {
 	// create HTTP server
 	s.http = kitHttp.NewHttpServer(s.cfg.Http, decision.LF())
+	if err := s.http.Limiter.Init(ctx); err != nil {
+		return err
+	}
 
 	// create and set middlewares
-	mdw := http.NewMiddleware()
+	mdw := http.NewMiddleware(verifier, s.cfg.Auth.AdminRole, s.cfg.Auth.RolePermissions())
 	s.http.RootRouter.Use(mdw.SetContextMiddleware)
 
 	// decision routing
 	routeBuilder := http.NewRouteBuilder(s.http, mdw)
-	routeBuilder.SetRoutes(sys.GetRoutes(sys.NewController()))
-	routeBuilder.SetRoutes(decisionHttp.GetRoutes(decisionHttp.NewController(s.decisionService)))
+	routeBuilder.SetRoutes(sys.GetRoutes(sys.NewController(s.health)))
+	routeBuilder.SetRoutes(decisionHttp.GetRoutes(decisionHttp.NewController(s.decisionService, s.problemService, s.groupService, s.templateService, s.exchangeService, s.codec)))
+	routeBuilder.SetRoutes([]*http.Route{routeBuilder.OpenApiRoute(&http.OpenApiInfo{Title: "Decision API", Version: "1.0.0"})})
 
 	return routeBuilder.Build()
 }
 
+func (s *ServiceImpl) initGrpcServer(verifier auth.Verifier) {
+	s.grpc = kitGrpc.NewServer(s.cfg.Grpc, decision.LF()).WithAuth(verifier)
+	pb.RegisterDecisionServiceServer(s.grpc.Srv, decisionGrpc.NewServer(s.decisionService, s.cfg.Auth.AdminRole))
+}
+
 // Init does all initializations
 func (s *ServiceImpl) Init(ctx context.Context) error {
 
@@ -67,11 +99,45 @@ func (s *ServiceImpl) Init(ctx context.Context) error {
 	// set log config
 	decision.Logger.Init(s.cfg.Log)
 
+	// set up span exporter
+	if err := tracing.Init(s.cfg.Tracing); err != nil {
+		return err
+	}
+
+	// create health checks registry, components register their checks when initialized
+	s.health = health.NewRegistry(s.cfg.Health, decision.LF())
+
+	// register decision methods
+	for _, m := range impl.BuiltInMethods() {
+		if err := s.methodRegistry.Register(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	// init storages
+	if err := s.storageAdapter.Init(ctx, s.cfg.Storages.Database); err != nil {
+		return err
+	}
+
+	// create token verifier, both HTTP and gRPC calls are authenticated with it
+	verifier, err := auth.NewVerifier(s.cfg.Auth.Jwt)
+	if err != nil {
+		return err
+	}
+
 	// init http server
-	if err := s.initHttpServer(ctx); err != nil {
+	if err := s.initHttpServer(ctx, verifier); err != nil {
 		return err
 	}
 
+	// init grpc server
+	s.initGrpcServer(verifier)
+
+	// register components health checks
+	s.storageAdapter.RegisterHealthChecks(s.health)
+	s.http.RegisterHealthChecks(s.health)
+	s.grpc.RegisterHealthChecks(s.health)
+
 	return nil
 }
 
@@ -80,9 +146,27 @@ func (s *ServiceImpl) Start(ctx context.Context) error {
 	// listen HTTP connections
 	s.http.Listen()
 
+	// listen gRPC connections
+	s.grpc.Listen()
+
 	return nil
 }
 
+// Close closes dependencies in order
+// incoming requests are drained first, so that domain services aren't called anymore,
+// then running computations are waited for and storages are closed the last as the others use them
 func (s *ServiceImpl) Close(ctx context.Context) {
-	s.http.Close()
+	closer := kit.NewCloser(decision.LF())
+	if s.http != nil {
+		closer.Add("http", s.http.Shutdown)
+	}
+	if s.grpc != nil {
+		closer.Add("grpc", s.grpc.Shutdown)
+	}
+	_ = closer.
+		Add("decision-svc", s.decisionService.Close).
+		Add("group-svc", s.groupService.Close).
+		Add("storage", s.storageAdapter.Close).
+		Add("tracing", func(context.Context) error { return tracing.Close() }).
+		Close(ctx)
 }
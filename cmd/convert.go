@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/mikhailbolshakov/decision/domain/decision/impl"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/i18n"
+	"golang.org/x/text/language"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const cmdConvert = "convert"
+
+// convert converts a problem between exchange formats (json, yaml, csv) without running the service
+// formats are taken from file extensions unless specified explicitly, stdin and stdout are used if files aren't specified
+//
+//	decision convert -in matrix.csv -out problem.yaml
+//	decision convert -from json -to csv < problem.json > matrix.csv
+func convert(ctx context.Context, args []string) int {
+	flags := flag.NewFlagSet(cmdConvert, flag.ContinueOnError)
+	in := flags.String("in", "", "input file, stdin if empty")
+	out := flags.String("out", "", "output file, stdout if empty")
+	from := flags.String("from", "", "input format: json, yaml or csv; by input file extension if empty")
+	to := flags.String("to", "", "output format: json, yaml or csv; by output file extension if empty")
+	name := flags.String("name", "", "problem name if the input doesn't specify one; by input file name if empty")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	codec := impl.NewProblemCodec()
+	if *from == "" {
+		*from = codec.Format(*in)
+	}
+	if *to == "" {
+		*to = codec.Format(*out)
+	}
+	if *from == "" || *to == "" {
+		fmt.Fprintln(os.Stderr, "input and output formats must be specified explicitly or by file extensions")
+		flags.Usage()
+		return 2
+	}
+	if *name == "" && *in != "" {
+		*name = strings.TrimSuffix(filepath.Base(*in), filepath.Ext(*in))
+	}
+
+	var input io.Reader = os.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer func() { _ = f.Close() }()
+		input = f
+	}
+
+	problem, err := codec.Decode(ctx, *from, *name, input)
+	if err != nil {
+		printErr(err)
+		return 1
+	}
+	content, err := codec.Encode(ctx, problem, *to)
+	if err != nil {
+		printErr(err)
+		return 1
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(content)
+	} else {
+		err = os.WriteFile(*out, content, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// printErr prints an error with a message pointing to the invalid row, column or path
+func printErr(err error) {
+	if appErr, ok := kit.IsAppErr(err); ok {
+		if msg, ok := i18n.Default.Message(language.Und, appErr.Code(), appErr.Fields()); ok {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", appErr.Code(), msg)
+			return
+		}
+	}
+	fmt.Fprintln(os.Stderr, err)
+}
@@ -14,6 +14,11 @@ func main() {
 	// init context
 	ctx := kit.NewRequestCtx().Empty().WithNewRequestId().ToContext(context.Background())
 
+	// subcommands
+	if len(os.Args) > 1 && os.Args[1] == cmdConvert {
+		os.Exit(convert(ctx, os.Args[2:]))
+	}
+
 	// create a new service
 	s := bootstrap.New()
 
@@ -40,6 +45,13 @@ func main() {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 	l.Inf("graceful shutdown")
+	// the second signal interrupts draining
+	go func() {
+		<-quit
+		l.Warn("forced shutdown")
+		os.Exit(1)
+	}()
 	s.Close(ctx)
+	l.Inf("closed")
 	os.Exit(0)
 }
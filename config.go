@@ -2,9 +2,13 @@ package decision
 
 import (
 	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/auth"
 	kitConfig "github.com/mikhailbolshakov/decision/kit/config"
+	kitGrpc "github.com/mikhailbolshakov/decision/kit/grpc"
+	"github.com/mikhailbolshakov/decision/kit/health"
 	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
 	"github.com/mikhailbolshakov/decision/kit/storages/pg"
+	"github.com/mikhailbolshakov/decision/kit/tracing"
 	"os"
 	"path/filepath"
 )
@@ -28,10 +32,35 @@ type CfgStorages struct {
 	Database *pg.DbClusterConfig
 }
 
+// CfgRolePermissions permissions granted to a role
+type CfgRolePermissions struct {
+	Role        string   `config:"role"`
+	Permissions []string `config:"permissions"`
+}
+
+type CfgAuth struct {
+	Jwt         *auth.Config
+	AdminRole   string                `config:"admin-role"`
+	Permissions []*CfgRolePermissions `config:"permissions"` // Permissions grants permissions to roles
+}
+
+// RolePermissions returns permissions by roles
+func (c *CfgAuth) RolePermissions() map[string][]string {
+	r := make(map[string][]string, len(c.Permissions))
+	for _, p := range c.Permissions {
+		r[p.Role] = append(r[p.Role], p.Permissions...)
+	}
+	return r
+}
+
 type Config struct {
 	Storages *CfgStorages
 	Log      *kit.LogConfig
 	Http     *kitHttp.Config
+	Grpc     *kitGrpc.Config
+	Auth     *CfgAuth
+	Tracing  *tracing.Config
+	Health   *health.Config
 }
 
 func LoadConfig() (*Config, error) {
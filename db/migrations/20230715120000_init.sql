@@ -0,0 +1,62 @@
+-- +goose Up
+create table if not exists decision.problems
+(
+  id         uuid primary key,
+  user_id    uuid      not null,
+  name       varchar   not null,
+  created_at timestamp not null,
+  updated_at timestamp not null,
+  deleted_at timestamp null
+);
+
+create index if not exists idx_problems_user on decision.problems (user_id);
+
+create table if not exists decision.options
+(
+  id         uuid primary key,
+  problem_id uuid      not null references decision.problems (id),
+  name       varchar   not null,
+  ord        int       not null default 0,
+  created_at timestamp not null,
+  updated_at timestamp not null,
+  deleted_at timestamp null
+);
+
+create index if not exists idx_options_problem on decision.options (problem_id);
+
+create table if not exists decision.qualities
+(
+  id          uuid primary key,
+  problem_id  uuid             not null references decision.problems (id),
+  option_id   uuid             not null references decision.options (id),
+  kind        varchar          not null,
+  name        varchar          not null,
+  importance  double precision not null default 0,
+  probability double precision not null default 0,
+  ord         int              not null default 0,
+  created_at  timestamp        not null,
+  updated_at  timestamp        not null,
+  deleted_at  timestamp        null
+);
+
+create index if not exists idx_qualities_problem on decision.qualities (problem_id);
+create index if not exists idx_qualities_option on decision.qualities (option_id);
+
+create table if not exists decision.decisions
+(
+  id         uuid primary key,
+  problem_id uuid      not null references decision.problems (id),
+  user_id    uuid      not null,
+  result     jsonb     not null,
+  created_at timestamp not null,
+  updated_at timestamp not null,
+  deleted_at timestamp null
+);
+
+create index if not exists idx_decisions_problem on decision.decisions (problem_id);
+
+-- +goose Down
+drop table if exists decision.decisions;
+drop table if exists decision.qualities;
+drop table if exists decision.options;
+drop table if exists decision.problems;
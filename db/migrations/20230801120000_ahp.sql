@@ -0,0 +1,7 @@
+-- +goose Up
+alter table decision.problems add column if not exists method varchar not null default 'pros-cons';
+alter table decision.problems add column if not exists ahp jsonb null;
+
+-- +goose Down
+alter table decision.problems drop column if exists ahp;
+alter table decision.problems drop column if exists method;
@@ -0,0 +1,9 @@
+-- +goose Up
+alter table decision.problems add column if not exists simulation jsonb null;
+alter table decision.qualities add column if not exists importance_dist jsonb null;
+alter table decision.qualities add column if not exists probability_dist jsonb null;
+
+-- +goose Down
+alter table decision.qualities drop column if exists probability_dist;
+alter table decision.qualities drop column if exists importance_dist;
+alter table decision.problems drop column if exists simulation;
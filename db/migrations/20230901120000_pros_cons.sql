@@ -0,0 +1,5 @@
+-- +goose Up
+alter table decision.problems add column if not exists pros_cons jsonb null;
+
+-- +goose Down
+alter table decision.problems drop column if exists pros_cons;
@@ -0,0 +1,32 @@
+-- +goose Up
+create table if not exists decision.members
+(
+  id         uuid primary key,
+  problem_id uuid             not null references decision.problems (id),
+  user_id    uuid             not null,
+  weight     double precision not null default 1,
+  created_at timestamp        not null,
+  updated_at timestamp        not null,
+  deleted_at timestamp        null
+);
+
+create unique index if not exists idx_members_problem_user on decision.members (problem_id, user_id);
+
+create table if not exists decision.assessments
+(
+  id          uuid primary key,
+  problem_id  uuid             not null references decision.problems (id),
+  user_id     uuid             not null,
+  quality_id  uuid             not null references decision.qualities (id),
+  importance  double precision not null default 0,
+  probability double precision not null default 0,
+  created_at  timestamp        not null,
+  updated_at  timestamp        not null,
+  deleted_at  timestamp        null
+);
+
+create index if not exists idx_assessments_problem on decision.assessments (problem_id);
+
+-- +goose Down
+drop table if exists decision.assessments;
+drop table if exists decision.members;
@@ -0,0 +1,21 @@
+-- +goose Up
+alter table decision.problems add column if not exists version int not null default 0;
+alter table decision.decisions add column if not exists problem_version int not null default 0;
+
+create table if not exists decision.problem_versions
+(
+  id         uuid primary key,
+  problem_id uuid      not null references decision.problems (id),
+  version    int       not null,
+  snapshot   jsonb     not null,
+  created_at timestamp not null,
+  updated_at timestamp not null,
+  deleted_at timestamp null
+);
+
+create unique index if not exists idx_problem_versions_problem_version on decision.problem_versions (problem_id, version);
+
+-- +goose Down
+drop table if exists decision.problem_versions;
+alter table decision.decisions drop column if exists problem_version;
+alter table decision.problems drop column if exists version;
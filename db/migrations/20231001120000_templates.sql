@@ -0,0 +1,59 @@
+-- +goose Up
+alter table decision.options add column if not exists fields jsonb null;
+
+create table if not exists decision.templates
+(
+  id            uuid primary key,
+  user_id       uuid      null,
+  name          varchar   not null,
+  description   varchar   null,
+  category      varchar   null,
+  criteria      jsonb     not null,
+  option_fields jsonb     null,
+  created_at    timestamp not null,
+  updated_at    timestamp not null,
+  deleted_at    timestamp null
+);
+
+create index if not exists idx_templates_user on decision.templates (user_id);
+
+-- system templates
+insert into decision.templates (id, user_id, name, description, category, criteria, option_fields, created_at, updated_at)
+values ('6a0d7c2e-3f1b-4c8e-9a4d-1e2f3a4b5c01', null, 'Vendors', 'Choosing a vendor or a service provider', 'business',
+        '[{"name":"Price","kind":"con","importance":8,"probability":1},
+          {"name":"Quality of product","kind":"pro","importance":9,"probability":1},
+          {"name":"Delivery time","kind":"con","importance":6,"probability":1},
+          {"name":"Support","kind":"pro","importance":5,"probability":1},
+          {"name":"Reputation","kind":"pro","importance":6,"probability":1},
+          {"name":"Lock-in risk","kind":"con","importance":4,"probability":0.5}]',
+        '[{"name":"website","type":"url"},
+          {"name":"email","type":"email"},
+          {"name":"phone","type":"phone"}]',
+        now(), now()),
+       ('6a0d7c2e-3f1b-4c8e-9a4d-1e2f3a4b5c02', null, 'Job offers', 'Choosing between job offers', 'career',
+        '[{"name":"Salary","kind":"pro","importance":9,"probability":1},
+          {"name":"Career growth","kind":"pro","importance":7,"probability":0.7},
+          {"name":"Team and culture","kind":"pro","importance":6,"probability":0.8},
+          {"name":"Work-life balance","kind":"pro","importance":6,"probability":0.8},
+          {"name":"Commute","kind":"con","importance":4,"probability":1},
+          {"name":"Company stability risk","kind":"con","importance":5,"probability":0.3}]',
+        '[{"name":"company","type":"string","required":true},
+          {"name":"salary","type":"number"},
+          {"name":"url","type":"url"}]',
+        now(), now()),
+       ('6a0d7c2e-3f1b-4c8e-9a4d-1e2f3a4b5c03', null, 'Cars', 'Choosing a car to buy', 'vehicles',
+        '[{"name":"Price","kind":"con","importance":9,"probability":1},
+          {"name":"Range","kind":"pro","importance":8,"probability":1},
+          {"name":"Safety","kind":"pro","importance":8,"probability":1},
+          {"name":"Comfort","kind":"pro","importance":5,"probability":1},
+          {"name":"Maintenance costs","kind":"con","importance":6,"probability":0.8},
+          {"name":"Depreciation","kind":"con","importance":5,"probability":0.9}]',
+        '[{"name":"vin","type":"vin"},
+          {"name":"regNumber","type":"car-reg-number"},
+          {"name":"price","type":"number"}]',
+        now(), now())
+on conflict (id) do nothing;
+
+-- +goose Down
+drop table if exists decision.templates;
+alter table decision.options drop column if exists fields;
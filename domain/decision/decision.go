@@ -1,39 +1,130 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+const (
+	// MethodProsCons rates an option as ratio of weighted pros to weighted cons
+	MethodProsCons = "pros-cons"
+	// MethodAhp rates options by Analytic Hierarchy Process
+	MethodAhp = "ahp"
+	// MethodTopsis rates options by closeness to the ideal solution
+	MethodTopsis = "topsis"
+
+	// ScoringRatio rates an option as (pros + smoothing) / (cons + smoothing)
+	ScoringRatio = "ratio"
+	// ScoringNet rates an option as normalized net score (pros - cons) / (pros + cons + smoothing) in range [-1, 1]
+	ScoringNet = "net"
+
+	// TopsisBenefit bigger score is better
+	TopsisBenefit = "benefit"
+	// TopsisCost smaller score is better
+	TopsisCost = "cost"
+)
 
 type Quality struct {
-	Id          string
-	Name        string
-	Importance  float64
-	Probability float64
+	Id              string
+	Name            string
+	Importance      float64
+	Probability     float64
+	ImportanceDist  *Distribution // ImportanceDist importance distribution for simulation, Importance is used if empty
+	ProbabilityDist *Distribution // ProbabilityDist probability distribution for simulation, Probability is used if empty
 }
 
 type Option struct {
-	Id   string
-	Name string
-	Pros []*Quality
-	Cons []*Quality
+	Id     string
+	Name   string
+	Pros   []*Quality
+	Cons   []*Quality
+	Fields map[string]string // Fields option attributes (e.g. defined by a template)
+}
+
+// ProsCons specifies scoring model for MethodProsCons
+// pros and cons are sums of importance * probability of the option qualities
+type ProsCons struct {
+	Scoring   string   // Scoring ScoringRatio or ScoringNet, ScoringRatio if empty
+	Smoothing *float64 // Smoothing additive smoothing; if empty, 0 is applied unless some option has zero cons, then 1 is applied to all options
+}
+
+// Ahp specifies pairwise comparisons for AHP method
+// all matrices are square, positive and reciprocal (a[i][j] = 1 / a[j][i])
+type Ahp struct {
+	Criteria            []string      // Criteria names of criteria
+	CriteriaComparisons [][]float64   // CriteriaComparisons pairwise comparisons of criteria
+	OptionComparisons   [][][]float64 // OptionComparisons pairwise comparisons of options (in order of problem options) for each criterion
+}
+
+type TopsisCriterion struct {
+	Name      string
+	Weight    float64 // Weight criterion weight, weights are normalized
+	Direction string  // Direction TopsisBenefit or TopsisCost, TopsisBenefit if empty
+}
+
+// Topsis specifies a decision matrix for TOPSIS method
+type Topsis struct {
+	Criteria []*TopsisCriterion
+	Scores   [][]float64 // Scores scores of options (in order of problem options) by each criterion
 }
 
 type Problem struct {
-	Id      string
-	Name    string
-	Options []*Option
+	Id         string
+	UserId     string
+	Name       string
+	Method     string      // Method decision method, MethodProsCons if empty
+	ProsCons   *ProsCons   // ProsCons scoring model for MethodProsCons, optional
+	Ahp        *Ahp        // Ahp comparisons, required for MethodAhp only
+	Topsis     *Topsis     // Topsis decision matrix, required for MethodTopsis only
+	Simulation *Simulation // Simulation if specified, Monte Carlo simulation is run along with the decision (pros-cons method only)
+	Options    []*Option
+	Version    int // Version current version of the problem, each change creates a new version
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 type DecisionResult struct {
-	OptionsRating map[string]float64
+	Method           string
+	OptionsRating    map[string]float64
+	Scoring          string            // Scoring applied scoring model (pros-cons only)
+	Smoothing        float64           // Smoothing applied smoothing (pros-cons only)
+	ConsistencyRatio float64           // ConsistencyRatio the worst consistency ratio of comparison matrices (AHP only)
+	Simulation       *SimulationResult // Simulation Monte Carlo simulation result, if requested
+	Group            *GroupResult      // Group group decision details
+	Explanation      *Explanation      // Explanation ranking of options and why the winner is chosen
 }
 
 type Decision struct {
-	Id        string
-	ProblemId string
-	UserId    string
-	Result    DecisionResult
+	Id             string
+	ProblemId      string
+	ProblemVersion int // ProblemVersion version of the problem the decision was made on
+	UserId         string
+	Result         DecisionResult
+	CreatedAt      time.Time
 }
 
 type DecisionService interface {
 	// MakeDecision makes decision for the problem
+	// if userId is specified, the problem and the decision are stored
 	MakeDecision(ctx context.Context, userId string, problem *Problem) (*Decision, error)
+	// MakeDecisionByProblem makes and stores decision for the stored user's problem
+	MakeDecisionByProblem(ctx context.Context, userId, problemId string) (*Decision, error)
+	// GetDecision retrieves a stored decision by id
+	GetDecision(ctx context.Context, decisionId string) (*Decision, error)
+	// AnalyzeSensitivity analyzes how importance and probability of qualities affect the decision, pros-cons method only
+	AnalyzeSensitivity(ctx context.Context, problem *Problem, params *SensitivityParams) (*SensitivityResult, error)
+	// GetMethods retrieves descriptions of all available decision methods
+	GetMethods(ctx context.Context) []*MethodDescription
+	// Close rejects new computations and waits for the running ones, they're cancelled when ctx is done
+	Close(ctx context.Context) error
+}
+
+type DecisionStorage interface {
+	// CreateDecision stores a decision
+	CreateDecision(ctx context.Context, decision *Decision) error
+	// GetDecision retrieves a decision by id
+	// returns nil if not found
+	GetDecision(ctx context.Context, decisionId string) (*Decision, error)
+	// GetDecisionsByProblem retrieves all decisions made for the problem
+	GetDecisionsByProblem(ctx context.Context, problemId string) ([]*Decision, error)
 }
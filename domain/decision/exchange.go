@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+const (
+	// ExchangeFormatJson versioned JSON document with the whole problem
+	ExchangeFormatJson = "json"
+	// ExchangeFormatYaml versioned YAML document with the whole problem
+	ExchangeFormatYaml = "yaml"
+	// ExchangeFormatCsv options×qualities matrix, a row per option and a column per quality or option field
+	// method specific params and distributions aren't kept
+	ExchangeFormatCsv = "csv"
+
+	// ExchangeDocumentVersion version of JSON and YAML documents produced by export
+	ExchangeDocumentVersion = 1
+)
+
+// ProblemCodec converts problems to exchange formats and back
+type ProblemCodec interface {
+	// Encode converts the problem to the format
+	Encode(ctx context.Context, problem *Problem, format string) ([]byte, error)
+	// Decode converts the content in the format to a problem
+	// name is applied if the content doesn't specify a problem name (e.g. CSV)
+	// errors point to the row and column (CSV) or the path (JSON, YAML) of an invalid value
+	Decode(ctx context.Context, format, name string, content io.Reader) (*Problem, error)
+	// Format returns a format by file name extension, empty if not supported
+	Format(filename string) string
+	// ContentType returns content type of the format
+	ContentType(format string) string
+}
+
+type ExchangeService interface {
+	// ExportProblem exports the user's problem to the format
+	ExportProblem(ctx context.Context, userId, problemId, format string) ([]byte, error)
+	// ImportProblem creates a problem of the user from the content in the format
+	ImportProblem(ctx context.Context, userId, format, name string, content io.Reader) (*Problem, error)
+}
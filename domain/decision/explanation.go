@@ -0,0 +1,28 @@
+package domain
+
+// QualityContribution describes how much the quality contributes to the weighted pros or cons of the option
+type QualityContribution struct {
+	QualityId string
+	Name      string
+	Kind      string  // Kind QualityKindPro or QualityKindCon
+	Weight    float64 // Weight importance * probability
+	Share     float64 // Share weight divided by the weighted total of the same kind (pros or cons) of the option, in range [0, 1]
+}
+
+// OptionExplanation describes the result of the option
+type OptionExplanation struct {
+	OptionId      string
+	Name          string
+	Rank          int                    // Rank position in the ranking starting from 1, tied options share the rank
+	Rating        float64                // Rating option rating
+	ProsTotal     float64                // ProsTotal weighted sum of pros (pros-cons only)
+	ConsTotal     float64                // ConsTotal weighted sum of cons (pros-cons only)
+	Contributions []*QualityContribution // Contributions pros and cons ordered by weight (pros-cons only)
+}
+
+// Explanation explains why the top-ranked option wins
+type Explanation struct {
+	Ranking []*OptionExplanation // Ranking options ordered by rating, the first one is the winner
+	Margin  float64              // Margin rating difference between the winner and the runner-up, 0 if there is a single option
+	Summary string               // Summary short natural-language explanation in the language of the request
+}
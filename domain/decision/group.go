@@ -0,0 +1,74 @@
+package domain
+
+import "context"
+
+const (
+	// AggregationMean assessments are aggregated by weighted arithmetic mean
+	AggregationMean = "mean"
+	// AggregationGeometric assessments are aggregated by weighted geometric mean
+	AggregationGeometric = "geometric"
+	// AggregationBorda individual rankings are aggregated by weighted Borda count
+	AggregationBorda = "borda"
+)
+
+// Member a user participating in a group decision
+type Member struct {
+	UserId string
+	Weight float64 // Weight user's weight in aggregation
+}
+
+// Assessment user's own assessment of a quality
+type Assessment struct {
+	UserId      string
+	QualityId   string
+	Importance  float64
+	Probability float64
+}
+
+// QualitySpread disagreement of participants about a quality
+type QualitySpread struct {
+	QualityId         string
+	ImportanceMin     float64
+	ImportanceMax     float64
+	ImportanceStdDev  float64
+	ProbabilityMin    float64
+	ProbabilityMax    float64
+	ProbabilityStdDev float64
+}
+
+// GroupResult group decision details
+type GroupResult struct {
+	Aggregation  string           // Aggregation aggregation method
+	Participants []*Member        // Participants users whose assessments were aggregated
+	Spread       []*QualitySpread // Spread disagreement per quality
+}
+
+type GroupService interface {
+	// SetMembers sets members of the problem, available for the owner only
+	// the owner participates with weight 1 unless specified explicitly
+	SetMembers(ctx context.Context, userId, problemId string, members []*Member) ([]*Member, error)
+	// GetMembers retrieves members of the problem
+	GetMembers(ctx context.Context, userId, problemId string) ([]*Member, error)
+	// SetAssessments replaces the user's assessments of the problem qualities
+	SetAssessments(ctx context.Context, userId, problemId string, assessments []*Assessment) ([]*Assessment, error)
+	// GetAssessments retrieves assessments of all participants
+	GetAssessments(ctx context.Context, userId, problemId string) ([]*Assessment, error)
+	// MakeGroupDecision aggregates assessments of all participants and makes decision
+	// if a participant hasn't assessed a quality, the problem's values are used
+	MakeGroupDecision(ctx context.Context, userId, problemId, aggregation string) (*Decision, error)
+	// GetDecisions retrieves all decisions made for the problem, available for the owner and members
+	GetDecisions(ctx context.Context, userId, problemId string) ([]*Decision, error)
+	// Close rejects new computations and waits for the running ones, they're cancelled when ctx is done
+	Close(ctx context.Context) error
+}
+
+type GroupStorage interface {
+	// SetMembers replaces members of the problem
+	SetMembers(ctx context.Context, problemId string, members []*Member) error
+	// GetMembers retrieves members of the problem
+	GetMembers(ctx context.Context, problemId string) ([]*Member, error)
+	// SetAssessments replaces the user's assessments of the problem
+	SetAssessments(ctx context.Context, problemId, userId string, assessments []*Assessment) error
+	// GetAssessments retrieves all assessments of the problem
+	GetAssessments(ctx context.Context, problemId string) ([]*Assessment, error)
+}
@@ -0,0 +1,184 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"math"
+)
+
+const (
+	// ahpMaxConsistencyRatio comparisons with bigger consistency ratio are rejected
+	ahpMaxConsistencyRatio = 0.1
+	// ahpReciprocalTolerance allows rounded reciprocal values (e.g. 0.33 for 1/3)
+	ahpReciprocalTolerance = 0.02
+	// power iteration params
+	ahpMaxIterations = 1000
+	ahpEpsilon       = 1e-10
+)
+
+// ahpRandomIndex Saaty's random consistency index by matrix size
+var ahpRandomIndex = []float64{0, 0, 0, 0.58, 0.90, 1.12, 1.24, 1.32, 1.41, 1.45, 1.49, 1.51, 1.48, 1.56, 1.57, 1.59}
+
+// ahpMethod rates options by Analytic Hierarchy Process
+// priorities of criteria and options are derived as principal eigenvectors of comparison matrices,
+// an option rating is a sum of its priorities by each criterion weighted by the criterion priority
+type ahpMethod struct{}
+
+func NewAhpMethod() domain.DecisionMethod {
+	return &ahpMethod{}
+}
+
+func (m *ahpMethod) Code() string {
+	return domain.MethodAhp
+}
+
+func (m *ahpMethod) Describe() *domain.MethodDescription {
+	comparison := numberParam("how much i is preferable to j on Saaty's scale (1/9..9), a[j][i] = 1/a[i][j]", kit.Float64Ptr(1.0/9), kit.Float64Ptr(9))
+	matrix := arrayParam("pairwise comparison matrix", arrayParam("", comparison))
+	return &domain.MethodDescription{
+		Code:        domain.MethodAhp,
+		Name:        "Analytic Hierarchy Process",
+		Description: "priorities of criteria and options are derived from pairwise comparisons, comparisons with consistency ratio above 0.1 are rejected",
+		Params: map[string]*domain.ParamSchema{
+			"ahp": objectParam("pairwise comparisons", map[string]*domain.ParamSchema{
+				"criteria":            arrayParam("names of criteria", stringParam("criterion name")),
+				"criteriaComparisons": matrix,
+				"optionComparisons":   arrayParam("comparisons of options (in order of options) for each criterion", matrix),
+			}, "criteriaComparisons", "optionComparisons"),
+		},
+	}
+}
+
+func (m *ahpMethod) Calculate(ctx context.Context, problem *domain.Problem) (domain.DecisionResult, error) {
+
+	r := domain.DecisionResult{
+		Method:        domain.MethodAhp,
+		OptionsRating: make(map[string]float64, len(problem.Options)),
+	}
+
+	a := problem.Ahp
+	if a == nil || len(a.CriteriaComparisons) == 0 {
+		return r, errors.ErrDecisionAhpEmpty(ctx)
+	}
+
+	// criteria priorities
+	weights, cr, err := ahpPriorities(ctx, "criteria", a.CriteriaComparisons)
+	if err != nil {
+		return r, err
+	}
+	if len(a.Criteria) > 0 && len(a.Criteria) != len(weights) {
+		return r, errors.ErrDecisionAhpMatrixInvalid(ctx, "criteria", "size doesn't match criteria")
+	}
+	if len(a.OptionComparisons) != len(weights) {
+		return r, errors.ErrDecisionAhpMatrixInvalid(ctx, "options", "a matrix must be specified for each criterion")
+	}
+	r.ConsistencyRatio = cr
+
+	// options priorities by each criterion
+	rating := make([]float64, len(problem.Options))
+	for i, cmp := range a.OptionComparisons {
+		name := fmt.Sprintf("options[%d]", i)
+		if len(cmp) != len(problem.Options) {
+			return r, errors.ErrDecisionAhpMatrixInvalid(ctx, name, "size doesn't match options")
+		}
+		pr, cr, err := ahpPriorities(ctx, name, cmp)
+		if err != nil {
+			return r, err
+		}
+		r.ConsistencyRatio = math.Max(r.ConsistencyRatio, cr)
+		for j := range pr {
+			rating[j] += weights[i] * pr[j]
+		}
+	}
+
+	for i, op := range problem.Options {
+		r.OptionsRating[op.Id] = kit.Round10000(rating[i])
+	}
+	r.ConsistencyRatio = kit.Round10000(r.ConsistencyRatio)
+
+	return r, nil
+}
+
+// ahpPriorities validates the comparison matrix and calculates a priority vector and consistency ratio
+func ahpPriorities(ctx context.Context, name string, m [][]float64) ([]float64, float64, error) {
+	if err := ahpValidateMatrix(ctx, name, m); err != nil {
+		return nil, 0, err
+	}
+	v, lambda := principalEigenvector(m)
+	cr := consistencyRatio(lambda, len(m))
+	if cr > ahpMaxConsistencyRatio {
+		return nil, 0, errors.ErrDecisionAhpInconsistent(ctx, name, kit.Round10000(cr))
+	}
+	return v, cr, nil
+}
+
+func ahpValidateMatrix(ctx context.Context, name string, m [][]float64) error {
+	if len(m) == 0 {
+		return errors.ErrDecisionAhpMatrixInvalid(ctx, name, "empty")
+	}
+	for i := range m {
+		if len(m[i]) != len(m) {
+			return errors.ErrDecisionAhpMatrixInvalid(ctx, name, "not square")
+		}
+		for j := range m[i] {
+			if m[i][j] <= 0 || math.IsInf(m[i][j], 0) || math.IsNaN(m[i][j]) {
+				return errors.ErrDecisionAhpMatrixInvalid(ctx, name, "values must be positive")
+			}
+			if i == j && m[i][j] != 1 {
+				return errors.ErrDecisionAhpMatrixInvalid(ctx, name, "diagonal values must be 1")
+			}
+			if math.Abs(m[i][j]*m[j][i]-1) > ahpReciprocalTolerance {
+				return errors.ErrDecisionAhpMatrixInvalid(ctx, name, "not reciprocal")
+			}
+		}
+	}
+	return nil
+}
+
+// principalEigenvector calculates the normalized principal eigenvector and the max eigenvalue by power iteration
+func principalEigenvector(m [][]float64) ([]float64, float64) {
+	n := len(m)
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = 1.0 / float64(n)
+	}
+	lambda := 0.0
+	for it := 0; it < ahpMaxIterations; it++ {
+		next := make([]float64, n)
+		sum := 0.0
+		for i := range m {
+			for j := range m[i] {
+				next[i] += m[i][j] * v[j]
+			}
+			sum += next[i]
+		}
+		// since v is normalized, the sum of m*v converges to the max eigenvalue
+		lambda = sum
+		diff := 0.0
+		for i := range next {
+			next[i] /= sum
+			diff = math.Max(diff, math.Abs(next[i]-v[i]))
+		}
+		v = next
+		if diff < ahpEpsilon {
+			break
+		}
+	}
+	return v, lambda
+}
+
+// consistencyRatio CR = CI / RI, where CI = (lambda - n) / (n - 1)
+func consistencyRatio(lambda float64, n int) float64 {
+	if n <= 2 {
+		return 0
+	}
+	ri := ahpRandomIndex[len(ahpRandomIndex)-1]
+	if n < len(ahpRandomIndex) {
+		ri = ahpRandomIndex[n]
+	}
+	ci := (lambda - float64(n)) / float64(n-1)
+	return math.Max(ci/ri, 0)
+}
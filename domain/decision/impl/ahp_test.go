@@ -0,0 +1,103 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ahpTestSuite struct {
+	kit.Suite
+}
+
+func (s *ahpTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func TestAhpSuite(t *testing.T) {
+	suite.Run(t, new(ahpTestSuite))
+}
+
+func (s *ahpTestSuite) problem() *domain.Problem {
+	return &domain.Problem{
+		Id:     kit.NewId(),
+		Name:   "car",
+		Method: domain.MethodAhp,
+		Options: []*domain.Option{
+			{Id: kit.NewId(), Name: "first"},
+			{Id: kit.NewId(), Name: "second"},
+		},
+		Ahp: &domain.Ahp{
+			Criteria: []string{"price", "comfort"},
+			CriteriaComparisons: [][]float64{
+				{1, 3},
+				{1.0 / 3, 1},
+			},
+			OptionComparisons: [][][]float64{
+				{{1, 4}, {0.25, 1}},
+				{{1, 0.5}, {2, 1}},
+			},
+		},
+	}
+}
+
+func (s *ahpTestSuite) Test_PrincipalEigenvector_Consistent() {
+	m := [][]float64{
+		{1, 3, 5},
+		{1.0 / 3, 1, 5.0 / 3},
+		{1.0 / 5, 3.0 / 5, 1},
+	}
+	v, lambda := principalEigenvector(m)
+	s.InDelta(15.0/23, v[0], 1e-6)
+	s.InDelta(5.0/23, v[1], 1e-6)
+	s.InDelta(3.0/23, v[2], 1e-6)
+	s.InDelta(3.0, lambda, 1e-6)
+	s.InDelta(0.0, consistencyRatio(lambda, len(m)), 1e-6)
+}
+
+func (s *ahpTestSuite) Test_Ahp() {
+	problem := s.problem()
+	r, err := NewAhpMethod().Calculate(s.Ctx, problem)
+	s.NoError(err)
+	s.Equal(domain.MethodAhp, r.Method)
+	// 0.75 * 0.8 + 0.25 * 1/3 and 0.75 * 0.2 + 0.25 * 2/3
+	s.InDelta(0.6833, r.OptionsRating[problem.Options[0].Id], 1e-4)
+	s.InDelta(0.3167, r.OptionsRating[problem.Options[1].Id], 1e-4)
+	s.Equal(0.0, r.ConsistencyRatio)
+}
+
+func (s *ahpTestSuite) Test_Ahp_Inconsistent() {
+	problem := s.problem()
+	problem.Ahp.Criteria = []string{"price", "comfort", "speed"}
+	problem.Ahp.CriteriaComparisons = [][]float64{
+		{1, 9, 1.0 / 9},
+		{1.0 / 9, 1, 9},
+		{9, 1.0 / 9, 1},
+	}
+	_, err := NewAhpMethod().Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionAhpInconsistent)
+}
+
+func (s *ahpTestSuite) Test_Ahp_NotReciprocal() {
+	problem := s.problem()
+	problem.Ahp.CriteriaComparisons[1][0] = 3
+	_, err := NewAhpMethod().Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionAhpMatrixInvalid)
+}
+
+func (s *ahpTestSuite) Test_Ahp_OptionsSizeMismatch() {
+	problem := s.problem()
+	problem.Options = append(problem.Options, &domain.Option{Id: kit.NewId(), Name: "third"})
+	_, err := NewAhpMethod().Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionAhpMatrixInvalid)
+}
+
+func (s *ahpTestSuite) Test_Ahp_Empty() {
+	problem := s.problem()
+	problem.Ahp = nil
+	_, err := NewAhpMethod().Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionAhpEmpty)
+}
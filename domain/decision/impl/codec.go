@@ -0,0 +1,550 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// csvOptionColumn the first CSV column with option names
+	csvOptionColumn = "option"
+	// csvFieldPrefix prefix of CSV columns with option fields
+	csvFieldPrefix = "field"
+	// csvProbabilitySeparator separates importance and probability in a CSV cell (e.g. 8@0.5), probability is 1 if omitted
+	csvProbabilitySeparator = "@"
+)
+
+// problemDocument versioned JSON/YAML document
+type problemDocument struct {
+	Version int         `json:"version" yaml:"version"`
+	Problem *problemDoc `json:"problem" yaml:"problem"`
+}
+
+type problemDoc struct {
+	Name       string         `json:"name" yaml:"name"`
+	Method     string         `json:"method,omitempty" yaml:"method,omitempty"`
+	ProsCons   *prosConsDoc   `json:"prosCons,omitempty" yaml:"prosCons,omitempty"`
+	Ahp        *ahpDoc        `json:"ahp,omitempty" yaml:"ahp,omitempty"`
+	Topsis     *topsisDoc     `json:"topsis,omitempty" yaml:"topsis,omitempty"`
+	Simulation *simulationDoc `json:"simulation,omitempty" yaml:"simulation,omitempty"`
+	Options    []*optionDoc   `json:"options" yaml:"options"`
+}
+
+type prosConsDoc struct {
+	Scoring   string   `json:"scoring,omitempty" yaml:"scoring,omitempty"`
+	Smoothing *float64 `json:"smoothing,omitempty" yaml:"smoothing,omitempty"`
+}
+
+type ahpDoc struct {
+	Criteria            []string      `json:"criteria,omitempty" yaml:"criteria,omitempty"`
+	CriteriaComparisons [][]float64   `json:"criteriaComparisons" yaml:"criteriaComparisons"`
+	OptionComparisons   [][][]float64 `json:"optionComparisons" yaml:"optionComparisons"`
+}
+
+type topsisCriterionDoc struct {
+	Name      string  `json:"name,omitempty" yaml:"name,omitempty"`
+	Weight    float64 `json:"weight" yaml:"weight"`
+	Direction string  `json:"direction,omitempty" yaml:"direction,omitempty"`
+}
+
+type topsisDoc struct {
+	Criteria []*topsisCriterionDoc `json:"criteria" yaml:"criteria"`
+	Scores   [][]float64           `json:"scores" yaml:"scores"`
+}
+
+type simulationDoc struct {
+	Iterations int    `json:"iterations" yaml:"iterations"`
+	Seed       *int64 `json:"seed,omitempty" yaml:"seed,omitempty"`
+}
+
+type distributionDoc struct {
+	Type   string  `json:"type" yaml:"type"`
+	Min    float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max    float64 `json:"max,omitempty" yaml:"max,omitempty"`
+	Mode   float64 `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Mean   float64 `json:"mean,omitempty" yaml:"mean,omitempty"`
+	StdDev float64 `json:"stdDev,omitempty" yaml:"stdDev,omitempty"`
+	Alpha  float64 `json:"alpha,omitempty" yaml:"alpha,omitempty"`
+	Beta   float64 `json:"beta,omitempty" yaml:"beta,omitempty"`
+}
+
+type optionDoc struct {
+	Name   string            `json:"name" yaml:"name"`
+	Fields map[string]string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Pros   []*qualityDoc     `json:"pros,omitempty" yaml:"pros,omitempty"`
+	Cons   []*qualityDoc     `json:"cons,omitempty" yaml:"cons,omitempty"`
+}
+
+type qualityDoc struct {
+	Name            string           `json:"name" yaml:"name"`
+	Importance      float64          `json:"importance" yaml:"importance"`
+	Probability     float64          `json:"probability" yaml:"probability"`
+	ImportanceDist  *distributionDoc `json:"importanceDist,omitempty" yaml:"importanceDist,omitempty"`
+	ProbabilityDist *distributionDoc `json:"probabilityDist,omitempty" yaml:"probabilityDist,omitempty"`
+}
+
+// csvColumn a CSV column with a quality or an option field
+type csvColumn struct {
+	kind string // kind quality kind or csvFieldPrefix
+	name string
+}
+
+func (c csvColumn) String() string {
+	return c.kind + ":" + c.name
+}
+
+type problemCodecImpl struct{}
+
+func NewProblemCodec() domain.ProblemCodec {
+	return &problemCodecImpl{}
+}
+
+func (c *problemCodecImpl) Format(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return domain.ExchangeFormatJson
+	case ".yaml", ".yml":
+		return domain.ExchangeFormatYaml
+	case ".csv":
+		return domain.ExchangeFormatCsv
+	}
+	return ""
+}
+
+func (c *problemCodecImpl) ContentType(format string) string {
+	switch format {
+	case domain.ExchangeFormatJson:
+		return "application/json"
+	case domain.ExchangeFormatYaml:
+		return "application/yaml"
+	case domain.ExchangeFormatCsv:
+		return "text/csv"
+	}
+	return ""
+}
+
+func (c *problemCodecImpl) Encode(ctx context.Context, problem *domain.Problem, format string) ([]byte, error) {
+	if problem == nil {
+		return nil, errors.ErrDecisionProblemEmpty(ctx)
+	}
+	switch format {
+	case domain.ExchangeFormatJson:
+		r, err := json.MarshalIndent(c.toDocument(problem), "", "  ")
+		if err != nil {
+			return nil, errors.ErrDecisionExportEncode(ctx, err)
+		}
+		return r, nil
+	case domain.ExchangeFormatYaml:
+		r, err := yaml.Marshal(c.toDocument(problem))
+		if err != nil {
+			return nil, errors.ErrDecisionExportEncode(ctx, err)
+		}
+		return r, nil
+	case domain.ExchangeFormatCsv:
+		return c.encodeCsv(ctx, problem)
+	}
+	return nil, errors.ErrDecisionExchangeFormatInvalid(ctx, format)
+}
+
+func (c *problemCodecImpl) Decode(ctx context.Context, format, name string, content io.Reader) (*domain.Problem, error) {
+	switch format {
+	case domain.ExchangeFormatJson:
+		doc := &problemDocument{}
+		decoder := json.NewDecoder(content)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(doc); err != nil {
+			return nil, errors.ErrDecisionImportParse(ctx, err)
+		}
+		return c.toProblemFromDocument(ctx, doc, name)
+	case domain.ExchangeFormatYaml:
+		doc := &problemDocument{}
+		decoder := yaml.NewDecoder(content)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(doc); err != nil {
+			return nil, errors.ErrDecisionImportParse(ctx, err)
+		}
+		return c.toProblemFromDocument(ctx, doc, name)
+	case domain.ExchangeFormatCsv:
+		return c.decodeCsv(ctx, name, content)
+	}
+	return nil, errors.ErrDecisionExchangeFormatInvalid(ctx, format)
+}
+
+func (c *problemCodecImpl) toDocument(p *domain.Problem) *problemDocument {
+	doc := &problemDoc{
+		Name:    p.Name,
+		Method:  p.Method,
+		Options: make([]*optionDoc, 0, len(p.Options)),
+	}
+	if p.ProsCons != nil {
+		doc.ProsCons = &prosConsDoc{Scoring: p.ProsCons.Scoring, Smoothing: p.ProsCons.Smoothing}
+	}
+	if p.Ahp != nil {
+		doc.Ahp = &ahpDoc{Criteria: p.Ahp.Criteria, CriteriaComparisons: p.Ahp.CriteriaComparisons, OptionComparisons: p.Ahp.OptionComparisons}
+	}
+	if p.Topsis != nil {
+		doc.Topsis = &topsisDoc{Scores: p.Topsis.Scores}
+		for _, cr := range p.Topsis.Criteria {
+			doc.Topsis.Criteria = append(doc.Topsis.Criteria, &topsisCriterionDoc{Name: cr.Name, Weight: cr.Weight, Direction: cr.Direction})
+		}
+	}
+	if p.Simulation != nil {
+		doc.Simulation = &simulationDoc{Iterations: p.Simulation.Iterations, Seed: p.Simulation.Seed}
+	}
+	for _, op := range p.Options {
+		doc.Options = append(doc.Options, &optionDoc{
+			Name:   op.Name,
+			Fields: op.Fields,
+			Pros:   c.toQualitiesDoc(op.Pros),
+			Cons:   c.toQualitiesDoc(op.Cons),
+		})
+	}
+	return &problemDocument{Version: domain.ExchangeDocumentVersion, Problem: doc}
+}
+
+func (c *problemCodecImpl) toQualitiesDoc(qualities []*domain.Quality) []*qualityDoc {
+	var r []*qualityDoc
+	for _, q := range qualities {
+		r = append(r, &qualityDoc{
+			Name:            q.Name,
+			Importance:      q.Importance,
+			Probability:     q.Probability,
+			ImportanceDist:  c.toDistributionDoc(q.ImportanceDist),
+			ProbabilityDist: c.toDistributionDoc(q.ProbabilityDist),
+		})
+	}
+	return r
+}
+
+func (c *problemCodecImpl) toDistributionDoc(d *domain.Distribution) *distributionDoc {
+	if d == nil {
+		return nil
+	}
+	return &distributionDoc{Type: d.Type, Min: d.Min, Max: d.Max, Mode: d.Mode, Mean: d.Mean, StdDev: d.StdDev, Alpha: d.Alpha, Beta: d.Beta}
+}
+
+func (c *problemCodecImpl) toDistributionDomain(d *distributionDoc) *domain.Distribution {
+	if d == nil {
+		return nil
+	}
+	return &domain.Distribution{Type: d.Type, Min: d.Min, Max: d.Max, Mode: d.Mode, Mean: d.Mean, StdDev: d.StdDev, Alpha: d.Alpha, Beta: d.Beta}
+}
+
+// toProblemFromDocument checks the document and converts it to a problem
+// shapes and values of method specific params are checked, consistency of AHP comparisons is checked when a decision is made
+func (c *problemCodecImpl) toProblemFromDocument(ctx context.Context, doc *problemDocument, name string) (*domain.Problem, error) {
+	if doc.Version < 1 || doc.Version > domain.ExchangeDocumentVersion {
+		return nil, errors.ErrDecisionImportVersionUnsupported(ctx, doc.Version)
+	}
+	if doc.Problem == nil {
+		return nil, errors.ErrDecisionImportValueInvalid(ctx, "problem")
+	}
+	d := doc.Problem
+	r := &domain.Problem{
+		Name:   d.Name,
+		Method: d.Method,
+	}
+	if r.Name == "" {
+		r.Name = name
+	}
+	if r.Name == "" {
+		return nil, errors.ErrDecisionImportValueInvalid(ctx, "problem.name")
+	}
+	if d.ProsCons != nil {
+		r.ProsCons = &domain.ProsCons{Scoring: d.ProsCons.Scoring, Smoothing: d.ProsCons.Smoothing}
+	}
+	if d.Simulation != nil {
+		r.Simulation = &domain.Simulation{Iterations: d.Simulation.Iterations, Seed: d.Simulation.Seed}
+	}
+	for i, op := range d.Options {
+		path := fmt.Sprintf("problem.options[%d]", i)
+		if op == nil {
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, path)
+		}
+		if op.Name == "" {
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, path+".name")
+		}
+		o := &domain.Option{Name: op.Name, Fields: op.Fields}
+		var err error
+		if o.Pros, err = c.toQualitiesFromDocument(ctx, path+".pros", op.Pros); err != nil {
+			return nil, err
+		}
+		if o.Cons, err = c.toQualitiesFromDocument(ctx, path+".cons", op.Cons); err != nil {
+			return nil, err
+		}
+		r.Options = append(r.Options, o)
+	}
+	if d.Ahp != nil {
+		if err := c.validateAhpDocument(ctx, d.Ahp, len(d.Options)); err != nil {
+			return nil, err
+		}
+		r.Ahp = &domain.Ahp{Criteria: d.Ahp.Criteria, CriteriaComparisons: d.Ahp.CriteriaComparisons, OptionComparisons: d.Ahp.OptionComparisons}
+	}
+	if d.Topsis != nil {
+		var err error
+		if r.Topsis, err = c.toTopsisFromDocument(ctx, d.Topsis, len(d.Options)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// validateAhpDocument checks comparisons are square positive reciprocal matrices of criteria and of options for each criterion
+func (c *problemCodecImpl) validateAhpDocument(ctx context.Context, a *ahpDoc, options int) error {
+	if ahpValidateMatrix(ctx, "criteria", a.CriteriaComparisons) != nil {
+		return errors.ErrDecisionImportValueInvalid(ctx, "problem.ahp.criteriaComparisons")
+	}
+	if len(a.Criteria) > 0 && len(a.Criteria) != len(a.CriteriaComparisons) {
+		return errors.ErrDecisionImportValueInvalid(ctx, "problem.ahp.criteria")
+	}
+	if len(a.OptionComparisons) != len(a.CriteriaComparisons) {
+		return errors.ErrDecisionImportValueInvalid(ctx, "problem.ahp.optionComparisons")
+	}
+	for i, m := range a.OptionComparisons {
+		if len(m) != options || ahpValidateMatrix(ctx, "options", m) != nil {
+			return errors.ErrDecisionImportValueInvalid(ctx, fmt.Sprintf("problem.ahp.optionComparisons[%d]", i))
+		}
+	}
+	return nil
+}
+
+// toTopsisFromDocument checks criteria and scores of each option by each criterion
+func (c *problemCodecImpl) toTopsisFromDocument(ctx context.Context, t *topsisDoc, options int) (*domain.Topsis, error) {
+	r := &domain.Topsis{Scores: t.Scores}
+	weights := 0.0
+	for i, cr := range t.Criteria {
+		path := fmt.Sprintf("problem.topsis.criteria[%d]", i)
+		switch {
+		case cr == nil:
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, path)
+		case !validNonNegative(cr.Weight):
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, path+".weight")
+		case cr.Direction != "" && cr.Direction != domain.TopsisBenefit && cr.Direction != domain.TopsisCost:
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, path+".direction")
+		}
+		weights += cr.Weight
+		r.Criteria = append(r.Criteria, &domain.TopsisCriterion{Name: cr.Name, Weight: cr.Weight, Direction: cr.Direction})
+	}
+	if weights == 0 {
+		return nil, errors.ErrDecisionImportValueInvalid(ctx, "problem.topsis.criteria")
+	}
+	if len(t.Scores) != options {
+		return nil, errors.ErrDecisionImportValueInvalid(ctx, "problem.topsis.scores")
+	}
+	for i, row := range t.Scores {
+		path := fmt.Sprintf("problem.topsis.scores[%d]", i)
+		if len(row) != len(t.Criteria) {
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, path)
+		}
+		for j, v := range row {
+			if !validNonNegative(v) {
+				return nil, errors.ErrDecisionImportValueInvalid(ctx, fmt.Sprintf("%s[%d]", path, j))
+			}
+		}
+	}
+	return r, nil
+}
+
+func (c *problemCodecImpl) toQualitiesFromDocument(ctx context.Context, path string, qualities []*qualityDoc) ([]*domain.Quality, error) {
+	var r []*domain.Quality
+	for i, q := range qualities {
+		qPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case q == nil:
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, qPath)
+		case q.Name == "":
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, qPath+".name")
+		case !validNonNegative(q.Importance):
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, qPath+".importance")
+		case !validProbability(q.Probability):
+			return nil, errors.ErrDecisionImportValueInvalid(ctx, qPath+".probability")
+		}
+		r = append(r, &domain.Quality{
+			Name:            q.Name,
+			Importance:      q.Importance,
+			Probability:     q.Probability,
+			ImportanceDist:  c.toDistributionDomain(q.ImportanceDist),
+			ProbabilityDist: c.toDistributionDomain(q.ProbabilityDist),
+		})
+	}
+	return r, nil
+}
+
+// encodeCsv writes a header with option column followed by quality and field columns in order of appearance
+func (c *problemCodecImpl) encodeCsv(ctx context.Context, problem *domain.Problem) ([]byte, error) {
+	var columns []csvColumn
+	index := map[csvColumn]int{}
+	addColumn := func(col csvColumn) {
+		if _, ok := index[col]; !ok {
+			index[col] = len(columns) + 1
+			columns = append(columns, col)
+		}
+	}
+	for _, op := range problem.Options {
+		for _, q := range op.Pros {
+			addColumn(csvColumn{kind: domain.QualityKindPro, name: q.Name})
+		}
+		for _, q := range op.Cons {
+			addColumn(csvColumn{kind: domain.QualityKindCon, name: q.Name})
+		}
+	}
+	for _, op := range problem.Options {
+		fields := make([]string, 0, len(op.Fields))
+		for f := range op.Fields {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		for _, f := range fields {
+			addColumn(csvColumn{kind: csvFieldPrefix, name: f})
+		}
+	}
+
+	header := make([]string, 0, len(columns)+1)
+	header = append(header, csvOptionColumn)
+	for _, col := range columns {
+		header = append(header, col.String())
+	}
+	rows := [][]string{header}
+
+	for _, op := range problem.Options {
+		row := make([]string, len(header))
+		row[0] = op.Name
+		for _, kq := range []struct {
+			kind      string
+			qualities []*domain.Quality
+		}{{domain.QualityKindPro, op.Pros}, {domain.QualityKindCon, op.Cons}} {
+			for _, q := range kq.qualities {
+				i := index[csvColumn{kind: kq.kind, name: q.Name}]
+				if row[i] != "" {
+					return nil, errors.ErrDecisionExportCsvQualityDuplicate(ctx, op.Name, q.Name)
+				}
+				row[i] = formatCsvQuality(q)
+			}
+		}
+		for f, v := range op.Fields {
+			row[index[csvColumn{kind: csvFieldPrefix, name: f}]] = v
+		}
+		rows = append(rows, row)
+	}
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, errors.ErrDecisionExportEncode(ctx, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeCsv reads options×qualities matrix, rows and columns in errors are numbered from 1, the header is row 1
+func (c *problemCodecImpl) decodeCsv(ctx context.Context, name string, content io.Reader) (*domain.Problem, error) {
+	if name == "" {
+		return nil, errors.ErrDecisionProblemNameEmpty(ctx)
+	}
+
+	reader := csv.NewReader(content)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.ErrDecisionImportParse(ctx, err)
+	}
+	if len(records) == 0 {
+		return nil, errors.ErrDecisionImportCsvEmpty(ctx)
+	}
+
+	// header, spreadsheets may prepend BOM
+	header := records[0]
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	if !strings.EqualFold(strings.TrimSpace(header[0]), csvOptionColumn) {
+		return nil, errors.ErrDecisionImportCsvHeaderInvalid(ctx, 1, header[0])
+	}
+	columns := make([]csvColumn, len(header))
+	seen := map[csvColumn]struct{}{}
+	for i := 1; i < len(header); i++ {
+		kind, colName, ok := strings.Cut(strings.TrimSpace(header[i]), ":")
+		col := csvColumn{kind: strings.ToLower(strings.TrimSpace(kind)), name: strings.TrimSpace(colName)}
+		if _, dup := seen[col]; dup || !ok || col.name == "" ||
+			(col.kind != domain.QualityKindPro && col.kind != domain.QualityKindCon && col.kind != csvFieldPrefix) {
+			return nil, errors.ErrDecisionImportCsvHeaderInvalid(ctx, i+1, header[i])
+		}
+		seen[col] = struct{}{}
+		columns[i] = col
+	}
+
+	r := &domain.Problem{Name: name}
+	for rowIdx, record := range records[1:] {
+		row := rowIdx + 2
+		op := &domain.Option{Name: strings.TrimSpace(record[0])}
+		if op.Name == "" {
+			return nil, errors.ErrDecisionImportCsvCellInvalid(ctx, row, 1, record[0])
+		}
+		for i := 1; i < len(record); i++ {
+			value := strings.TrimSpace(record[i])
+			if value == "" {
+				continue
+			}
+			col := columns[i]
+			if col.kind == csvFieldPrefix {
+				if op.Fields == nil {
+					op.Fields = map[string]string{}
+				}
+				op.Fields[col.name] = value
+				continue
+			}
+			q, ok := parseCsvQuality(col.name, value)
+			if !ok {
+				return nil, errors.ErrDecisionImportCsvCellInvalid(ctx, row, i+1, record[i])
+			}
+			if col.kind == domain.QualityKindPro {
+				op.Pros = append(op.Pros, q)
+			} else {
+				op.Cons = append(op.Cons, q)
+			}
+		}
+		r.Options = append(r.Options, op)
+	}
+	return r, nil
+}
+
+// formatCsvQuality formats a quality as importance[@probability], probability is omitted if equals 1
+func formatCsvQuality(q *domain.Quality) string {
+	r := strconv.FormatFloat(q.Importance, 'f', -1, 64)
+	if q.Probability != 1 {
+		r += csvProbabilitySeparator + strconv.FormatFloat(q.Probability, 'f', -1, 64)
+	}
+	return r
+}
+
+func parseCsvQuality(name, value string) (*domain.Quality, bool) {
+	imp, prob, hasProb := strings.Cut(value, csvProbabilitySeparator)
+	q := &domain.Quality{Name: name, Probability: 1}
+	var err error
+	if q.Importance, err = strconv.ParseFloat(strings.TrimSpace(imp), 64); err != nil || !validNonNegative(q.Importance) {
+		return nil, false
+	}
+	if hasProb {
+		if q.Probability, err = strconv.ParseFloat(strings.TrimSpace(prob), 64); err != nil || !validProbability(q.Probability) {
+			return nil, false
+		}
+	}
+	return q, true
+}
+
+func validNonNegative(v float64) bool {
+	return v >= 0 && !math.IsInf(v, 0) && !math.IsNaN(v)
+}
+
+func validProbability(v float64) bool {
+	return v >= 0 && v <= 1
+}
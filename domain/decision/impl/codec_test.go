@@ -0,0 +1,204 @@
+package impl
+
+import (
+	"bytes"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type codecTestSuite struct {
+	kit.Suite
+	codec domain.ProblemCodec
+}
+
+func (s *codecTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+	s.codec = NewProblemCodec()
+}
+
+func TestCodecSuite(t *testing.T) {
+	suite.Run(t, new(codecTestSuite))
+}
+
+func (s *codecTestSuite) problem() *domain.Problem {
+	return &domain.Problem{
+		Name:       "cars",
+		Method:     domain.MethodProsCons,
+		ProsCons:   &domain.ProsCons{Scoring: domain.ScoringNet, Smoothing: kit.Float64Ptr(0.5)},
+		Simulation: &domain.Simulation{Iterations: 100},
+		Options: []*domain.Option{
+			{
+				Name:   "first",
+				Fields: map[string]string{"vin": "WVWZZZ1JZXW000001"},
+				Pros: []*domain.Quality{
+					{Name: "range", Importance: 8, Probability: 1, ImportanceDist: &domain.Distribution{Type: domain.DistUniform, Min: 7, Max: 9}},
+				},
+				Cons: []*domain.Quality{{Name: "price", Importance: 9, Probability: 0.9}},
+			},
+			{
+				Name: "second",
+				Pros: []*domain.Quality{{Name: "range", Importance: 5, Probability: 1}, {Name: "comfort", Importance: 3, Probability: 0.5}},
+			},
+		},
+	}
+}
+
+func (s *codecTestSuite) Test_Document_RoundTrip() {
+	for _, format := range []string{domain.ExchangeFormatJson, domain.ExchangeFormatYaml} {
+		s.T().Run(format, func(t *testing.T) {
+			p := s.problem()
+			content, err := s.codec.Encode(s.Ctx, p, format)
+			s.NoError(err)
+			s.Contains(string(content), "version")
+			r, err := s.codec.Decode(s.Ctx, format, "", bytes.NewReader(content))
+			s.NoError(err)
+			s.Equal(p, r)
+		})
+	}
+}
+
+func (s *codecTestSuite) Test_Csv_RoundTrip() {
+	p := s.problem()
+	content, err := s.codec.Encode(s.Ctx, p, domain.ExchangeFormatCsv)
+	s.NoError(err)
+	s.Equal("option,pro:range,con:price,pro:comfort,field:vin\nfirst,8,9@0.9,,WVWZZZ1JZXW000001\nsecond,5,,3@0.5,\n", string(content))
+
+	r, err := s.codec.Decode(s.Ctx, domain.ExchangeFormatCsv, "cars", bytes.NewReader(content))
+	s.NoError(err)
+	s.Equal("cars", r.Name)
+	s.Len(r.Options, 2)
+	s.Equal(p.Options[0].Fields, r.Options[0].Fields)
+	s.Equal(p.Options[0].Cons, r.Options[0].Cons)
+	s.Equal(p.Options[1].Pros, r.Options[1].Pros)
+	s.Empty(r.Options[1].Fields)
+	// distributions aren't kept
+	s.Nil(r.Options[0].Pros[0].ImportanceDist)
+}
+
+func (s *codecTestSuite) Test_Csv_Decode_Spreadsheet() {
+	content := "\ufeffOption, Pro: Range ,CON:Price\n first , 8 , 9 @ 0.5\n"
+	r, err := s.codec.Decode(s.Ctx, domain.ExchangeFormatCsv, "cars", strings.NewReader(content))
+	s.NoError(err)
+	s.Equal("first", r.Options[0].Name)
+	s.Equal(&domain.Quality{Name: "Range", Importance: 8, Probability: 1}, r.Options[0].Pros[0])
+	s.Equal(&domain.Quality{Name: "Price", Importance: 9, Probability: 0.5}, r.Options[0].Cons[0])
+}
+
+func (s *codecTestSuite) Test_Csv_Decode_Invalid() {
+	tests := []struct {
+		name    string
+		content string
+		code    string
+		fields  kit.KV
+	}{
+		{"empty", "", errors.ErrCodeDecisionImportCsvEmpty, nil},
+		{"first column", "name,pro:range\n", errors.ErrCodeDecisionImportCsvHeaderInvalid, kit.KV{"column": 1}},
+		{"column kind", "option,pro:range,pros:price\n", errors.ErrCodeDecisionImportCsvHeaderInvalid, kit.KV{"column": 3}},
+		{"column duplicate", "option,pro:range,pro:range\n", errors.ErrCodeDecisionImportCsvHeaderInvalid, kit.KV{"column": 3}},
+		{"option name", "option,pro:range\nfirst,1\n,2\n", errors.ErrCodeDecisionImportCsvCellInvalid, kit.KV{"row": 3, "column": 1}},
+		{"importance", "option,pro:range,con:price\nfirst,1,-2\n", errors.ErrCodeDecisionImportCsvCellInvalid, kit.KV{"row": 2, "column": 3}},
+		{"probability", "option,pro:range\nfirst,1@2\n", errors.ErrCodeDecisionImportCsvCellInvalid, kit.KV{"row": 2, "column": 2}},
+		{"row size", "option,pro:range\nfirst,1,2\n", errors.ErrCodeDecisionImportParse, nil},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			_, err := s.codec.Decode(s.Ctx, domain.ExchangeFormatCsv, "cars", strings.NewReader(tt.content))
+			s.AssertAppErr(err, tt.code)
+			appErr, _ := kit.IsAppErr(err)
+			for k, v := range tt.fields {
+				s.Equal(v, appErr.Fields()[k], k)
+			}
+		})
+	}
+}
+
+// methodDoc returns a YAML document of a problem with two options and the method params
+func (s *codecTestSuite) methodDoc(params string) string {
+	return "version: 1\nproblem:\n  name: cars\n  options:\n    - name: first\n    - name: second\n  " + params + "\n"
+}
+
+func (s *codecTestSuite) Test_Document_Decode_MethodParams() {
+	r, err := s.codec.Decode(s.Ctx, domain.ExchangeFormatYaml, "", strings.NewReader(s.methodDoc(
+		"ahp:\n    criteria: [price, range]\n    criteriaComparisons: [[1, 2], [0.5, 1]]\n    optionComparisons: [[[1, 3], [0.33, 1]], [[1, 0.5], [2, 1]]]\n"+
+			"  topsis:\n    criteria: [{weight: 1, direction: cost}]\n    scores: [[1], [3]]")))
+	s.NoError(err)
+	s.Equal([]string{"price", "range"}, r.Ahp.Criteria)
+	s.Len(r.Ahp.OptionComparisons, 2)
+	s.Equal(domain.TopsisCost, r.Topsis.Criteria[0].Direction)
+	s.Equal([][]float64{{1}, {3}}, r.Topsis.Scores)
+}
+
+func (s *codecTestSuite) Test_Document_Decode_Invalid() {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		code    string
+		path    string
+	}{
+		{"syntax", domain.ExchangeFormatJson, `{"version":1,`, errors.ErrCodeDecisionImportParse, ""},
+		{"unknown field", domain.ExchangeFormatYaml, "version: 1\nproblem:\n  title: cars\n", errors.ErrCodeDecisionImportParse, ""},
+		{"no version", domain.ExchangeFormatJson, `{"problem":{"name":"cars"}}`, errors.ErrCodeDecisionImportVersionUnsupported, ""},
+		{"future version", domain.ExchangeFormatYaml, "version: 2\n", errors.ErrCodeDecisionImportVersionUnsupported, ""},
+		{"option name", domain.ExchangeFormatYaml, "version: 1\nproblem:\n  name: cars\n  options:\n    - name: first\n    - pros: []\n",
+			errors.ErrCodeDecisionImportValueInvalid, "problem.options[1].name"},
+		{"probability", domain.ExchangeFormatJson, `{"version":1,"problem":{"name":"cars","options":[{"name":"first","cons":[{"name":"price","importance":1,"probability":2}]}]}}`,
+			errors.ErrCodeDecisionImportValueInvalid, "problem.options[0].cons[0].probability"},
+		{"ahp not reciprocal", domain.ExchangeFormatYaml, s.methodDoc("ahp:\n    criteriaComparisons: [[1, 2], [2, 1]]\n    optionComparisons: [[[1, 3], [0.33, 1]], [[1, 3], [0.33, 1]]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.ahp.criteriaComparisons"},
+		{"ahp criteria", domain.ExchangeFormatYaml, s.methodDoc("ahp:\n    criteria: [price]\n    criteriaComparisons: [[1, 2], [0.5, 1]]\n    optionComparisons: [[[1, 3], [0.33, 1]], [[1, 3], [0.33, 1]]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.ahp.criteria"},
+		{"ahp matrix per criterion", domain.ExchangeFormatYaml, s.methodDoc("ahp:\n    criteriaComparisons: [[1, 2], [0.5, 1]]\n    optionComparisons: [[[1, 3], [0.33, 1]]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.ahp.optionComparisons"},
+		{"ahp options size", domain.ExchangeFormatYaml, s.methodDoc("ahp:\n    criteriaComparisons: [[1, 2], [0.5, 1]]\n    optionComparisons: [[[1, 3], [0.33, 1]], [[1]]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.ahp.optionComparisons[1]"},
+		{"topsis weight", domain.ExchangeFormatYaml, s.methodDoc("topsis:\n    criteria: [{weight: 1}, {weight: -1}]\n    scores: [[1, 2], [3, 4]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.topsis.criteria[1].weight"},
+		{"topsis direction", domain.ExchangeFormatYaml, s.methodDoc("topsis:\n    criteria: [{weight: 1, direction: less}]\n    scores: [[1], [3]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.topsis.criteria[0].direction"},
+		{"topsis zero weights", domain.ExchangeFormatYaml, s.methodDoc("topsis:\n    criteria: [{weight: 0}]\n    scores: [[1], [3]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.topsis.criteria"},
+		{"topsis scores per option", domain.ExchangeFormatYaml, s.methodDoc("topsis:\n    criteria: [{weight: 1}]\n    scores: [[1]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.topsis.scores"},
+		{"topsis scores per criterion", domain.ExchangeFormatYaml, s.methodDoc("topsis:\n    criteria: [{weight: 1}]\n    scores: [[1], [3, 4]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.topsis.scores[1]"},
+		{"topsis negative score", domain.ExchangeFormatYaml, s.methodDoc("topsis:\n    criteria: [{weight: 1}, {weight: 1}]\n    scores: [[1, 2], [3, -4]]"),
+			errors.ErrCodeDecisionImportValueInvalid, "problem.topsis.scores[1][1]"},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			_, err := s.codec.Decode(s.Ctx, tt.format, "", strings.NewReader(tt.content))
+			s.AssertAppErr(err, tt.code)
+			if tt.path != "" {
+				appErr, _ := kit.IsAppErr(err)
+				s.Equal(tt.path, appErr.Fields()["path"])
+			}
+		})
+	}
+}
+
+func (s *codecTestSuite) Test_Csv_Encode_QualityDuplicate() {
+	p := s.problem()
+	p.Options[1].Pros[1].Name = "range"
+	_, err := s.codec.Encode(s.Ctx, p, domain.ExchangeFormatCsv)
+	s.AssertAppErr(err, errors.ErrCodeDecisionExportCsvQualityDuplicate)
+}
+
+func (s *codecTestSuite) Test_FormatInvalid() {
+	_, err := s.codec.Encode(s.Ctx, s.problem(), "xml")
+	s.AssertAppErr(err, errors.ErrCodeDecisionExchangeFormatInvalid)
+	_, err = s.codec.Decode(s.Ctx, "xml", "", strings.NewReader(""))
+	s.AssertAppErr(err, errors.ErrCodeDecisionExchangeFormatInvalid)
+}
+
+func (s *codecTestSuite) Test_Format() {
+	s.Equal(domain.ExchangeFormatJson, s.codec.Format("problem.JSON"))
+	s.Equal(domain.ExchangeFormatYaml, s.codec.Format("/tmp/problem.yml"))
+	s.Equal(domain.ExchangeFormatCsv, s.codec.Format("matrix.csv"))
+	s.Empty(s.codec.Format("matrix.xlsx"))
+}
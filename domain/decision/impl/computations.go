@@ -0,0 +1,71 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/errors"
+	"sync"
+)
+
+// computations keeps track of running computations, so that they can be waited for or cancelled on close
+type computations struct {
+	sync.Mutex
+	wg      sync.WaitGroup
+	closing bool
+	nextId  int
+	cancels map[int]context.CancelFunc
+}
+
+func newComputations() *computations {
+	return &computations{cancels: map[int]context.CancelFunc{}}
+}
+
+// start registers a computation, the returned context is cancelled if close deadline passes
+// done must be called when the computation is finished
+func (c *computations) start(ctx context.Context) (context.Context, func(), error) {
+	c.Lock()
+	defer c.Unlock()
+	if c.closing {
+		return nil, nil, errors.ErrDecisionServiceClosed(ctx)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	id := c.nextId
+	c.nextId++
+	c.cancels[id] = cancel
+	c.wg.Add(1)
+	done := func() {
+		c.Lock()
+		delete(c.cancels, id)
+		c.Unlock()
+		cancel()
+		c.wg.Done()
+	}
+	return ctx, done, nil
+}
+
+// close rejects new computations and waits for the running ones
+// when ctx is done, the running computations are cancelled and waited for once again
+func (c *computations) close(ctx context.Context) error {
+	c.Lock()
+	c.closing = true
+	c.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.Lock()
+	for _, cancel := range c.cancels {
+		cancel()
+	}
+	c.Unlock()
+	<-finished
+	return nil
+}
@@ -0,0 +1,61 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type computationsTestSuite struct {
+	kit.Suite
+}
+
+func (s *computationsTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func TestComputationsSuite(t *testing.T) {
+	suite.Run(t, new(computationsTestSuite))
+}
+
+func (s *computationsTestSuite) Test_Close_WaitsRunning() {
+	c := newComputations()
+	_, done, err := c.start(s.Ctx)
+	s.NoError(err)
+
+	finished := make(chan struct{})
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		close(finished)
+		done()
+	}()
+	s.NoError(c.close(s.Ctx))
+	select {
+	case <-finished:
+	default:
+		s.Fail("close returned before computation finished")
+	}
+
+	// new computations are rejected
+	_, _, err = c.start(s.Ctx)
+	s.AssertAppErr(err, errors.ErrCodeDecisionServiceClosed)
+}
+
+func (s *computationsTestSuite) Test_Close_CancelsOnDeadline() {
+	c := newComputations()
+	ctx, done, err := c.start(s.Ctx)
+	s.NoError(err)
+	go func() {
+		<-ctx.Done()
+		done()
+	}()
+
+	closeCtx, cancel := context.WithTimeout(s.Ctx, time.Millisecond*50)
+	defer cancel()
+	s.NoError(c.close(closeCtx))
+	s.Error(ctx.Err())
+}
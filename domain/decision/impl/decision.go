@@ -2,37 +2,340 @@ package impl
 
 import (
 	"context"
+	"github.com/mikhailbolshakov/decision"
 	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
 	"github.com/mikhailbolshakov/decision/kit"
+	"reflect"
+	"time"
 )
 
 type decisionServiceImpl struct {
+	methodRegistry  domain.MethodRegistry
+	problemStorage  domain.ProblemStorage
+	decisionStorage domain.DecisionStorage
+	computations    *computations
 }
 
-func NewDecisionService() domain.DecisionService {
-	return &decisionServiceImpl{}
+func NewDecisionService(methodRegistry domain.MethodRegistry, problemStorage domain.ProblemStorage, decisionStorage domain.DecisionStorage) domain.DecisionService {
+	return &tracedDecisionService{
+		svc: &decisionServiceImpl{
+			methodRegistry:  methodRegistry,
+			problemStorage:  problemStorage,
+			decisionStorage: decisionStorage,
+			computations:    newComputations(),
+		},
+	}
+}
+
+func (p *decisionServiceImpl) l() kit.CLogger {
+	return decision.L().Cmp("decision-svc")
 }
 
 func (p *decisionServiceImpl) MakeDecision(ctx context.Context, userId string, problem *domain.Problem) (*domain.Decision, error) {
+	l := p.l().C(ctx).Mth("make-decision")
+
+	if problem == nil {
+		return nil, errors.ErrDecisionProblemEmpty(ctx)
+	}
+	if err := validateOptions(ctx, problem); err != nil {
+		return nil, err
+	}
+
+	ctx, done, err := p.computations.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	// guest decisions aren't stored, so ids are needed only to identify options in the result
+	if userId == "" {
+		setIds(problem)
+		return calculate(ctx, p.methodRegistry, problem, userId)
+	}
+
+	if err := validateMethod(ctx, p.methodRegistry, problem); err != nil {
+		return nil, err
+	}
+
+	stored, err := p.prepareProblem(ctx, userId, problem)
+	if err != nil {
+		return nil, err
+	}
+
+	// calculation validates the problem, so an invalid problem isn't stored
+	r, err := calculate(ctx, p.methodRegistry, problem, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// store problem
+	if err := p.storeProblem(ctx, problem, stored); err != nil {
+		return nil, err
+	}
+	r.ProblemVersion = problem.Version
+	r.CreatedAt = kit.Now()
+
+	// store decision
+	if err = p.decisionStorage.CreateDecision(ctx, r); err != nil {
+		return nil, err
+	}
+
+	l.F(kit.KV{"problemId": problem.Id, "decisionId": r.Id}).Dbg("stored")
+
+	return r, nil
+}
+
+func (p *decisionServiceImpl) MakeDecisionByProblem(ctx context.Context, userId, problemId string) (*domain.Decision, error) {
+	l := p.l().C(ctx).Mth("make-decision-problem")
+
+	ctx, done, err := p.computations.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 
-	r := &domain.Decision{
-		Id:        kit.NewRandString(),
-		ProblemId: problem.Id,
-		UserId:    userId,
+	problem, err := getUserProblem(ctx, p.problemStorage, userId, problemId)
+	if err != nil {
+		return nil, err
 	}
 
+	r, err := calculate(ctx, p.methodRegistry, problem, userId)
+	if err != nil {
+		return nil, err
+	}
+	r.CreatedAt = kit.Now()
+
+	// store decision
+	if err = p.decisionStorage.CreateDecision(ctx, r); err != nil {
+		return nil, err
+	}
+
+	l.F(kit.KV{"problemId": problem.Id, "decisionId": r.Id}).Dbg("stored")
+
+	return r, nil
+}
+
+func (p *decisionServiceImpl) GetDecision(ctx context.Context, decisionId string) (*domain.Decision, error) {
+	p.l().C(ctx).Mth("get-decision").Dbg()
+	r, err := p.decisionStorage.GetDecision(ctx, decisionId)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, errors.ErrDecisionNotFound(ctx, decisionId)
+	}
+	return r, nil
+}
+
+func (p *decisionServiceImpl) Close(ctx context.Context) error {
+	p.l().C(ctx).Mth("close").Dbg()
+	return p.computations.close(ctx)
+}
+
+func (p *decisionServiceImpl) GetMethods(ctx context.Context) []*domain.MethodDescription {
+	p.l().C(ctx).Mth("get-methods").Dbg()
+	methods := p.methodRegistry.List(ctx)
+	r := make([]*domain.MethodDescription, 0, len(methods))
+	for _, m := range methods {
+		r = append(r, m.Describe())
+	}
+	return r
+}
+
+// calculate rates options of the problem by the problem's method
+func calculate(ctx context.Context, methodRegistry domain.MethodRegistry, problem *domain.Problem, userId string) (*domain.Decision, error) {
+
+	method, err := methodRegistry.Get(ctx, problem.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := method.Calculate(ctx, problem)
+	if err == nil && problem.Simulation != nil {
+		res.Simulation, err = simulate(ctx, method, problem)
+	}
+	observeComputation(method.Code(), start, err)
+	if err != nil {
+		return nil, err
+	}
+	// qualities are weighted by importance and probability by pros-cons method only
+	res.Explanation = explain(ctx, problem, res, method.Code() == domain.MethodProsCons)
+
+	return &domain.Decision{
+		Id:             kit.NewId(),
+		ProblemId:      problem.Id,
+		ProblemVersion: problem.Version,
+		UserId:         userId,
+		Result:         res,
+	}, nil
+}
+
+// prepareProblem checks the problem can be stored by the user and sets ids
+// returns the already stored problem, nil if the problem is new
+func (p *decisionServiceImpl) prepareProblem(ctx context.Context, userId string, problem *domain.Problem) (*domain.Problem, error) {
+
+	if err := validateIds(ctx, problem); err != nil {
+		return nil, err
+	}
+
+	// check if the problem is already stored
+	var stored *domain.Problem
+	if problem.Id != "" {
+		var err error
+		stored, err = p.problemStorage.GetProblem(ctx, problem.Id)
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil && stored.UserId != userId {
+			return nil, errors.ErrDecisionProblemForbidden(ctx, problem.Id)
+		}
+		// a deleted problem can't be recreated with the same id
+		if stored == nil {
+			exists, err := p.problemStorage.ProblemExists(ctx, problem.Id)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, errors.ErrDecisionProblemNotFound(ctx, problem.Id)
+			}
+		}
+	}
+
+	if err := p.validateChildIds(ctx, problem); err != nil {
+		return nil, err
+	}
+
+	setIds(problem)
+	problem.UserId = userId
+
+	return stored, nil
+}
+
+// validateChildIds checks specified ids of options and qualities don't belong to other problems
+func (p *decisionServiceImpl) validateChildIds(ctx context.Context, problem *domain.Problem) error {
+	var optionIds, qualityIds []string
 	for _, op := range problem.Options {
-		//
-		kCon, kPro := 0.0, 0.0
-		for _, con := range op.Cons {
-			kCon += con.Importance * con.Probability
+		if op.Id != "" {
+			optionIds = append(optionIds, op.Id)
 		}
-		//
-		for _, pro := range op.Pros {
-			kPro += pro.Importance * pro.Probability
+		for _, q := range qualities(op) {
+			if q.Id != "" {
+				qualityIds = append(qualityIds, q.Id)
+			}
 		}
-		r.Result.OptionsRating[op.Id] = kit.Round100(kPro / kCon)
 	}
+	if len(optionIds) == 0 && len(qualityIds) == 0 {
+		return nil
+	}
+	foreignIds, err := p.problemStorage.GetForeignIds(ctx, problem.Id, optionIds, qualityIds)
+	if err != nil {
+		return err
+	}
+	if len(foreignIds) > 0 {
+		return errors.ErrDecisionProblemForeignIds(ctx, foreignIds)
+	}
+	return nil
+}
 
-	return r, nil
+// storeProblem creates a new problem or updates the stored one
+// the stored problem isn't updated if nothing changed, so that re-running a decision doesn't create a new version
+func (p *decisionServiceImpl) storeProblem(ctx context.Context, problem, stored *domain.Problem) error {
+
+	if stored == nil {
+		problem.CreatedAt, problem.UpdatedAt = kit.Now(), kit.Now()
+		return p.problemStorage.CreateProblem(ctx, problem)
+	}
+
+	problem.CreatedAt = stored.CreatedAt
+	if sameProblem(problem, stored) {
+		problem.UpdatedAt, problem.Version = stored.UpdatedAt, stored.Version
+		return nil
+	}
+
+	problem.UpdatedAt = kit.Now()
+	return p.problemStorage.UpdateProblem(ctx, problem)
+}
+
+// sameProblem checks if problems have the same content, empty and nil values are considered equal
+func sameProblem(a, b *domain.Problem) bool {
+	if a.Name != b.Name || a.Method != b.Method || len(a.Options) != len(b.Options) ||
+		!sameValues(a.ProsCons, b.ProsCons) || !sameValues(a.Simulation, b.Simulation) {
+		return false
+	}
+	if (a.Ahp == nil) != (b.Ahp == nil) || (a.Topsis == nil) != (b.Topsis == nil) {
+		return false
+	}
+	if a.Ahp != nil && !(sameValues(a.Ahp.Criteria, b.Ahp.Criteria) &&
+		sameValues(a.Ahp.CriteriaComparisons, b.Ahp.CriteriaComparisons) &&
+		sameValues(a.Ahp.OptionComparisons, b.Ahp.OptionComparisons)) {
+		return false
+	}
+	if a.Topsis != nil && !(sameValues(a.Topsis.Criteria, b.Topsis.Criteria) && sameValues(a.Topsis.Scores, b.Topsis.Scores)) {
+		return false
+	}
+	for i := range a.Options {
+		opA, opB := a.Options[i], b.Options[i]
+		if opA.Id != opB.Id || opA.Name != opB.Name || !sameValues(opA.Fields, opB.Fields) ||
+			!sameValues(opA.Pros, opB.Pros) || !sameValues(opA.Cons, opB.Cons) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameValues deeply compares values considering empty slices and maps equal to nil
+func sameValues(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() == reflect.Slice || va.Kind() == reflect.Map {
+		if va.Len() == 0 && vb.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// setIds generates ids for the problem, options and qualities if not specified
+func setIds(problem *domain.Problem) {
+	if problem.Id == "" {
+		problem.Id = kit.NewId()
+	}
+	for _, op := range problem.Options {
+		if op.Id == "" {
+			op.Id = kit.NewId()
+		}
+		for _, q := range qualities(op) {
+			if q.Id == "" {
+				q.Id = kit.NewId()
+			}
+		}
+	}
+}
+
+// validateIds checks all specified ids are valid UUIDs, so that they can be stored
+func validateIds(ctx context.Context, problem *domain.Problem) error {
+	ids := []string{problem.Id}
+	for _, op := range problem.Options {
+		ids = append(ids, op.Id)
+		for _, q := range qualities(op) {
+			ids = append(ids, q.Id)
+		}
+	}
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if err := kit.ValidateUUIDs(id); err != nil {
+			return errors.ErrDecisionProblemInvalidId(ctx, id)
+		}
+	}
+	return nil
+}
+
+// qualities returns both pros and cons of the option
+func qualities(op *domain.Option) []*domain.Quality {
+	r := make([]*domain.Quality, 0, len(op.Pros)+len(op.Cons))
+	r = append(r, op.Pros...)
+	return append(r, op.Cons...)
 }
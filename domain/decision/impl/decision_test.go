@@ -1 +1,284 @@
 package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type decisionTestSuite struct {
+	kit.Suite
+	problemStorage  *mocks.ProblemStorage
+	decisionStorage *mocks.DecisionStorage
+	svc             domain.DecisionService
+}
+
+func (s *decisionTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *decisionTestSuite) SetupTest() {
+	s.problemStorage = &mocks.ProblemStorage{}
+	s.decisionStorage = &mocks.DecisionStorage{}
+	s.svc = NewDecisionService(builtInRegistry(s.Ctx), s.problemStorage, s.decisionStorage)
+}
+
+func TestDecisionSuite(t *testing.T) {
+	suite.Run(t, new(decisionTestSuite))
+}
+
+func (s *decisionTestSuite) problem() *domain.Problem {
+	return &domain.Problem{
+		Name: "problem",
+		Options: []*domain.Option{
+			{
+				Name: "first",
+				Pros: []*domain.Quality{{Name: "pro", Importance: 10, Probability: 0.5}},
+				Cons: []*domain.Quality{{Name: "con", Importance: 5, Probability: 0.5}},
+			},
+			{
+				Name: "second",
+				Pros: []*domain.Quality{{Name: "pro", Importance: 5, Probability: 0.5}},
+				Cons: []*domain.Quality{{Name: "con", Importance: 10, Probability: 0.5}},
+			},
+		},
+	}
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_Guest_NotStored() {
+	problem := s.problem()
+	r, err := s.svc.MakeDecision(s.Ctx, "", problem)
+	s.NoError(err)
+	s.NotEmpty(r.Id)
+	s.Equal(2.0, r.Result.OptionsRating[problem.Options[0].Id])
+	s.Equal(0.5, r.Result.OptionsRating[problem.Options[1].Id])
+	s.problemStorage.AssertNotCalled(s.T(), "CreateProblem", mock.Anything, mock.Anything)
+	s.decisionStorage.AssertNotCalled(s.T(), "CreateDecision", mock.Anything, mock.Anything)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_NewProblem() {
+	userId := kit.NewId()
+	problem := s.problem()
+	s.problemStorage.On("CreateProblem", mock.Anything, mock.AnythingOfType("*domain.Problem")).Return(nil)
+	s.decisionStorage.On("CreateDecision", mock.Anything, mock.AnythingOfType("*domain.Decision")).Return(nil)
+	r, err := s.svc.MakeDecision(s.Ctx, userId, problem)
+	s.NoError(err)
+	s.NotEmpty(problem.Id)
+	s.Equal(userId, problem.UserId)
+	s.Equal(problem.Id, r.ProblemId)
+	s.Equal(userId, r.UserId)
+	s.NotEmpty(r.CreatedAt)
+	for _, op := range problem.Options {
+		s.NotEmpty(op.Id)
+		s.NoError(kit.ValidateUUIDs(op.Id))
+	}
+	s.problemStorage.AssertExpectations(s.T())
+	s.decisionStorage.AssertExpectations(s.T())
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_ExistentProblem() {
+	userId := kit.NewId()
+	problem := s.problem()
+	problem.Id = kit.NewId()
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(&domain.Problem{Id: problem.Id, UserId: userId}, nil)
+	s.problemStorage.On("UpdateProblem", mock.Anything, problem).Return(nil)
+	s.decisionStorage.On("CreateDecision", mock.Anything, mock.AnythingOfType("*domain.Decision")).Return(nil)
+	_, err := s.svc.MakeDecision(s.Ctx, userId, problem)
+	s.NoError(err)
+	s.problemStorage.AssertExpectations(s.T())
+	s.decisionStorage.AssertExpectations(s.T())
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_SameProblemNotUpdated() {
+	userId := kit.NewId()
+	problem := s.problem()
+	setIds(problem)
+	stored := s.problem()
+	for i, op := range stored.Options {
+		op.Id = problem.Options[i].Id
+		op.Pros[0].Id, op.Cons[0].Id = problem.Options[i].Pros[0].Id, problem.Options[i].Cons[0].Id
+		op.Fields = map[string]string{}
+	}
+	stored.Id, stored.UserId, stored.Method, stored.Version = problem.Id, userId, domain.MethodProsCons, 5
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(stored, nil)
+	s.problemStorage.On("GetForeignIds", mock.Anything, problem.Id, mock.Anything, mock.Anything).Return(nil, nil)
+	s.decisionStorage.On("CreateDecision", mock.Anything, mock.AnythingOfType("*domain.Decision")).Return(nil)
+	r, err := s.svc.MakeDecision(s.Ctx, userId, problem)
+	s.NoError(err)
+	s.Equal(5, r.ProblemVersion)
+	s.problemStorage.AssertNotCalled(s.T(), "UpdateProblem", mock.Anything, mock.Anything)
+
+	// any change makes a new version
+	problem.Options[1].Cons[0].Probability = 0.6
+	s.problemStorage.On("UpdateProblem", mock.Anything, problem).Return(nil)
+	_, err = s.svc.MakeDecision(s.Ctx, userId, problem)
+	s.NoError(err)
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_StoredVersion() {
+	problem := s.problem()
+	s.problemStorage.On("CreateProblem", mock.Anything, problem).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Problem).Version = 1 }).
+		Return(nil)
+	s.decisionStorage.On("CreateDecision", mock.Anything, mock.AnythingOfType("*domain.Decision")).Return(nil)
+	r, err := s.svc.MakeDecision(s.Ctx, kit.NewId(), problem)
+	s.NoError(err)
+	s.Equal(1, r.ProblemVersion)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_InvalidProblemNotStored() {
+	tests := []struct {
+		name   string
+		modify func(p *domain.Problem)
+		code   string
+	}{
+		{"importance", func(p *domain.Problem) { p.Options[0].Pros[0].Importance = -5 }, errors.ErrCodeDecisionQualityImportanceInvalid},
+		{"probability", func(p *domain.Problem) { p.Options[0].Cons[0].Probability = 2 }, errors.ErrCodeDecisionQualityProbabilityInvalid},
+		{"ahp not square", func(p *domain.Problem) {
+			p.Method = domain.MethodAhp
+			p.Ahp = &domain.Ahp{CriteriaComparisons: [][]float64{{1, 2}}, OptionComparisons: [][][]float64{{{1, 2}, {0.5, 1}}}}
+		}, errors.ErrCodeDecisionAhpMatrixInvalid},
+		{"topsis scores", func(p *domain.Problem) {
+			p.Method = domain.MethodTopsis
+			p.Topsis = &domain.Topsis{Criteria: []*domain.TopsisCriterion{{Weight: 1}}, Scores: [][]float64{{1}}}
+		}, errors.ErrCodeDecisionTopsisInvalid},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			problem := s.problem()
+			problem.Id = kit.NewId()
+			userId := kit.NewId()
+			s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(&domain.Problem{Id: problem.Id, UserId: userId}, nil)
+			tt.modify(problem)
+			_, err := s.svc.MakeDecision(s.Ctx, userId, problem)
+			s.AssertAppErr(err, tt.code)
+		})
+	}
+	s.problemStorage.AssertNotCalled(s.T(), "UpdateProblem", mock.Anything, mock.Anything)
+	s.decisionStorage.AssertNotCalled(s.T(), "CreateDecision", mock.Anything, mock.Anything)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_AnotherUserProblem() {
+	problem := s.problem()
+	problem.Id = kit.NewId()
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(&domain.Problem{Id: problem.Id, UserId: kit.NewId()}, nil)
+	_, err := s.svc.MakeDecision(s.Ctx, kit.NewId(), problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemForbidden)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_DeletedProblem() {
+	problem := s.problem()
+	problem.Id = kit.NewId()
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(nil, nil)
+	s.problemStorage.On("ProblemExists", mock.Anything, problem.Id).Return(true, nil)
+	_, err := s.svc.MakeDecision(s.Ctx, kit.NewId(), problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemNotFound)
+	s.problemStorage.AssertNotCalled(s.T(), "CreateProblem", mock.Anything, mock.Anything)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_NewProblemWithId() {
+	problem := s.problem()
+	problem.Id = kit.NewId()
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(nil, nil)
+	s.problemStorage.On("ProblemExists", mock.Anything, problem.Id).Return(false, nil)
+	s.problemStorage.On("CreateProblem", mock.Anything, problem).Return(nil)
+	s.decisionStorage.On("CreateDecision", mock.Anything, mock.AnythingOfType("*domain.Decision")).Return(nil)
+	_, err := s.svc.MakeDecision(s.Ctx, kit.NewId(), problem)
+	s.NoError(err)
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_ForeignIds() {
+	userId := kit.NewId()
+	problem := s.problem()
+	problem.Id = kit.NewId()
+	problem.Options[0].Id, problem.Options[1].Pros[0].Id = kit.NewId(), kit.NewId()
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(&domain.Problem{Id: problem.Id, UserId: userId}, nil)
+	s.problemStorage.On("GetForeignIds", mock.Anything, problem.Id, []string{problem.Options[0].Id}, []string{problem.Options[1].Pros[0].Id}).
+		Return([]string{problem.Options[1].Pros[0].Id}, nil)
+	_, err := s.svc.MakeDecision(s.Ctx, userId, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemForeignIds)
+	s.problemStorage.AssertNotCalled(s.T(), "UpdateProblem", mock.Anything, mock.Anything)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_User_InvalidId() {
+	problem := s.problem()
+	problem.Options[0].Id = "invalid"
+	_, err := s.svc.MakeDecision(s.Ctx, kit.NewId(), problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemInvalidId)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_DuplicateOptionId() {
+	problem := s.problem()
+	problem.Options[0].Id = kit.NewId()
+	problem.Options[1].Id = problem.Options[0].Id
+	_, err := s.svc.MakeDecision(s.Ctx, "", problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionOptionIdDuplicate)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_DuplicateQualityId() {
+	problem := s.problem()
+	problem.Options[0].Pros[0].Id = kit.NewId()
+	problem.Options[1].Cons[0].Id = problem.Options[0].Pros[0].Id
+	_, err := s.svc.MakeDecision(s.Ctx, kit.NewId(), problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityIdDuplicate)
+	s.problemStorage.AssertNotCalled(s.T(), "CreateProblem", mock.Anything, mock.Anything)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_QualityNameEmpty() {
+	problem := s.problem()
+	problem.Options[0].Pros[0].Name = ""
+	_, err := s.svc.MakeDecision(s.Ctx, "", problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityNameEmpty)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_EmptyProblem() {
+	_, err := s.svc.MakeDecision(s.Ctx, "", nil)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemEmpty)
+}
+
+func (s *decisionTestSuite) Test_GetDecision_NotFound() {
+	id := kit.NewId()
+	s.decisionStorage.On("GetDecision", mock.Anything, id).Return(nil, nil)
+	_, err := s.svc.GetDecision(s.Ctx, id)
+	s.AssertAppErr(err, errors.ErrCodeDecisionNotFound)
+}
+
+func (s *decisionTestSuite) Test_MakeDecisionByProblem() {
+	userId := kit.NewId()
+	problem := s.problem()
+	problem.UserId = userId
+	problem.Version = 3
+	setIds(problem)
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	s.decisionStorage.On("CreateDecision", mock.Anything, mock.AnythingOfType("*domain.Decision")).Return(nil)
+	r, err := s.svc.MakeDecisionByProblem(s.Ctx, userId, problem.Id)
+	s.NoError(err)
+	s.Equal(problem.Id, r.ProblemId)
+	s.Equal(3, r.ProblemVersion)
+	s.Equal(2.0, r.Result.OptionsRating[problem.Options[0].Id])
+	s.decisionStorage.AssertExpectations(s.T())
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_InvalidMethod() {
+	problem := s.problem()
+	problem.Method = "unknown"
+	_, err := s.svc.MakeDecision(s.Ctx, "", problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionMethodInvalid)
+}
+
+func (s *decisionTestSuite) Test_MakeDecision_WithSimulation() {
+	problem := s.problem()
+	problem.Simulation = &domain.Simulation{Iterations: 10}
+	r, err := s.svc.MakeDecision(s.Ctx, "", problem)
+	s.NoError(err)
+	s.NotNil(r.Result.Simulation)
+	s.Equal(10, r.Result.Simulation.Iterations)
+	s.Len(r.Result.Simulation.Options, 2)
+}
@@ -0,0 +1,96 @@
+package impl
+
+import (
+	"context"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"math"
+	"math/rand"
+)
+
+// validateDistribution checks distribution params
+func validateDistribution(ctx context.Context, qualityId string, d *domain.Distribution) error {
+	if d == nil {
+		return nil
+	}
+	for _, v := range []float64{d.Min, d.Max, d.Mode, d.Mean, d.StdDev, d.Alpha, d.Beta} {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return errors.ErrDecisionDistributionInvalid(ctx, qualityId, "values must be finite")
+		}
+	}
+	switch d.Type {
+	case domain.DistUniform:
+		if d.Min > d.Max {
+			return errors.ErrDecisionDistributionInvalid(ctx, qualityId, "min > max")
+		}
+	case domain.DistTriangular:
+		if d.Min > d.Mode || d.Mode > d.Max {
+			return errors.ErrDecisionDistributionInvalid(ctx, qualityId, "mode must be in range [min, max]")
+		}
+	case domain.DistNormal:
+		if d.StdDev < 0 {
+			return errors.ErrDecisionDistributionInvalid(ctx, qualityId, "stdDev must be non-negative")
+		}
+	case domain.DistBeta:
+		if d.Alpha <= 0 || d.Beta <= 0 {
+			return errors.ErrDecisionDistributionInvalid(ctx, qualityId, "alpha and beta must be positive")
+		}
+		if d.Min > d.Max {
+			return errors.ErrDecisionDistributionInvalid(ctx, qualityId, "min > max")
+		}
+	default:
+		return errors.ErrDecisionDistributionInvalid(ctx, qualityId, "unknown type")
+	}
+	return nil
+}
+
+// sample draws a random value from the distribution
+func sample(rnd *rand.Rand, d *domain.Distribution) float64 {
+	switch d.Type {
+	case domain.DistUniform:
+		return d.Min + rnd.Float64()*(d.Max-d.Min)
+	case domain.DistTriangular:
+		if d.Max == d.Min {
+			return d.Min
+		}
+		// inverse CDF
+		u := rnd.Float64()
+		f := (d.Mode - d.Min) / (d.Max - d.Min)
+		if u < f {
+			return d.Min + math.Sqrt(u*(d.Max-d.Min)*(d.Mode-d.Min))
+		}
+		return d.Max - math.Sqrt((1-u)*(d.Max-d.Min)*(d.Max-d.Mode))
+	case domain.DistNormal:
+		return d.Mean + rnd.NormFloat64()*d.StdDev
+	case domain.DistBeta:
+		x, y := sampleGamma(rnd, d.Alpha), sampleGamma(rnd, d.Beta)
+		v := x / (x + y)
+		if d.Min == 0 && d.Max == 0 {
+			return v
+		}
+		return d.Min + v*(d.Max-d.Min)
+	}
+	return 0
+}
+
+// sampleGamma draws a random value from Gamma(shape, 1) by Marsaglia and Tsang method
+func sampleGamma(rnd *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// boost shape and scale back
+		return sampleGamma(rnd, shape+1) * math.Pow(rnd.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rnd.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rnd.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
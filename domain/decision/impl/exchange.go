@@ -0,0 +1,47 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/kit"
+	"io"
+)
+
+type exchangeServiceImpl struct {
+	problemService domain.ProblemService
+	codec          domain.ProblemCodec
+}
+
+func NewExchangeService(problemService domain.ProblemService, codec domain.ProblemCodec) domain.ExchangeService {
+	return &exchangeServiceImpl{
+		problemService: problemService,
+		codec:          codec,
+	}
+}
+
+func (s *exchangeServiceImpl) l() kit.CLogger {
+	return decision.L().Cmp("exchange-svc")
+}
+
+func (s *exchangeServiceImpl) ExportProblem(ctx context.Context, userId, problemId, format string) ([]byte, error) {
+	s.l().C(ctx).Mth("export").F(kit.KV{"format": format}).Dbg()
+
+	problem, err := s.problemService.GetProblem(ctx, userId, problemId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.codec.Encode(ctx, problem, format)
+}
+
+func (s *exchangeServiceImpl) ImportProblem(ctx context.Context, userId, format, name string, content io.Reader) (*domain.Problem, error) {
+	s.l().C(ctx).Mth("import").F(kit.KV{"format": format}).Dbg()
+
+	problem, err := s.codec.Decode(ctx, format, name, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.problemService.CreateProblem(ctx, userId, problem)
+}
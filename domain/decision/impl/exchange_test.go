@@ -0,0 +1,65 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type exchangeTestSuite struct {
+	kit.Suite
+	problemStorage *mocks.ProblemStorage
+	svc            domain.ExchangeService
+}
+
+func (s *exchangeTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *exchangeTestSuite) SetupTest() {
+	s.problemStorage = &mocks.ProblemStorage{}
+	s.svc = NewExchangeService(NewProblemService(builtInRegistry(s.Ctx), s.problemStorage), NewProblemCodec())
+}
+
+func TestExchangeSuite(t *testing.T) {
+	suite.Run(t, new(exchangeTestSuite))
+}
+
+func (s *exchangeTestSuite) Test_ImportProblem_Csv() {
+	userId := kit.NewId()
+	s.problemStorage.On("CreateProblem", mock.Anything, mock.Anything).Return(nil)
+	r, err := s.svc.ImportProblem(s.Ctx, userId, domain.ExchangeFormatCsv, "cars", strings.NewReader("option,pro:range,con:price\nfirst,8,9@0.5\nsecond,5,4\n"))
+	s.NoError(err)
+	s.Equal(userId, r.UserId)
+	s.Equal("cars", r.Name)
+	s.Len(r.Options, 2)
+	s.NoError(kit.ValidateUUIDs(r.Id, r.Options[0].Id, r.Options[0].Pros[0].Id))
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *exchangeTestSuite) Test_ImportProblem_MethodInvalid() {
+	_, err := s.svc.ImportProblem(s.Ctx, kit.NewId(), domain.ExchangeFormatYaml, "", strings.NewReader("version: 1\nproblem:\n  name: cars\n  method: any\n"))
+	s.AssertAppErr(err, errors.ErrCodeDecisionMethodInvalid)
+	s.problemStorage.AssertNotCalled(s.T(), "CreateProblem", mock.Anything, mock.Anything)
+}
+
+func (s *exchangeTestSuite) Test_ExportProblem_AnotherUser() {
+	problem := &domain.Problem{Id: kit.NewId(), UserId: kit.NewId(), Name: "cars"}
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	_, err := s.svc.ExportProblem(s.Ctx, kit.NewId(), problem.Id, domain.ExchangeFormatJson)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemForbidden)
+}
+
+func (s *exchangeTestSuite) Test_ExportProblem_Yaml() {
+	problem := &domain.Problem{Id: kit.NewId(), UserId: kit.NewId(), Name: "cars", Options: []*domain.Option{{Id: kit.NewId(), Name: "first"}}}
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	r, err := s.svc.ExportProblem(s.Ctx, problem.UserId, problem.Id, domain.ExchangeFormatYaml)
+	s.NoError(err)
+	s.Equal("version: 1\nproblem:\n    name: cars\n    options:\n        - name: first\n", string(r))
+}
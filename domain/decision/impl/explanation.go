@@ -0,0 +1,130 @@
+package impl
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/i18n"
+	"golang.org/x/text/language"
+	"sort"
+	"strings"
+)
+
+// locales are catalogs of explanation summaries
+//
+//go:embed locales/*.yaml
+var locales embed.FS
+
+func init() {
+	i18n.Default.MustLoad(locales, "locales")
+}
+
+// explain ranks options of the result and explains why the winner is chosen
+// if breakdown is requested, weighted pros and cons of each option are broken down by qualities
+func explain(ctx context.Context, problem *domain.Problem, res domain.DecisionResult, breakdown bool) *domain.Explanation {
+	r := &domain.Explanation{}
+	for _, op := range problem.Options {
+		rating, ok := res.OptionsRating[op.Id]
+		if !ok {
+			continue
+		}
+		oe := &domain.OptionExplanation{
+			OptionId: op.Id,
+			Name:     op.Name,
+			Rating:   rating,
+		}
+		if breakdown {
+			var pros, cons []*domain.QualityContribution
+			oe.ProsTotal, pros = contributions(domain.QualityKindPro, op.Pros)
+			oe.ConsTotal, cons = contributions(domain.QualityKindCon, op.Cons)
+			oe.Contributions = append(pros, cons...)
+		}
+		r.Ranking = append(r.Ranking, oe)
+	}
+	if len(r.Ranking) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(r.Ranking, func(i, j int) bool { return r.Ranking[i].Rating > r.Ranking[j].Rating })
+	for i, oe := range r.Ranking {
+		// tied options share the rank
+		if i > 0 && oe.Rating == r.Ranking[i-1].Rating {
+			oe.Rank = r.Ranking[i-1].Rank
+		} else {
+			oe.Rank = i + 1
+		}
+	}
+	if len(r.Ranking) > 1 {
+		r.Margin = kit.Round10000(r.Ranking[0].Rating - r.Ranking[1].Rating)
+	}
+
+	rCtx, _ := kit.Request(ctx)
+	r.Summary = summary(rCtx.GetLang(), r)
+	return r
+}
+
+// contributions returns the weighted total of the qualities and contribution of each quality ordered by weight
+func contributions(kind string, qualities []*domain.Quality) (float64, []*domain.QualityContribution) {
+	weights := make([]float64, len(qualities))
+	total := 0.0
+	for i, q := range qualities {
+		weights[i] = q.Importance * q.Probability
+		total += weights[i]
+	}
+	r := make([]*domain.QualityContribution, 0, len(qualities))
+	for i, q := range qualities {
+		c := &domain.QualityContribution{
+			QualityId: q.Id,
+			Name:      q.Name,
+			Kind:      kind,
+			Weight:    kit.Round10000(weights[i]),
+		}
+		if total > 0 {
+			c.Share = kit.Round10000(weights[i] / total)
+		}
+		r = append(r, c)
+	}
+	sort.SliceStable(r, func(i, j int) bool { return r[i].Weight > r[j].Weight })
+	return kit.Round10000(total), r
+}
+
+// summary generates a short explanation: the winner, its margin to the runner-up and the main pro and con of the winner
+func summary(lang language.Tag, e *domain.Explanation) string {
+	winner := e.Ranking[0]
+	args := kit.KV{"winner": optionName(winner), "rating": winner.Rating, "margin": e.Margin}
+	key := "explanation.single"
+	if len(e.Ranking) > 1 {
+		args["runnerUp"] = optionName(e.Ranking[1])
+		key = "explanation.winner"
+		if e.Ranking[1].Rank == winner.Rank {
+			key = "explanation.tie"
+		}
+	}
+
+	var parts []string
+	if msg, ok := i18n.Default.Message(lang, key, args); ok {
+		parts = append(parts, msg)
+	}
+	// contributions are ordered by weight, so the first one of the kind is the main one
+	for _, kind := range []string{domain.QualityKindPro, domain.QualityKindCon} {
+		for _, c := range winner.Contributions {
+			if c.Kind != kind || c.Weight == 0 {
+				continue
+			}
+			if msg, ok := i18n.Default.Message(lang, "explanation."+kind, kit.KV{"quality": c.Name, "share": fmt.Sprintf("%.0f", c.Share*100)}); ok {
+				parts = append(parts, msg)
+			}
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func optionName(oe *domain.OptionExplanation) string {
+	if oe.Name != "" {
+		return oe.Name
+	}
+	return oe.OptionId
+}
@@ -0,0 +1,116 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+	"testing"
+)
+
+type explanationTestSuite struct {
+	kit.Suite
+	registry domain.MethodRegistry
+}
+
+func (s *explanationTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+	s.registry = builtInRegistry(s.Ctx)
+}
+
+func TestExplanationSuite(t *testing.T) {
+	suite.Run(t, new(explanationTestSuite))
+}
+
+func (s *explanationTestSuite) problem() *domain.Problem {
+	return &domain.Problem{
+		Id:   kit.NewId(),
+		Name: "problem",
+		Options: []*domain.Option{
+			{
+				Id:   kit.NewId(),
+				Name: "second",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "speed", Importance: 5, Probability: 1}},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "price", Importance: 5, Probability: 1}},
+			},
+			{
+				Id:   kit.NewId(),
+				Name: "first",
+				Pros: []*domain.Quality{
+					{Id: kit.NewId(), Name: "design", Importance: 2, Probability: 1},
+					{Id: kit.NewId(), Name: "price", Importance: 8, Probability: 1},
+				},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "noise", Importance: 4, Probability: 0.5}},
+			},
+		},
+	}
+}
+
+func (s *explanationTestSuite) Test_ProsCons_Breakdown() {
+	problem := s.problem()
+	d, err := calculate(s.Ctx, s.registry, problem, "")
+	s.NoError(err)
+	e := d.Result.Explanation
+	s.NotNil(e)
+	s.Len(e.Ranking, 2)
+	s.Equal(4.0, e.Margin)
+
+	winner := e.Ranking[0]
+	s.Equal(problem.Options[1].Id, winner.OptionId)
+	s.Equal(1, winner.Rank)
+	s.Equal(5.0, winner.Rating)
+	s.Equal(10.0, winner.ProsTotal)
+	s.Equal(2.0, winner.ConsTotal)
+	s.Len(winner.Contributions, 3)
+	// pros go first ordered by weight
+	s.Equal(&domain.QualityContribution{QualityId: problem.Options[1].Pros[1].Id, Name: "price", Kind: domain.QualityKindPro, Weight: 8, Share: 0.8}, winner.Contributions[0])
+	s.Equal(&domain.QualityContribution{QualityId: problem.Options[1].Pros[0].Id, Name: "design", Kind: domain.QualityKindPro, Weight: 2, Share: 0.2}, winner.Contributions[1])
+	s.Equal(&domain.QualityContribution{QualityId: problem.Options[1].Cons[0].Id, Name: "noise", Kind: domain.QualityKindCon, Weight: 2, Share: 1}, winner.Contributions[2])
+
+	runnerUp := e.Ranking[1]
+	s.Equal(problem.Options[0].Id, runnerUp.OptionId)
+	s.Equal(2, runnerUp.Rank)
+	s.Equal(1.0, runnerUp.Rating)
+
+	s.Equal(`"first" is the best option with rating 5, 4 ahead of "second". Its strongest pro is "price" (80% of pros). Its main con is "noise" (100% of cons).`, e.Summary)
+}
+
+func (s *explanationTestSuite) Test_Summary_Localized() {
+	ctx := kit.NewRequestCtx().WithLang(language.Russian).ToContext(s.Ctx)
+	d, err := calculate(ctx, s.registry, s.problem(), "")
+	s.NoError(err)
+	s.Equal("«first» — лучший вариант с рейтингом 5, опережает «second» на 4. Главный плюс — «price» (80% плюсов). Главный минус — «noise» (100% минусов).", d.Result.Explanation.Summary)
+}
+
+func (s *explanationTestSuite) Test_Tie() {
+	problem := s.problem()
+	problem.Options = append(problem.Options, &domain.Option{Id: kit.NewId(), Name: "third"})
+	e := explain(s.Ctx, problem, domain.DecisionResult{OptionsRating: map[string]float64{
+		problem.Options[0].Id: 2,
+		problem.Options[1].Id: 1,
+		problem.Options[2].Id: 2,
+	}}, false)
+	s.Equal([]int{1, 1, 3}, []int{e.Ranking[0].Rank, e.Ranking[1].Rank, e.Ranking[2].Rank})
+	// tied options keep the order of the problem
+	s.Equal(problem.Options[0].Id, e.Ranking[0].OptionId)
+	s.Equal(problem.Options[2].Id, e.Ranking[1].OptionId)
+	s.Equal(0.0, e.Margin)
+	s.Empty(e.Ranking[0].Contributions)
+	s.Equal(`"second" and "third" share the first place with rating 2.`, e.Summary)
+}
+
+func (s *explanationTestSuite) Test_SingleOption() {
+	problem := s.problem()
+	problem.Options = problem.Options[:1]
+	d, err := calculate(s.Ctx, s.registry, problem, "")
+	s.NoError(err)
+	e := d.Result.Explanation
+	s.Len(e.Ranking, 1)
+	s.Equal(0.0, e.Margin)
+	s.Equal(`"second" is the only option, its rating is 1. Its strongest pro is "speed" (100% of pros). Its main con is "price" (100% of cons).`, e.Summary)
+}
+
+func (s *explanationTestSuite) Test_NotRated() {
+	s.Nil(explain(s.Ctx, s.problem(), domain.DecisionResult{}, true))
+}
@@ -0,0 +1,446 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"math"
+	"sort"
+	"time"
+)
+
+// ownerWeight weight of the problem owner if not specified among members
+const ownerWeight = 1.0
+
+type groupServiceImpl struct {
+	methodRegistry  domain.MethodRegistry
+	problemStorage  domain.ProblemStorage
+	groupStorage    domain.GroupStorage
+	decisionStorage domain.DecisionStorage
+	computations    *computations
+}
+
+func NewGroupService(methodRegistry domain.MethodRegistry, problemStorage domain.ProblemStorage, groupStorage domain.GroupStorage, decisionStorage domain.DecisionStorage) domain.GroupService {
+	return &tracedGroupService{
+		svc: &groupServiceImpl{
+			methodRegistry:  methodRegistry,
+			problemStorage:  problemStorage,
+			groupStorage:    groupStorage,
+			decisionStorage: decisionStorage,
+			computations:    newComputations(),
+		},
+	}
+}
+
+func (s *groupServiceImpl) l() kit.CLogger {
+	return decision.L().Cmp("group-svc")
+}
+
+func (s *groupServiceImpl) SetMembers(ctx context.Context, userId, problemId string, members []*domain.Member) ([]*domain.Member, error) {
+	s.l().C(ctx).Mth("set-members").Dbg()
+
+	problem, _, err := s.getGroupProblem(ctx, userId, problemId)
+	if err != nil {
+		return nil, err
+	}
+	if problem.UserId != userId {
+		return nil, errors.ErrDecisionProblemOwnerOnly(ctx, problemId)
+	}
+
+	users := make(map[string]struct{}, len(members))
+	for _, m := range members {
+		if m == nil {
+			return nil, errors.ErrDecisionMemberInvalid(ctx, "")
+		}
+		if err := kit.ValidateUUIDs(m.UserId); err != nil || m.Weight <= 0 || math.IsInf(m.Weight, 0) || math.IsNaN(m.Weight) {
+			return nil, errors.ErrDecisionMemberInvalid(ctx, m.UserId)
+		}
+		if _, ok := users[m.UserId]; ok {
+			return nil, errors.ErrDecisionMemberDuplicate(ctx, m.UserId)
+		}
+		users[m.UserId] = struct{}{}
+	}
+
+	if err := s.groupStorage.SetMembers(ctx, problemId, members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (s *groupServiceImpl) GetMembers(ctx context.Context, userId, problemId string) ([]*domain.Member, error) {
+	s.l().C(ctx).Mth("get-members").Dbg()
+	_, members, err := s.getGroupProblem(ctx, userId, problemId)
+	return members, err
+}
+
+func (s *groupServiceImpl) SetAssessments(ctx context.Context, userId, problemId string, assessments []*domain.Assessment) ([]*domain.Assessment, error) {
+	s.l().C(ctx).Mth("set-assessments").Dbg()
+
+	problem, _, err := s.getGroupProblem(ctx, userId, problemId)
+	if err != nil {
+		return nil, err
+	}
+
+	// each quality of the problem can be assessed once
+	qs := make(map[string]bool)
+	for _, op := range problem.Options {
+		for _, q := range qualities(op) {
+			qs[q.Id] = false
+		}
+	}
+	for _, a := range assessments {
+		if a == nil {
+			return nil, errors.ErrDecisionAssessmentQualityInvalid(ctx, "")
+		}
+		if assessed, ok := qs[a.QualityId]; !ok || assessed {
+			return nil, errors.ErrDecisionAssessmentQualityInvalid(ctx, a.QualityId)
+		}
+		qs[a.QualityId] = true
+		if err := validateQualityValues(ctx, &domain.Quality{Id: a.QualityId, Importance: a.Importance, Probability: a.Probability}); err != nil {
+			return nil, err
+		}
+		a.UserId = userId
+	}
+
+	if err := s.groupStorage.SetAssessments(ctx, problemId, userId, assessments); err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
+
+func (s *groupServiceImpl) GetAssessments(ctx context.Context, userId, problemId string) ([]*domain.Assessment, error) {
+	s.l().C(ctx).Mth("get-assessments").Dbg()
+	if _, _, err := s.getGroupProblem(ctx, userId, problemId); err != nil {
+		return nil, err
+	}
+	return s.groupStorage.GetAssessments(ctx, problemId)
+}
+
+func (s *groupServiceImpl) MakeGroupDecision(ctx context.Context, userId, problemId, aggregation string) (*domain.Decision, error) {
+	l := s.l().C(ctx).Mth("make-group-decision")
+
+	if aggregation == "" {
+		aggregation = domain.AggregationMean
+	}
+	if aggregation != domain.AggregationMean && aggregation != domain.AggregationGeometric && aggregation != domain.AggregationBorda {
+		return nil, errors.ErrDecisionAggregationInvalid(ctx, aggregation)
+	}
+
+	ctx, done, err := s.computations.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	problem, members, err := s.getGroupProblem(ctx, userId, problemId)
+	if err != nil {
+		return nil, err
+	}
+	assessments, err := s.groupStorage.GetAssessments(ctx, problemId)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := groupParticipants(problem, members)
+	problems := assessedProblems(problem, participants, assessments)
+
+	var r *domain.Decision
+	if aggregation == domain.AggregationBorda {
+		r, err = s.borda(ctx, problem, participants, problems, userId)
+	} else {
+		r, err = calculate(ctx, s.methodRegistry, aggregateProblem(problem, participants, problems, aggregation), userId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.Result.Group = &domain.GroupResult{
+		Aggregation:  aggregation,
+		Participants: participants,
+		Spread:       qualitySpread(problem, problems),
+	}
+	r.CreatedAt = kit.Now()
+
+	// store decision
+	if err = s.decisionStorage.CreateDecision(ctx, r); err != nil {
+		return nil, err
+	}
+
+	l.F(kit.KV{"problemId": problem.Id, "decisionId": r.Id}).Dbg("stored")
+
+	return r, nil
+}
+
+func (s *groupServiceImpl) GetDecisions(ctx context.Context, userId, problemId string) ([]*domain.Decision, error) {
+	s.l().C(ctx).Mth("get-decisions").Dbg()
+	if _, _, err := s.getGroupProblem(ctx, userId, problemId); err != nil {
+		return nil, err
+	}
+	return s.decisionStorage.GetDecisionsByProblem(ctx, problemId)
+}
+
+func (s *groupServiceImpl) Close(ctx context.Context) error {
+	s.l().C(ctx).Mth("close").Dbg()
+	return s.computations.close(ctx)
+}
+
+// getGroupProblem retrieves the problem with its members and checks the user is either the owner or a member
+func (s *groupServiceImpl) getGroupProblem(ctx context.Context, userId, problemId string) (*domain.Problem, []*domain.Member, error) {
+	if err := kit.ValidateUUIDs(problemId); err != nil {
+		return nil, nil, errors.ErrDecisionProblemInvalidId(ctx, problemId)
+	}
+	problem, err := s.problemStorage.GetProblem(ctx, problemId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problem == nil {
+		return nil, nil, errors.ErrDecisionProblemNotFound(ctx, problemId)
+	}
+	members, err := s.groupStorage.GetMembers(ctx, problemId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problem.UserId == userId {
+		return problem, members, nil
+	}
+	for _, m := range members {
+		if m.UserId == userId {
+			return problem, members, nil
+		}
+	}
+	return nil, nil, errors.ErrDecisionProblemForbidden(ctx, problemId)
+}
+
+// borda rates options of each participant's problem and sums weighted Borda points
+// an option gets (n - 1 - rank) points, tied options share the points of their ranks
+// the rating is normalized by the total weight, so that the max possible rating is n - 1
+func (s *groupServiceImpl) borda(ctx context.Context, problem *domain.Problem, participants []*domain.Member, problems []*domain.Problem, userId string) (*domain.Decision, error) {
+
+	method, err := s.methodRegistry.Get(ctx, problem.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	points := make(map[string]float64, len(problem.Options))
+	totalWeight := 0.0
+	for i, m := range participants {
+		var pr domain.DecisionResult
+		pr, err = method.Calculate(ctx, problems[i])
+		if err != nil {
+			break
+		}
+		for opId, p := range bordaPoints(problem, pr.OptionsRating) {
+			points[opId] += m.Weight * p
+		}
+		totalWeight += m.Weight
+	}
+	observeComputation(method.Code(), start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	// scoring params are taken from the problem, since results of participants may resolve defaults differently
+	res := domain.DecisionResult{
+		Method:        method.Code(),
+		OptionsRating: make(map[string]float64, len(problem.Options)),
+	}
+	if method.Code() == domain.MethodProsCons {
+		res.Scoring = domain.ScoringRatio
+		if problem.ProsCons != nil {
+			if problem.ProsCons.Scoring != "" {
+				res.Scoring = problem.ProsCons.Scoring
+			}
+			if problem.ProsCons.Smoothing != nil {
+				res.Smoothing = *problem.ProsCons.Smoothing
+			}
+		}
+	}
+	for _, op := range problem.Options {
+		res.OptionsRating[op.Id] = kit.Round10000(points[op.Id] / totalWeight)
+	}
+	// Borda points aren't derived from qualities, so only the ranking is explained
+	res.Explanation = explain(ctx, problem, res, false)
+
+	return &domain.Decision{
+		Id:             kit.NewId(),
+		ProblemId:      problem.Id,
+		ProblemVersion: problem.Version,
+		UserId:         userId,
+		Result:         res,
+	}, nil
+}
+
+// bordaPoints converts ratings to Borda points
+func bordaPoints(problem *domain.Problem, rating map[string]float64) map[string]float64 {
+	ops := make([]string, 0, len(problem.Options))
+	for _, op := range problem.Options {
+		ops = append(ops, op.Id)
+	}
+	sort.SliceStable(ops, func(i, j int) bool { return rating[ops[i]] > rating[ops[j]] })
+
+	n := len(ops)
+	r := make(map[string]float64, n)
+	for i := 0; i < n; {
+		// group of tied options takes ranks i..j-1
+		j := i + 1
+		for j < n && rating[ops[j]] == rating[ops[i]] {
+			j++
+		}
+		p := 0.0
+		for k := i; k < j; k++ {
+			p += float64(n - 1 - k)
+		}
+		for k := i; k < j; k++ {
+			r[ops[k]] = p / float64(j-i)
+		}
+		i = j
+	}
+	return r
+}
+
+// groupParticipants returns members along with the owner
+func groupParticipants(problem *domain.Problem, members []*domain.Member) []*domain.Member {
+	for _, m := range members {
+		if m.UserId == problem.UserId {
+			return members
+		}
+	}
+	return append([]*domain.Member{{UserId: problem.UserId, Weight: ownerWeight}}, members...)
+}
+
+// assessedProblems returns a copy of the problem for each participant with qualities set to the participant's assessments
+// qualities not assessed by the participant keep values of the problem
+func assessedProblems(problem *domain.Problem, participants []*domain.Member, assessments []*domain.Assessment) []*domain.Problem {
+	byUser := make(map[string]map[string]*domain.Assessment, len(participants))
+	for _, a := range assessments {
+		if byUser[a.UserId] == nil {
+			byUser[a.UserId] = make(map[string]*domain.Assessment)
+		}
+		byUser[a.UserId][a.QualityId] = a
+	}
+	r := make([]*domain.Problem, 0, len(participants))
+	for _, m := range participants {
+		user := byUser[m.UserId]
+		r = append(r, copyProblem(problem, func(q *domain.Quality) {
+			if a, ok := user[q.Id]; ok {
+				q.Importance, q.Probability = a.Importance, a.Probability
+			}
+		}))
+	}
+	return r
+}
+
+// aggregateProblem returns a copy of the problem with qualities aggregated over participants' problems
+func aggregateProblem(problem *domain.Problem, participants []*domain.Member, problems []*domain.Problem, aggregation string) *domain.Problem {
+	values := groupValues(problem, problems)
+	return copyProblem(problem, func(q *domain.Quality) {
+		v := values[q.Id]
+		q.Importance = aggregate(aggregation, participants, v.importance)
+		q.Probability = aggregate(aggregation, participants, v.probability)
+	})
+}
+
+// aggregate calculates weighted arithmetic or geometric mean
+func aggregate(aggregation string, participants []*domain.Member, values []float64) float64 {
+	sum, totalWeight := 0.0, 0.0
+	for i, m := range participants {
+		totalWeight += m.Weight
+		if aggregation == domain.AggregationGeometric {
+			// any zero value makes the geometric mean zero
+			if values[i] == 0 {
+				return 0
+			}
+			sum += m.Weight * math.Log(values[i])
+		} else {
+			sum += m.Weight * values[i]
+		}
+	}
+	if aggregation == domain.AggregationGeometric {
+		return math.Exp(sum / totalWeight)
+	}
+	return sum / totalWeight
+}
+
+type qualityValues struct {
+	importance  []float64
+	probability []float64
+}
+
+// groupValues collects values of each quality over participants' problems
+func groupValues(problem *domain.Problem, problems []*domain.Problem) map[string]*qualityValues {
+	r := make(map[string]*qualityValues)
+	for _, op := range problem.Options {
+		for _, q := range qualities(op) {
+			r[q.Id] = &qualityValues{}
+		}
+	}
+	for _, p := range problems {
+		for _, op := range p.Options {
+			for _, q := range qualities(op) {
+				v := r[q.Id]
+				v.importance = append(v.importance, q.Importance)
+				v.probability = append(v.probability, q.Probability)
+			}
+		}
+	}
+	return r
+}
+
+// qualitySpread calculates disagreement of participants about each quality
+func qualitySpread(problem *domain.Problem, problems []*domain.Problem) []*domain.QualitySpread {
+	values := groupValues(problem, problems)
+	var r []*domain.QualitySpread
+	for _, op := range problem.Options {
+		for _, q := range qualities(op) {
+			v := values[q.Id]
+			s := &domain.QualitySpread{QualityId: q.Id}
+			s.ImportanceMin, s.ImportanceMax, s.ImportanceStdDev = spread(v.importance)
+			s.ProbabilityMin, s.ProbabilityMax, s.ProbabilityStdDev = spread(v.probability)
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+// spread returns min, max and population standard deviation
+func spread(values []float64) (float64, float64, float64) {
+	if len(values) == 0 {
+		return 0, 0, 0
+	}
+	min, max, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, v := range values {
+		min, max = math.Min(min, v), math.Max(max, v)
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return min, max, kit.Round10000(math.Sqrt(variance / float64(len(values))))
+}
+
+// copyProblem copies the problem with its options and qualities, fn modifies copied qualities
+func copyProblem(problem *domain.Problem, fn func(q *domain.Quality)) *domain.Problem {
+	r := *problem
+	r.Options = make([]*domain.Option, 0, len(problem.Options))
+	for _, op := range problem.Options {
+		o := &domain.Option{Id: op.Id, Name: op.Name}
+		o.Pros = copyQualities(op.Pros, fn)
+		o.Cons = copyQualities(op.Cons, fn)
+		r.Options = append(r.Options, o)
+	}
+	return &r
+}
+
+func copyQualities(qualities []*domain.Quality, fn func(q *domain.Quality)) []*domain.Quality {
+	r := make([]*domain.Quality, 0, len(qualities))
+	for _, q := range qualities {
+		c := *q
+		fn(&c)
+		r = append(r, &c)
+	}
+	return r
+}
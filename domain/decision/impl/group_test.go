@@ -0,0 +1,253 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type groupTestSuite struct {
+	kit.Suite
+	problemStorage  *mocks.ProblemStorage
+	groupStorage    *mocks.GroupStorage
+	decisionStorage *mocks.DecisionStorage
+	svc             domain.GroupService
+}
+
+func (s *groupTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *groupTestSuite) SetupTest() {
+	s.problemStorage = &mocks.ProblemStorage{}
+	s.groupStorage = &mocks.GroupStorage{}
+	s.decisionStorage = &mocks.DecisionStorage{}
+	s.svc = NewGroupService(builtInRegistry(s.Ctx), s.problemStorage, s.groupStorage, s.decisionStorage)
+}
+
+func TestGroupSuite(t *testing.T) {
+	suite.Run(t, new(groupTestSuite))
+}
+
+// storedProblem mocks a stored problem with members
+// first option: pro 10 * 0.5, con 5 * 0.5; second option: pro 5 * 0.5, con 10 * 0.5
+func (s *groupTestSuite) storedProblem(ownerId string, members ...*domain.Member) *domain.Problem {
+	problem := &domain.Problem{
+		Id:     kit.NewId(),
+		UserId: ownerId,
+		Name:   "problem",
+		Options: []*domain.Option{
+			{
+				Id:   kit.NewId(),
+				Name: "first",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 10, Probability: 0.5}},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 5, Probability: 0.5}},
+			},
+			{
+				Id:   kit.NewId(),
+				Name: "second",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 5, Probability: 0.5}},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 10, Probability: 0.5}},
+			},
+		},
+	}
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	s.groupStorage.On("GetMembers", mock.Anything, problem.Id).Return(members, nil)
+	return problem
+}
+
+// groupDecision makes a decision where the member assesses importance of the first option's pro as 2
+func (s *groupTestSuite) groupDecision(aggregation string, memberWeight float64) (*domain.Problem, *domain.Decision, error) {
+	ownerId, memberId := kit.NewId(), kit.NewId()
+	problem := s.storedProblem(ownerId, &domain.Member{UserId: memberId, Weight: memberWeight})
+	s.groupStorage.On("GetAssessments", mock.Anything, problem.Id).Return([]*domain.Assessment{
+		{UserId: memberId, QualityId: problem.Options[0].Pros[0].Id, Importance: 2, Probability: 0.5},
+	}, nil)
+	s.decisionStorage.On("CreateDecision", mock.Anything, mock.Anything).Return(nil)
+	r, err := s.svc.MakeGroupDecision(s.Ctx, memberId, problem.Id, aggregation)
+	return problem, r, err
+}
+
+func (s *groupTestSuite) Test_SetMembers_Ok() {
+	ownerId := kit.NewId()
+	problem := s.storedProblem(ownerId)
+	members := []*domain.Member{{UserId: kit.NewId(), Weight: 2}}
+	s.groupStorage.On("SetMembers", mock.Anything, problem.Id, members).Return(nil)
+	r, err := s.svc.SetMembers(s.Ctx, ownerId, problem.Id, members)
+	s.NoError(err)
+	s.Equal(members, r)
+	s.groupStorage.AssertExpectations(s.T())
+}
+
+func (s *groupTestSuite) Test_SetMembers_NotOwner() {
+	memberId := kit.NewId()
+	problem := s.storedProblem(kit.NewId(), &domain.Member{UserId: memberId, Weight: 1})
+	_, err := s.svc.SetMembers(s.Ctx, memberId, problem.Id, nil)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemOwnerOnly)
+}
+
+func (s *groupTestSuite) Test_SetMembers_Invalid() {
+	ownerId := kit.NewId()
+	problem := s.storedProblem(ownerId)
+	_, err := s.svc.SetMembers(s.Ctx, ownerId, problem.Id, []*domain.Member{{UserId: kit.NewId()}})
+	s.AssertAppErr(err, errors.ErrCodeDecisionMemberInvalid)
+	_, err = s.svc.SetMembers(s.Ctx, ownerId, problem.Id, []*domain.Member{{UserId: "any", Weight: 1}})
+	s.AssertAppErr(err, errors.ErrCodeDecisionMemberInvalid)
+}
+
+func (s *groupTestSuite) Test_SetMembers_Duplicate() {
+	ownerId, memberId := kit.NewId(), kit.NewId()
+	problem := s.storedProblem(ownerId)
+	_, err := s.svc.SetMembers(s.Ctx, ownerId, problem.Id, []*domain.Member{{UserId: memberId, Weight: 1}, {UserId: memberId, Weight: 2}})
+	s.AssertAppErr(err, errors.ErrCodeDecisionMemberDuplicate)
+}
+
+func (s *groupTestSuite) Test_SetAssessments_Ok() {
+	memberId := kit.NewId()
+	problem := s.storedProblem(kit.NewId(), &domain.Member{UserId: memberId, Weight: 1})
+	assessments := []*domain.Assessment{{QualityId: problem.Options[1].Cons[0].Id, Importance: 3, Probability: 1}}
+	s.groupStorage.On("SetAssessments", mock.Anything, problem.Id, memberId, assessments).Return(nil)
+	r, err := s.svc.SetAssessments(s.Ctx, memberId, problem.Id, assessments)
+	s.NoError(err)
+	s.Equal(memberId, r[0].UserId)
+	s.groupStorage.AssertExpectations(s.T())
+}
+
+func (s *groupTestSuite) Test_SetAssessments_Forbidden() {
+	problem := s.storedProblem(kit.NewId())
+	_, err := s.svc.SetAssessments(s.Ctx, kit.NewId(), problem.Id, nil)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemForbidden)
+}
+
+func (s *groupTestSuite) Test_SetAssessments_QualityInvalid() {
+	ownerId := kit.NewId()
+	problem := s.storedProblem(ownerId)
+	_, err := s.svc.SetAssessments(s.Ctx, ownerId, problem.Id, []*domain.Assessment{{QualityId: kit.NewId()}})
+	s.AssertAppErr(err, errors.ErrCodeDecisionAssessmentQualityInvalid)
+	qualityId := problem.Options[0].Pros[0].Id
+	_, err = s.svc.SetAssessments(s.Ctx, ownerId, problem.Id, []*domain.Assessment{{QualityId: qualityId}, {QualityId: qualityId}})
+	s.AssertAppErr(err, errors.ErrCodeDecisionAssessmentQualityInvalid)
+}
+
+func (s *groupTestSuite) Test_SetAssessments_ValuesInvalid() {
+	ownerId := kit.NewId()
+	problem := s.storedProblem(ownerId)
+	_, err := s.svc.SetAssessments(s.Ctx, ownerId, problem.Id, []*domain.Assessment{{QualityId: problem.Options[0].Pros[0].Id, Probability: 2}})
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityProbabilityInvalid)
+}
+
+func (s *groupTestSuite) Test_MakeGroupDecision_Mean() {
+	problem, r, err := s.groupDecision("", 1)
+	s.NoError(err)
+	// importance (10 + 2) / 2 = 6, rating 6 * 0.5 / 2.5
+	s.Equal(1.2, r.Result.OptionsRating[problem.Options[0].Id])
+	s.Equal(0.5, r.Result.OptionsRating[problem.Options[1].Id])
+	s.Equal(domain.AggregationMean, r.Result.Group.Aggregation)
+	s.Len(r.Result.Group.Participants, 2)
+	s.Equal(problem.UserId, r.Result.Group.Participants[0].UserId)
+	s.Len(r.Result.Group.Spread, 4)
+	sp := r.Result.Group.Spread[0]
+	s.Equal(problem.Options[0].Pros[0].Id, sp.QualityId)
+	s.Equal(2.0, sp.ImportanceMin)
+	s.Equal(10.0, sp.ImportanceMax)
+	s.Equal(4.0, sp.ImportanceStdDev)
+	s.Equal(0.0, sp.ProbabilityStdDev)
+	s.Equal(0.0, r.Result.Group.Spread[1].ImportanceStdDev)
+	// stored problem isn't modified
+	s.Equal(10.0, problem.Options[0].Pros[0].Importance)
+	s.decisionStorage.AssertExpectations(s.T())
+}
+
+func (s *groupTestSuite) Test_MakeGroupDecision_WeightedMean() {
+	problem, r, err := s.groupDecision(domain.AggregationMean, 3)
+	s.NoError(err)
+	// importance (10 + 2 * 3) / 4 = 4, rating 4 * 0.5 / 2.5
+	s.Equal(0.8, r.Result.OptionsRating[problem.Options[0].Id])
+}
+
+func (s *groupTestSuite) Test_MakeGroupDecision_Geometric() {
+	problem, r, err := s.groupDecision(domain.AggregationGeometric, 1)
+	s.NoError(err)
+	// importance sqrt(10 * 2), rating sqrt(20) * 0.5 / 2.5
+	s.Equal(0.89, r.Result.OptionsRating[problem.Options[0].Id])
+	s.Equal(domain.AggregationGeometric, r.Result.Group.Aggregation)
+}
+
+func (s *groupTestSuite) Test_MakeGroupDecision_Borda() {
+	problem, r, err := s.groupDecision(domain.AggregationBorda, 3)
+	s.NoError(err)
+	// the owner prefers the first option, the member with weight 3 prefers the second one
+	s.Equal(0.25, r.Result.OptionsRating[problem.Options[0].Id])
+	s.Equal(0.75, r.Result.OptionsRating[problem.Options[1].Id])
+	s.Equal(domain.AggregationBorda, r.Result.Group.Aggregation)
+}
+
+func (s *groupTestSuite) Test_MakeGroupDecision_Borda_ProblemScoring() {
+	ownerId, memberId := kit.NewId(), kit.NewId()
+	problem := s.storedProblem(ownerId, &domain.Member{UserId: memberId, Weight: 1})
+	// zero cons of the member make default smoothing applied to the member's rating only
+	s.groupStorage.On("GetAssessments", mock.Anything, problem.Id).Return([]*domain.Assessment{
+		{UserId: memberId, QualityId: problem.Options[0].Cons[0].Id, Importance: 0, Probability: 0.5},
+	}, nil)
+	s.decisionStorage.On("CreateDecision", mock.Anything, mock.Anything).Return(nil)
+	r, err := s.svc.MakeGroupDecision(s.Ctx, memberId, problem.Id, domain.AggregationBorda)
+	s.NoError(err)
+	s.Equal(domain.MethodProsCons, r.Result.Method)
+	s.Equal(domain.ScoringRatio, r.Result.Scoring)
+	s.Equal(0.0, r.Result.Smoothing)
+
+	problem.ProsCons = &domain.ProsCons{Scoring: domain.ScoringNet, Smoothing: kit.Float64Ptr(2)}
+	r, err = s.svc.MakeGroupDecision(s.Ctx, memberId, problem.Id, domain.AggregationBorda)
+	s.NoError(err)
+	s.Equal(domain.ScoringNet, r.Result.Scoring)
+	s.Equal(2.0, r.Result.Smoothing)
+}
+
+func (s *groupTestSuite) Test_MakeGroupDecision_AggregationInvalid() {
+	_, err := s.svc.MakeGroupDecision(s.Ctx, kit.NewId(), kit.NewId(), "any")
+	s.AssertAppErr(err, errors.ErrCodeDecisionAggregationInvalid)
+}
+
+func (s *groupTestSuite) Test_MakeGroupDecision_NotFound() {
+	problemId := kit.NewId()
+	s.problemStorage.On("GetProblem", mock.Anything, problemId).Return(nil, nil)
+	_, err := s.svc.MakeGroupDecision(s.Ctx, kit.NewId(), problemId, domain.AggregationMean)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemNotFound)
+}
+
+func (s *groupTestSuite) Test_MakeGroupDecision_Closed() {
+	s.NoError(s.svc.Close(s.Ctx))
+	_, err := s.svc.MakeGroupDecision(s.Ctx, kit.NewId(), kit.NewId(), domain.AggregationMean)
+	s.AssertAppErr(err, errors.ErrCodeDecisionServiceClosed)
+	s.problemStorage.AssertNotCalled(s.T(), "GetProblem", mock.Anything, mock.Anything)
+}
+
+func (s *groupTestSuite) Test_GetDecisions_Member() {
+	memberId := kit.NewId()
+	problem := s.storedProblem(kit.NewId(), &domain.Member{UserId: memberId, Weight: 1})
+	decisions := []*domain.Decision{{Id: kit.NewId(), ProblemId: problem.Id}}
+	s.decisionStorage.On("GetDecisionsByProblem", mock.Anything, problem.Id).Return(decisions, nil)
+	r, err := s.svc.GetDecisions(s.Ctx, memberId, problem.Id)
+	s.NoError(err)
+	s.Equal(decisions, r)
+}
+
+func (s *groupTestSuite) Test_GetDecisions_Forbidden() {
+	problem := s.storedProblem(kit.NewId())
+	_, err := s.svc.GetDecisions(s.Ctx, kit.NewId(), problem.Id)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemForbidden)
+	s.decisionStorage.AssertNotCalled(s.T(), "GetDecisionsByProblem", mock.Anything, mock.Anything)
+}
+
+func (s *groupTestSuite) Test_BordaPoints_Ties() {
+	problem := &domain.Problem{Options: []*domain.Option{{Id: "a"}, {Id: "b"}, {Id: "c"}}}
+	r := bordaPoints(problem, map[string]float64{"a": 1, "b": 3, "c": 1})
+	s.Equal(2.0, r["b"])
+	s.Equal(0.5, r["a"])
+	s.Equal(0.5, r["c"])
+}
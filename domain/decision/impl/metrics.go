@@ -0,0 +1,26 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision/kit/metrics"
+	"time"
+)
+
+const (
+	resultOk    = "ok"
+	resultError = "error"
+)
+
+var (
+	computationsTotal   = metrics.Default.Counter("decision_computations_total", "Number of decision computations", "method", "result")
+	computationDuration = metrics.Default.Histogram("decision_computation_duration_seconds", "Decision computation duration including simulation", nil, "method")
+)
+
+// observeComputation records a decision computation by the method
+func observeComputation(method string, start time.Time, err error) {
+	result := resultOk
+	if err != nil {
+		result = resultError
+	}
+	computationsTotal.Inc(method, result)
+	computationDuration.Observe(time.Since(start).Seconds(), method)
+}
@@ -0,0 +1,398 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type problemServiceImpl struct {
+	methodRegistry domain.MethodRegistry
+	problemStorage domain.ProblemStorage
+}
+
+func NewProblemService(methodRegistry domain.MethodRegistry, problemStorage domain.ProblemStorage) domain.ProblemService {
+	return &problemServiceImpl{
+		methodRegistry: methodRegistry,
+		problemStorage: problemStorage,
+	}
+}
+
+func (s *problemServiceImpl) l() kit.CLogger {
+	return decision.L().Cmp("problem-svc")
+}
+
+func (s *problemServiceImpl) CreateProblem(ctx context.Context, userId string, problem *domain.Problem) (*domain.Problem, error) {
+	s.l().C(ctx).Mth("create").Dbg()
+
+	if problem == nil {
+		return nil, errors.ErrDecisionProblemEmpty(ctx)
+	}
+	if err := s.validateProblem(ctx, problem); err != nil {
+		return nil, err
+	}
+
+	// ids are always generated by the service
+	problem.Id = ""
+	for _, op := range problem.Options {
+		op.Id = ""
+		for _, q := range qualities(op) {
+			q.Id = ""
+		}
+	}
+	setIds(problem)
+
+	problem.UserId = userId
+	problem.CreatedAt, problem.UpdatedAt = kit.Now(), kit.Now()
+
+	if err := s.problemStorage.CreateProblem(ctx, problem); err != nil {
+		return nil, err
+	}
+	return problem, nil
+}
+
+func (s *problemServiceImpl) UpdateProblem(ctx context.Context, userId string, problem *domain.Problem) (*domain.Problem, error) {
+	s.l().C(ctx).Mth("update").Dbg()
+
+	if problem == nil {
+		return nil, errors.ErrDecisionProblemEmpty(ctx)
+	}
+	if problem.Name == "" {
+		return nil, errors.ErrDecisionProblemNameEmpty(ctx)
+	}
+	if err := validateMethod(ctx, s.methodRegistry, problem); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.GetProblem(ctx, userId, problem.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *stored
+	updated.Name = problem.Name
+	updated.Method = problem.Method
+	updated.Ahp = problem.Ahp
+	updated.ProsCons = problem.ProsCons
+	updated.Topsis = problem.Topsis
+	updated.Simulation = problem.Simulation
+
+	// no new version if nothing changed
+	if sameProblem(&updated, stored) {
+		return stored, nil
+	}
+	updated.UpdatedAt = kit.Now()
+
+	if err := s.problemStorage.UpdateProblem(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (s *problemServiceImpl) GetProblem(ctx context.Context, userId, problemId string) (*domain.Problem, error) {
+	s.l().C(ctx).Mth("get").Dbg()
+	return getUserProblem(ctx, s.problemStorage, userId, problemId)
+}
+
+func (s *problemServiceImpl) SearchProblems(ctx context.Context, criteria *domain.ProblemSearchCriteria) (*domain.ProblemSearchResponse, error) {
+	s.l().C(ctx).Mth("search").Dbg()
+
+	if criteria.Size <= 0 {
+		criteria.Size = defaultPageSize
+	}
+	if criteria.Size > maxPageSize {
+		criteria.Size = maxPageSize
+	}
+	if criteria.Index <= 0 {
+		criteria.Index = 1
+	}
+
+	return s.problemStorage.SearchProblems(ctx, criteria)
+}
+
+func (s *problemServiceImpl) DeleteProblem(ctx context.Context, userId, problemId string) error {
+	s.l().C(ctx).Mth("delete").Dbg()
+
+	if _, err := s.GetProblem(ctx, userId, problemId); err != nil {
+		return err
+	}
+
+	return s.problemStorage.DeleteProblem(ctx, problemId)
+}
+
+func (s *problemServiceImpl) AddOption(ctx context.Context, userId, problemId string, option *domain.Option) (*domain.Option, error) {
+	s.l().C(ctx).Mth("add-option").Dbg()
+
+	if err := validateOption(ctx, option); err != nil {
+		return nil, err
+	}
+
+	problem, err := s.GetProblem(ctx, userId, problemId)
+	if err != nil {
+		return nil, err
+	}
+	// there is no way to specify comparisons of the new option with the others
+	if hasOptionComparisons(problem) {
+		return nil, errors.ErrDecisionOptionComparisonsSet(ctx, problemId)
+	}
+
+	option.Id = kit.NewId()
+	for _, q := range qualities(option) {
+		q.Id = kit.NewId()
+	}
+
+	if err := s.problemStorage.CreateOption(ctx, problemId, option); err != nil {
+		return nil, err
+	}
+	return option, nil
+}
+
+func (s *problemServiceImpl) UpdateOption(ctx context.Context, userId, problemId string, option *domain.Option) (*domain.Option, error) {
+	s.l().C(ctx).Mth("update-option").Dbg()
+
+	if option == nil || option.Name == "" {
+		return nil, errors.ErrDecisionOptionNameEmpty(ctx)
+	}
+
+	stored, err := s.getOption(ctx, userId, problemId, option.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored.Name = option.Name
+	stored.Fields = option.Fields
+
+	if err := s.problemStorage.UpdateOption(ctx, stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (s *problemServiceImpl) DeleteOption(ctx context.Context, userId, problemId, optionId string) error {
+	s.l().C(ctx).Mth("delete-option").Dbg()
+
+	problem, err := s.GetProblem(ctx, userId, problemId)
+	if err != nil {
+		return err
+	}
+	for i, op := range problem.Options {
+		if op.Id == optionId {
+			removeOptionComparisons(problem, i)
+			return s.problemStorage.DeleteOption(ctx, problem, optionId)
+		}
+	}
+	return errors.ErrDecisionOptionNotFound(ctx, optionId)
+}
+
+func (s *problemServiceImpl) AddQuality(ctx context.Context, userId, problemId, optionId, kind string, quality *domain.Quality) (*domain.Quality, error) {
+	s.l().C(ctx).Mth("add-quality").Dbg()
+
+	if err := s.validateKind(ctx, kind); err != nil {
+		return nil, err
+	}
+	if err := validateQuality(ctx, quality); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getOption(ctx, userId, problemId, optionId); err != nil {
+		return nil, err
+	}
+
+	quality.Id = kit.NewId()
+
+	if err := s.problemStorage.CreateQuality(ctx, problemId, optionId, kind, quality); err != nil {
+		return nil, err
+	}
+	return quality, nil
+}
+
+func (s *problemServiceImpl) UpdateQuality(ctx context.Context, userId, problemId, optionId, kind string, quality *domain.Quality) (*domain.Quality, error) {
+	s.l().C(ctx).Mth("update-quality").Dbg()
+
+	if err := validateQuality(ctx, quality); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.getQuality(ctx, userId, problemId, optionId, kind, quality.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored.Name = quality.Name
+	stored.Importance = quality.Importance
+	stored.Probability = quality.Probability
+	stored.ImportanceDist = quality.ImportanceDist
+	stored.ProbabilityDist = quality.ProbabilityDist
+
+	if err := s.problemStorage.UpdateQuality(ctx, stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (s *problemServiceImpl) DeleteQuality(ctx context.Context, userId, problemId, optionId, kind, qualityId string) error {
+	s.l().C(ctx).Mth("delete-quality").Dbg()
+
+	if _, err := s.getQuality(ctx, userId, problemId, optionId, kind, qualityId); err != nil {
+		return err
+	}
+
+	return s.problemStorage.DeleteQuality(ctx, qualityId)
+}
+
+func (s *problemServiceImpl) getOption(ctx context.Context, userId, problemId, optionId string) (*domain.Option, error) {
+	problem, err := s.GetProblem(ctx, userId, problemId)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range problem.Options {
+		if op.Id == optionId {
+			return op, nil
+		}
+	}
+	return nil, errors.ErrDecisionOptionNotFound(ctx, optionId)
+}
+
+func (s *problemServiceImpl) getQuality(ctx context.Context, userId, problemId, optionId, kind, qualityId string) (*domain.Quality, error) {
+	if err := s.validateKind(ctx, kind); err != nil {
+		return nil, err
+	}
+	option, err := s.getOption(ctx, userId, problemId, optionId)
+	if err != nil {
+		return nil, err
+	}
+	qs := option.Pros
+	if kind == domain.QualityKindCon {
+		qs = option.Cons
+	}
+	for _, q := range qs {
+		if q.Id == qualityId {
+			return q, nil
+		}
+	}
+	return nil, errors.ErrDecisionQualityNotFound(ctx, qualityId)
+}
+
+func (s *problemServiceImpl) validateProblem(ctx context.Context, problem *domain.Problem) error {
+	if problem.Name == "" {
+		return errors.ErrDecisionProblemNameEmpty(ctx)
+	}
+	if err := validateMethod(ctx, s.methodRegistry, problem); err != nil {
+		return err
+	}
+	return validateOptions(ctx, problem)
+}
+
+// validateOptions checks options and their qualities, ids must be unique within the problem
+func validateOptions(ctx context.Context, problem *domain.Problem) error {
+	optionIds, qualityIds := map[string]struct{}{}, map[string]struct{}{}
+	for _, op := range problem.Options {
+		if err := validateOption(ctx, op); err != nil {
+			return err
+		}
+		if op.Id != "" {
+			if _, ok := optionIds[op.Id]; ok {
+				return errors.ErrDecisionOptionIdDuplicate(ctx, op.Id)
+			}
+			optionIds[op.Id] = struct{}{}
+		}
+		for _, q := range qualities(op) {
+			if q.Id == "" {
+				continue
+			}
+			if _, ok := qualityIds[q.Id]; ok {
+				return errors.ErrDecisionQualityIdDuplicate(ctx, q.Id)
+			}
+			qualityIds[q.Id] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func validateOption(ctx context.Context, option *domain.Option) error {
+	if option == nil || option.Name == "" {
+		return errors.ErrDecisionOptionNameEmpty(ctx)
+	}
+	for _, q := range qualities(option) {
+		if err := validateQuality(ctx, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateQuality(ctx context.Context, quality *domain.Quality) error {
+	if quality == nil || quality.Name == "" {
+		return errors.ErrDecisionQualityNameEmpty(ctx)
+	}
+	if err := validateQualityValues(ctx, quality); err != nil {
+		return err
+	}
+	if err := validateDistribution(ctx, quality.Id, quality.ImportanceDist); err != nil {
+		return err
+	}
+	return validateDistribution(ctx, quality.Id, quality.ProbabilityDist)
+}
+
+func (s *problemServiceImpl) validateKind(ctx context.Context, kind string) error {
+	if kind != domain.QualityKindPro && kind != domain.QualityKindCon {
+		return errors.ErrDecisionQualityKindInvalid(ctx, kind)
+	}
+	return nil
+}
+
+// hasOptionComparisons checks if the problem has method params indexed by option order
+func hasOptionComparisons(problem *domain.Problem) bool {
+	return (problem.Ahp != nil && len(problem.Ahp.OptionComparisons) > 0) || (problem.Topsis != nil && len(problem.Topsis.Scores) > 0)
+}
+
+// removeOptionComparisons removes comparisons of the option with the given index from method params
+func removeOptionComparisons(problem *domain.Problem, index int) {
+	if problem.Ahp != nil {
+		for k, m := range problem.Ahp.OptionComparisons {
+			if index >= len(m) {
+				continue
+			}
+			r := make([][]float64, 0, len(m)-1)
+			for i, row := range m {
+				if i == index {
+					continue
+				}
+				if index < len(row) {
+					row = append(append([]float64{}, row[:index]...), row[index+1:]...)
+				}
+				r = append(r, row)
+			}
+			problem.Ahp.OptionComparisons[k] = r
+		}
+	}
+	if problem.Topsis != nil && index < len(problem.Topsis.Scores) {
+		scores := problem.Topsis.Scores
+		problem.Topsis.Scores = append(append([][]float64{}, scores[:index]...), scores[index+1:]...)
+	}
+}
+
+// getUserProblem retrieves the problem and checks it belongs to the user
+func getUserProblem(ctx context.Context, problemStorage domain.ProblemStorage, userId, problemId string) (*domain.Problem, error) {
+	if err := kit.ValidateUUIDs(problemId); err != nil {
+		return nil, errors.ErrDecisionProblemInvalidId(ctx, problemId)
+	}
+	problem, err := problemStorage.GetProblem(ctx, problemId)
+	if err != nil {
+		return nil, err
+	}
+	if problem == nil {
+		return nil, errors.ErrDecisionProblemNotFound(ctx, problemId)
+	}
+	if problem.UserId != userId {
+		return nil, errors.ErrDecisionProblemForbidden(ctx, problemId)
+	}
+	return problem, nil
+}
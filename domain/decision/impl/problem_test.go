@@ -0,0 +1,224 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type problemTestSuite struct {
+	kit.Suite
+	problemStorage *mocks.ProblemStorage
+	svc            domain.ProblemService
+}
+
+func (s *problemTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *problemTestSuite) SetupTest() {
+	s.problemStorage = &mocks.ProblemStorage{}
+	s.svc = NewProblemService(builtInRegistry(s.Ctx), s.problemStorage)
+}
+
+func TestProblemSuite(t *testing.T) {
+	suite.Run(t, new(problemTestSuite))
+}
+
+func (s *problemTestSuite) storedProblem(userId string) *domain.Problem {
+	return &domain.Problem{
+		Id:     kit.NewId(),
+		UserId: userId,
+		Name:   "problem",
+		Options: []*domain.Option{
+			{
+				Id:   kit.NewId(),
+				Name: "option",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 10, Probability: 0.5}},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 5, Probability: 0.5}},
+			},
+		},
+	}
+}
+
+func (s *problemTestSuite) Test_CreateProblem_IdsGenerated() {
+	userId := kit.NewId()
+	problem := &domain.Problem{
+		Id:   "any",
+		Name: "problem",
+		Options: []*domain.Option{
+			{Name: "option", Pros: []*domain.Quality{{Name: "pro"}}},
+		},
+	}
+	s.problemStorage.On("CreateProblem", mock.Anything, problem).Return(nil)
+	r, err := s.svc.CreateProblem(s.Ctx, userId, problem)
+	s.NoError(err)
+	s.NoError(kit.ValidateUUIDs(r.Id, r.Options[0].Id, r.Options[0].Pros[0].Id))
+	s.Equal(userId, r.UserId)
+	s.NotEmpty(r.CreatedAt)
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *problemTestSuite) Test_CreateProblem_NameEmpty() {
+	_, err := s.svc.CreateProblem(s.Ctx, kit.NewId(), &domain.Problem{})
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemNameEmpty)
+}
+
+func (s *problemTestSuite) Test_CreateProblem_QualityNameEmpty() {
+	problem := &domain.Problem{
+		Name:    "problem",
+		Options: []*domain.Option{{Name: "option", Cons: []*domain.Quality{{}}}},
+	}
+	_, err := s.svc.CreateProblem(s.Ctx, kit.NewId(), problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityNameEmpty)
+}
+
+func (s *problemTestSuite) Test_GetProblem_NotFound() {
+	id := kit.NewId()
+	s.problemStorage.On("GetProblem", mock.Anything, id).Return(nil, nil)
+	_, err := s.svc.GetProblem(s.Ctx, kit.NewId(), id)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemNotFound)
+}
+
+func (s *problemTestSuite) Test_GetProblem_AnotherUser() {
+	problem := s.storedProblem(kit.NewId())
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	_, err := s.svc.GetProblem(s.Ctx, kit.NewId(), problem.Id)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemForbidden)
+}
+
+func (s *problemTestSuite) Test_GetProblem_InvalidId() {
+	_, err := s.svc.GetProblem(s.Ctx, kit.NewId(), "invalid")
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemInvalidId)
+}
+
+func (s *problemTestSuite) Test_SearchProblems_PagingDefaults() {
+	criteria := &domain.ProblemSearchCriteria{UserId: kit.NewId()}
+	criteria.Size = 1000
+	s.problemStorage.On("SearchProblems", mock.Anything, criteria).Return(&domain.ProblemSearchResponse{}, nil)
+	_, err := s.svc.SearchProblems(s.Ctx, criteria)
+	s.NoError(err)
+	s.Equal(maxPageSize, criteria.Size)
+	s.Equal(1, criteria.Index)
+}
+
+func (s *problemTestSuite) Test_DeleteProblem() {
+	userId := kit.NewId()
+	problem := s.storedProblem(userId)
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	s.problemStorage.On("DeleteProblem", mock.Anything, problem.Id).Return(nil)
+	s.NoError(s.svc.DeleteProblem(s.Ctx, userId, problem.Id))
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *problemTestSuite) Test_UpdateProblem_NotChanged() {
+	userId := kit.NewId()
+	stored := s.storedProblem(userId)
+	stored.Method = domain.MethodProsCons
+	s.problemStorage.On("GetProblem", mock.Anything, stored.Id).Return(stored, nil)
+	r, err := s.svc.UpdateProblem(s.Ctx, userId, &domain.Problem{Id: stored.Id, Name: stored.Name})
+	s.NoError(err)
+	s.Equal(stored, r)
+	s.problemStorage.AssertNotCalled(s.T(), "UpdateProblem", mock.Anything, mock.Anything)
+}
+
+func (s *problemTestSuite) Test_AddOption() {
+	userId := kit.NewId()
+	problem := s.storedProblem(userId)
+	option := &domain.Option{Name: "new", Pros: []*domain.Quality{{Name: "pro"}}}
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	s.problemStorage.On("CreateOption", mock.Anything, problem.Id, option).Return(nil)
+	r, err := s.svc.AddOption(s.Ctx, userId, problem.Id, option)
+	s.NoError(err)
+	s.NoError(kit.ValidateUUIDs(r.Id, r.Pros[0].Id))
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *problemTestSuite) Test_AddOption_ComparisonsSet() {
+	userId := kit.NewId()
+	problem := s.storedProblem(userId)
+	problem.Method = domain.MethodAhp
+	problem.Ahp = &domain.Ahp{CriteriaComparisons: [][]float64{{1}}, OptionComparisons: [][][]float64{{{1}}}}
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	_, err := s.svc.AddOption(s.Ctx, userId, problem.Id, &domain.Option{Name: "new"})
+	s.AssertAppErr(err, errors.ErrCodeDecisionOptionComparisonsSet)
+	s.problemStorage.AssertNotCalled(s.T(), "CreateOption", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *problemTestSuite) Test_DeleteOption_ComparisonsRemoved() {
+	userId := kit.NewId()
+	problem := s.storedProblem(userId)
+	problem.Options = append(problem.Options, &domain.Option{Id: kit.NewId(), Name: "second"}, &domain.Option{Id: kit.NewId(), Name: "third"})
+	problem.Ahp = &domain.Ahp{
+		CriteriaComparisons: [][]float64{{1}},
+		OptionComparisons:   [][][]float64{{{1, 2, 4}, {0.5, 1, 2}, {0.25, 0.5, 1}}},
+	}
+	problem.Topsis = &domain.Topsis{
+		Criteria: []*domain.TopsisCriterion{{Weight: 1}},
+		Scores:   [][]float64{{1}, {2}, {3}},
+	}
+	optionId := problem.Options[1].Id
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	s.problemStorage.On("DeleteOption", mock.Anything, problem, optionId).Return(nil)
+	s.NoError(s.svc.DeleteOption(s.Ctx, userId, problem.Id, optionId))
+	s.Equal([][][]float64{{{1, 4}, {0.25, 1}}}, problem.Ahp.OptionComparisons)
+	s.Equal([][]float64{{1}, {3}}, problem.Topsis.Scores)
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *problemTestSuite) Test_DeleteOption_NotFound() {
+	userId := kit.NewId()
+	problem := s.storedProblem(userId)
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	err := s.svc.DeleteOption(s.Ctx, userId, problem.Id, kit.NewId())
+	s.AssertAppErr(err, errors.ErrCodeDecisionOptionNotFound)
+}
+
+func (s *problemTestSuite) Test_UpdateOption_NotFound() {
+	userId := kit.NewId()
+	problem := s.storedProblem(userId)
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	_, err := s.svc.UpdateOption(s.Ctx, userId, problem.Id, &domain.Option{Id: kit.NewId(), Name: "option"})
+	s.AssertAppErr(err, errors.ErrCodeDecisionOptionNotFound)
+}
+
+func (s *problemTestSuite) Test_UpdateQuality() {
+	userId := kit.NewId()
+	problem := s.storedProblem(userId)
+	con := problem.Options[0].Cons[0]
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	s.problemStorage.On("UpdateQuality", mock.Anything, con).Return(nil)
+	r, err := s.svc.UpdateQuality(s.Ctx, userId, problem.Id, problem.Options[0].Id, domain.QualityKindCon,
+		&domain.Quality{Id: con.Id, Name: "updated", Importance: 1, Probability: 1})
+	s.NoError(err)
+	s.Equal("updated", r.Name)
+	s.Equal(1.0, r.Importance)
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *problemTestSuite) Test_DeleteQuality_WrongKind() {
+	userId := kit.NewId()
+	problem := s.storedProblem(userId)
+	s.problemStorage.On("GetProblem", mock.Anything, problem.Id).Return(problem, nil)
+	err := s.svc.DeleteQuality(s.Ctx, userId, problem.Id, problem.Options[0].Id, domain.QualityKindPro, problem.Options[0].Cons[0].Id)
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityNotFound)
+}
+
+func (s *problemTestSuite) Test_AddQuality_InvalidKind() {
+	_, err := s.svc.AddQuality(s.Ctx, kit.NewId(), kit.NewId(), kit.NewId(), "neutral", &domain.Quality{Name: "q"})
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityKindInvalid)
+}
+
+func (s *problemTestSuite) Test_CreateProblem_ProbabilityInvalid() {
+	problem := &domain.Problem{
+		Name:    "problem",
+		Options: []*domain.Option{{Name: "option", Pros: []*domain.Quality{{Name: "pro", Probability: -0.1}}}},
+	}
+	_, err := s.svc.CreateProblem(s.Ctx, kit.NewId(), problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityProbabilityInvalid)
+}
@@ -0,0 +1,143 @@
+package impl
+
+import (
+	"context"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"math"
+)
+
+const (
+	// defaultRatioSmoothing smoothing applied to ratio scoring if some option has zero cons
+	defaultRatioSmoothing = 1.0
+)
+
+// prosConsMethod rates an option by the weighted pros and the weighted cons
+// either as their ratio (default) or as normalized net score, see domain.ProsCons
+type prosConsMethod struct{}
+
+func NewProsConsMethod() domain.DecisionMethod {
+	return &prosConsMethod{}
+}
+
+func (m *prosConsMethod) Code() string {
+	return domain.MethodProsCons
+}
+
+func (m *prosConsMethod) Describe() *domain.MethodDescription {
+	dist := objectParam("distribution for simulation", map[string]*domain.ParamSchema{
+		"type":   stringParam("distribution type", domain.DistUniform, domain.DistTriangular, domain.DistNormal, domain.DistBeta),
+		"min":    numberParam("min value (uniform, triangular, beta)", nil, nil),
+		"max":    numberParam("max value (uniform, triangular, beta)", nil, nil),
+		"mode":   numberParam("most likely value (triangular)", nil, nil),
+		"mean":   numberParam("mean value (normal)", nil, nil),
+		"stdDev": numberParam("standard deviation (normal)", kit.Float64Ptr(0), nil),
+		"alpha":  numberParam("shape (beta)", kit.Float64Ptr(0), nil),
+		"beta":   numberParam("shape (beta)", kit.Float64Ptr(0), nil),
+	}, "type")
+	quality := objectParam("", map[string]*domain.ParamSchema{
+		"name":            stringParam("quality name"),
+		"importance":      numberParam("how important the quality is", kit.Float64Ptr(0), nil),
+		"probability":     numberParam("probability of the quality", kit.Float64Ptr(0), kit.Float64Ptr(1)),
+		"importanceDist":  dist,
+		"probabilityDist": dist,
+	}, "name")
+	return &domain.MethodDescription{
+		Code:        domain.MethodProsCons,
+		Name:        "Pros and cons",
+		Description: "an option is rated by the sum of pros and the sum of cons weighted by importance and probability either as their ratio or as normalized net score",
+		Params: map[string]*domain.ParamSchema{
+			"prosCons": objectParam("scoring model", map[string]*domain.ParamSchema{
+				"scoring":   stringParam("ratio: (pros + smoothing) / (cons + smoothing), net: (pros - cons) / (pros + cons + smoothing)", domain.ScoringRatio, domain.ScoringNet),
+				"smoothing": numberParam("additive smoothing, if empty 1 is applied to ratio when some option has zero cons", kit.Float64Ptr(0), nil),
+			}),
+			"options": arrayParam("options", objectParam("", map[string]*domain.ParamSchema{
+				"name": stringParam("option name"),
+				"pros": arrayParam("positive qualities", quality),
+				"cons": arrayParam("negative qualities", quality),
+			}, "name")),
+		},
+	}
+}
+
+func (m *prosConsMethod) Calculate(ctx context.Context, problem *domain.Problem) (domain.DecisionResult, error) {
+
+	r := domain.DecisionResult{
+		Method:        domain.MethodProsCons,
+		Scoring:       domain.ScoringRatio,
+		OptionsRating: make(map[string]float64, len(problem.Options)),
+	}
+
+	var smoothing *float64
+	if problem.ProsCons != nil {
+		if problem.ProsCons.Scoring != "" {
+			r.Scoring = problem.ProsCons.Scoring
+		}
+		smoothing = problem.ProsCons.Smoothing
+	}
+	if r.Scoring != domain.ScoringRatio && r.Scoring != domain.ScoringNet {
+		return r, errors.ErrDecisionScoringInvalid(ctx, r.Scoring)
+	}
+	if smoothing != nil && (*smoothing < 0 || math.IsInf(*smoothing, 0) || math.IsNaN(*smoothing)) {
+		return r, errors.ErrDecisionSmoothingInvalid(ctx)
+	}
+
+	kPros, kCons := make([]float64, len(problem.Options)), make([]float64, len(problem.Options))
+	zeroCons := false
+	for i, op := range problem.Options {
+		//
+		for _, con := range op.Cons {
+			if err := validateQualityValues(ctx, con); err != nil {
+				return r, err
+			}
+			kCons[i] += con.Importance * con.Probability
+		}
+		//
+		for _, pro := range op.Pros {
+			if err := validateQualityValues(ctx, pro); err != nil {
+				return r, err
+			}
+			kPros[i] += pro.Importance * pro.Probability
+		}
+		zeroCons = zeroCons || kCons[i] == 0
+	}
+
+	// the ratio is undefined for zero cons, so the same smoothing is applied to all options to keep them comparable
+	if smoothing != nil {
+		r.Smoothing = *smoothing
+	} else if zeroCons && r.Scoring == domain.ScoringRatio {
+		r.Smoothing = defaultRatioSmoothing
+	}
+
+	for i, op := range problem.Options {
+		kPro, kCon := kPros[i], kCons[i]
+		switch r.Scoring {
+		case domain.ScoringRatio:
+			if kCon+r.Smoothing == 0 {
+				return r, errors.ErrDecisionRatioUndefined(ctx, op.Id)
+			}
+			r.OptionsRating[op.Id] = kit.Round100((kPro + r.Smoothing) / (kCon + r.Smoothing))
+		case domain.ScoringNet:
+			// neither pros nor cons means neutral
+			if kPro+kCon+r.Smoothing == 0 {
+				r.OptionsRating[op.Id] = 0
+				continue
+			}
+			r.OptionsRating[op.Id] = kit.Round10000((kPro - kCon) / (kPro + kCon + r.Smoothing))
+		}
+	}
+
+	return r, nil
+}
+
+// validateQualityValues checks importance and probability of the quality
+func validateQualityValues(ctx context.Context, quality *domain.Quality) error {
+	if quality.Importance < 0 || math.IsInf(quality.Importance, 0) || math.IsNaN(quality.Importance) {
+		return errors.ErrDecisionQualityImportanceInvalid(ctx, quality.Id, quality.Importance)
+	}
+	if quality.Probability < 0 || quality.Probability > 1 || math.IsNaN(quality.Probability) {
+		return errors.ErrDecisionQualityProbabilityInvalid(ctx, quality.Id, quality.Probability)
+	}
+	return nil
+}
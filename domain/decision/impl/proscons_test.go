@@ -0,0 +1,123 @@
+package impl
+
+import (
+	"encoding/json"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"testing"
+)
+
+type prosConsTestSuite struct {
+	kit.Suite
+	method domain.DecisionMethod
+}
+
+func (s *prosConsTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+	s.method = NewProsConsMethod()
+}
+
+func TestProsConsSuite(t *testing.T) {
+	suite.Run(t, new(prosConsTestSuite))
+}
+
+func (s *prosConsTestSuite) problem() *domain.Problem {
+	return &domain.Problem{
+		Id:   kit.NewId(),
+		Name: "problem",
+		Options: []*domain.Option{
+			{
+				Id:   kit.NewId(),
+				Name: "first",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 10, Probability: 0.5}},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 5, Probability: 0.5}},
+			},
+			{
+				Id:   kit.NewId(),
+				Name: "second",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 5, Probability: 0.5}},
+			},
+			{
+				Id:   kit.NewId(),
+				Name: "third",
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 0, Probability: 1}},
+			},
+		},
+	}
+}
+
+func (s *prosConsTestSuite) Test_Ratio_NoCons_SmoothingApplied() {
+	problem := s.problem()
+	r, err := s.method.Calculate(s.Ctx, problem)
+	s.NoError(err)
+	s.Equal(domain.ScoringRatio, r.Scoring)
+	s.Equal(defaultRatioSmoothing, r.Smoothing)
+	s.Equal(1.71, r.OptionsRating[problem.Options[0].Id])
+	s.Equal(3.5, r.OptionsRating[problem.Options[1].Id])
+	s.Equal(1.0, r.OptionsRating[problem.Options[2].Id])
+	for _, v := range r.OptionsRating {
+		s.False(math.IsInf(v, 0) || math.IsNaN(v))
+	}
+	_, err = json.Marshal(r)
+	s.NoError(err)
+}
+
+func (s *prosConsTestSuite) Test_Ratio_NoSmoothingNeeded() {
+	problem := s.problem()
+	problem.Options = problem.Options[:1]
+	r, err := s.method.Calculate(s.Ctx, problem)
+	s.NoError(err)
+	s.Equal(0.0, r.Smoothing)
+	s.Equal(2.0, r.OptionsRating[problem.Options[0].Id])
+}
+
+func (s *prosConsTestSuite) Test_Ratio_ZeroSmoothing_Undefined() {
+	problem := s.problem()
+	problem.ProsCons = &domain.ProsCons{Smoothing: kit.Float64Ptr(0)}
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionRatioUndefined)
+}
+
+func (s *prosConsTestSuite) Test_Net() {
+	problem := s.problem()
+	problem.ProsCons = &domain.ProsCons{Scoring: domain.ScoringNet}
+	r, err := s.method.Calculate(s.Ctx, problem)
+	s.NoError(err)
+	s.Equal(domain.ScoringNet, r.Scoring)
+	s.Equal(0.0, r.Smoothing)
+	s.Equal(0.3333, r.OptionsRating[problem.Options[0].Id])
+	s.Equal(1.0, r.OptionsRating[problem.Options[1].Id])
+	s.Equal(0.0, r.OptionsRating[problem.Options[2].Id])
+}
+
+func (s *prosConsTestSuite) Test_InvalidScoring() {
+	problem := s.problem()
+	problem.ProsCons = &domain.ProsCons{Scoring: "log"}
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionScoringInvalid)
+}
+
+func (s *prosConsTestSuite) Test_NegativeSmoothing() {
+	problem := s.problem()
+	problem.ProsCons = &domain.ProsCons{Smoothing: kit.Float64Ptr(-1)}
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionSmoothingInvalid)
+}
+
+func (s *prosConsTestSuite) Test_NegativeImportance() {
+	problem := s.problem()
+	problem.Options[0].Pros[0].Importance = -1
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityImportanceInvalid)
+}
+
+func (s *prosConsTestSuite) Test_ProbabilityOutOfRange() {
+	problem := s.problem()
+	problem.Options[0].Cons[0].Probability = 1.5
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionQualityProbabilityInvalid)
+}
@@ -0,0 +1,102 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"sort"
+	"sync"
+)
+
+type methodRegistryImpl struct {
+	sync.RWMutex
+	methods map[string]domain.DecisionMethod
+}
+
+func NewMethodRegistry() domain.MethodRegistry {
+	return &methodRegistryImpl{
+		methods: map[string]domain.DecisionMethod{},
+	}
+}
+
+// BuiltInMethods returns all methods implemented by the service
+func BuiltInMethods() []domain.DecisionMethod {
+	return []domain.DecisionMethod{NewProsConsMethod(), NewAhpMethod(), NewTopsisMethod()}
+}
+
+func (r *methodRegistryImpl) l() kit.CLogger {
+	return decision.L().Cmp("method-registry")
+}
+
+func (r *methodRegistryImpl) Register(ctx context.Context, method domain.DecisionMethod) error {
+	if method == nil || method.Code() == "" {
+		return errors.ErrDecisionMethodEmpty(ctx)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.methods[method.Code()]; ok {
+		return errors.ErrDecisionMethodAlreadyRegistered(ctx, method.Code())
+	}
+	r.methods[method.Code()] = method
+
+	r.l().C(ctx).Mth("register").F(kit.KV{"method": method.Code()}).Dbg("registered")
+
+	return nil
+}
+
+func (r *methodRegistryImpl) Get(ctx context.Context, code string) (domain.DecisionMethod, error) {
+	if code == "" {
+		code = domain.MethodProsCons
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	m, ok := r.methods[code]
+	if !ok {
+		return nil, errors.ErrDecisionMethodInvalid(ctx, code)
+	}
+	return m, nil
+}
+
+func (r *methodRegistryImpl) List(ctx context.Context) []domain.DecisionMethod {
+	r.RLock()
+	defer r.RUnlock()
+
+	res := make([]domain.DecisionMethod, 0, len(r.methods))
+	for _, m := range r.methods {
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Code() < res[j].Code() })
+	return res
+}
+
+// validateMethod checks the method is registered and sets the default one if empty
+func validateMethod(ctx context.Context, registry domain.MethodRegistry, problem *domain.Problem) error {
+	m, err := registry.Get(ctx, problem.Method)
+	if err != nil {
+		return err
+	}
+	problem.Method = m.Code()
+	return nil
+}
+
+func objectParam(description string, properties map[string]*domain.ParamSchema, required ...string) *domain.ParamSchema {
+	return &domain.ParamSchema{Type: domain.ParamTypeObject, Description: description, Properties: properties, Required: required}
+}
+
+func arrayParam(description string, items *domain.ParamSchema) *domain.ParamSchema {
+	return &domain.ParamSchema{Type: domain.ParamTypeArray, Description: description, Items: items}
+}
+
+func numberParam(description string, min, max *float64) *domain.ParamSchema {
+	return &domain.ParamSchema{Type: domain.ParamTypeNumber, Description: description, Minimum: min, Maximum: max}
+}
+
+func stringParam(description string, enum ...string) *domain.ParamSchema {
+	return &domain.ParamSchema{Type: domain.ParamTypeString, Description: description, Enum: enum}
+}
@@ -0,0 +1,79 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/mocks"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+// builtInRegistry creates a registry with all built-in methods registered
+func builtInRegistry(ctx context.Context) domain.MethodRegistry {
+	r := NewMethodRegistry()
+	for _, m := range BuiltInMethods() {
+		_ = r.Register(ctx, m)
+	}
+	return r
+}
+
+type registryTestSuite struct {
+	kit.Suite
+	registry domain.MethodRegistry
+}
+
+func (s *registryTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *registryTestSuite) SetupTest() {
+	s.registry = builtInRegistry(s.Ctx)
+}
+
+func TestRegistrySuite(t *testing.T) {
+	suite.Run(t, new(registryTestSuite))
+}
+
+func (s *registryTestSuite) Test_Get_Default() {
+	m, err := s.registry.Get(s.Ctx, "")
+	s.NoError(err)
+	s.Equal(domain.MethodProsCons, m.Code())
+}
+
+func (s *registryTestSuite) Test_Get_NotRegistered() {
+	_, err := s.registry.Get(s.Ctx, "unknown")
+	s.AssertAppErr(err, errors.ErrCodeDecisionMethodInvalid)
+}
+
+func (s *registryTestSuite) Test_Register_Custom() {
+	m := &mocks.DecisionMethod{}
+	m.On("Code").Return("custom")
+	s.NoError(s.registry.Register(s.Ctx, m))
+	r, err := s.registry.Get(s.Ctx, "custom")
+	s.NoError(err)
+	s.Equal(m, r)
+	s.Len(s.registry.List(s.Ctx), 4)
+}
+
+func (s *registryTestSuite) Test_Register_Duplicate() {
+	s.AssertAppErr(s.registry.Register(s.Ctx, NewAhpMethod()), errors.ErrCodeDecisionMethodAlreadyRegistered)
+}
+
+func (s *registryTestSuite) Test_Register_Empty() {
+	s.AssertAppErr(s.registry.Register(s.Ctx, nil), errors.ErrCodeDecisionMethodEmpty)
+}
+
+func (s *registryTestSuite) Test_List_Described() {
+	methods := s.registry.List(s.Ctx)
+	s.Len(methods, 3)
+	s.Equal(domain.MethodAhp, methods[0].Code())
+	for _, m := range methods {
+		d := m.Describe()
+		s.Equal(m.Code(), d.Code)
+		s.NotEmpty(d.Name)
+		s.NotEmpty(d.Params)
+	}
+}
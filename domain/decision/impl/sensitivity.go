@@ -0,0 +1,240 @@
+package impl
+
+import (
+	"context"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"math"
+	"sort"
+)
+
+const (
+	defaultSensitivityRange = 0.2
+	// sensitivitySteps number of steps to scan a param domain for break-even
+	sensitivitySteps = 100
+	// sensitivityBisections number of bisections to refine a break-even value
+	sensitivityBisections = 30
+	// sensitivityImportanceFactor importance domain upper bound as a multiple of the max importance of the problem
+	sensitivityImportanceFactor = 10
+	// maxSensitivityQualities each quality param is scanned separately, so the number of qualities is limited
+	maxSensitivityQualities = 100
+	// maxSensitivityEvaluations limits the total number of ratings calculated by the analysis
+	maxSensitivityEvaluations = 100000
+)
+
+func (p *decisionServiceImpl) AnalyzeSensitivity(ctx context.Context, problem *domain.Problem, params *domain.SensitivityParams) (*domain.SensitivityResult, error) {
+	p.l().C(ctx).Mth("sensitivity").Dbg()
+
+	if problem == nil {
+		return nil, errors.ErrDecisionProblemEmpty(ctx)
+	}
+
+	rng := defaultSensitivityRange
+	if params != nil && params.Range != 0 {
+		if params.Range < 0 || params.Range > 1 {
+			return nil, errors.ErrDecisionSensitivityRangeInvalid(ctx)
+		}
+		rng = params.Range
+	}
+
+	if err := validateIds(ctx, problem); err != nil {
+		return nil, err
+	}
+	setIds(problem)
+
+	method, err := p.methodRegistry.Get(ctx, problem.Method)
+	if err != nil {
+		return nil, err
+	}
+	// only pros-cons rating depends on importance and probability of qualities
+	if method.Code() != domain.MethodProsCons {
+		return nil, errors.ErrDecisionSensitivityMethodUnsupported(ctx, method.Code())
+	}
+	count := 0
+	for _, op := range problem.Options {
+		count += len(op.Pros) + len(op.Cons)
+	}
+	if count > maxSensitivityQualities {
+		return nil, errors.ErrDecisionSensitivityQualitiesExceeded(ctx, maxSensitivityQualities)
+	}
+
+	ctx, done, err := p.computations.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	a := &sensitivityAnalyzer{method: method, problem: problem}
+	return a.analyze(ctx, rng)
+}
+
+// sensitivityAnalyzer perturbs quality params of the problem in place and rates options by the method
+type sensitivityAnalyzer struct {
+	method      domain.DecisionMethod
+	problem     *domain.Problem
+	winnerId    string
+	evaluations int
+}
+
+func (a *sensitivityAnalyzer) analyze(ctx context.Context, rng float64) (*domain.SensitivityResult, error) {
+
+	base, err := a.calculate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.winnerId = a.leader(base.OptionsRating)
+
+	r := &domain.SensitivityResult{
+		WinnerId:      a.winnerId,
+		OptionsRating: base.OptionsRating,
+	}
+
+	maxImportance := 0.0
+	for _, op := range a.problem.Options {
+		for _, q := range qualities(op) {
+			maxImportance = math.Max(maxImportance, q.Importance)
+		}
+	}
+	if maxImportance == 0 {
+		maxImportance = 1
+	}
+
+	for _, op := range a.problem.Options {
+		for _, kq := range []struct {
+			kind      string
+			qualities []*domain.Quality
+		}{{domain.QualityKindPro, op.Pros}, {domain.QualityKindCon, op.Cons}} {
+			for _, q := range kq.qualities {
+				imp, err := a.param(ctx, op, q, domain.ParamImportance, &q.Importance, maxImportance*sensitivityImportanceFactor, rng)
+				if err != nil {
+					return nil, err
+				}
+				prob, err := a.param(ctx, op, q, domain.ParamProbability, &q.Probability, 1, rng)
+				if err != nil {
+					return nil, err
+				}
+				imp.Kind, prob.Kind = kq.kind, kq.kind
+				r.Qualities = append(r.Qualities, imp, prob)
+			}
+		}
+	}
+
+	// tornado chart order
+	sort.SliceStable(r.Qualities, func(i, j int) bool { return r.Qualities[i].Swing > r.Qualities[j].Swing })
+
+	return r, nil
+}
+
+// param analyzes a single param in domain [0, upper]
+func (a *sensitivityAnalyzer) param(ctx context.Context, op *domain.Option, q *domain.Quality, name string, value *float64, upper, rng float64) (*domain.QualitySensitivity, error) {
+
+	v := *value
+	r := &domain.QualitySensitivity{
+		OptionId:  op.Id,
+		QualityId: q.Id,
+		Param:     name,
+		Value:     v,
+		Low:       kit.Round10000(math.Max(v*(1-rng), 0)),
+		High:      kit.Round10000(math.Min(v*(1+rng), upper)),
+	}
+
+	low, err := a.rate(ctx, value, r.Low)
+	if err != nil {
+		return nil, err
+	}
+	high, err := a.rate(ctx, value, r.High)
+	if err != nil {
+		return nil, err
+	}
+	r.RatingLow, r.RatingHigh = low.OptionsRating[op.Id], high.OptionsRating[op.Id]
+	r.Swing = kit.Round10000(math.Abs(r.RatingHigh - r.RatingLow))
+
+	if r.BreakEvenDown, err = a.breakEven(ctx, value, v, 0); err != nil {
+		return nil, err
+	}
+	if r.BreakEvenUp, err = a.breakEven(ctx, value, v, math.Max(upper, v)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// breakEven scans values from the current one to the bound and finds the nearest value at which the winner changes
+func (a *sensitivityAnalyzer) breakEven(ctx context.Context, value *float64, from, to float64) (*domain.BreakEven, error) {
+	if from == to {
+		return nil, nil
+	}
+	step := (to - from) / sensitivitySteps
+	prev := from
+	for i := 1; i <= sensitivitySteps; i++ {
+		x := from + step*float64(i)
+		winnerId, err := a.winner(ctx, value, x)
+		if err != nil {
+			return nil, err
+		}
+		if winnerId == a.winnerId {
+			prev = x
+			continue
+		}
+		// refine by bisection, prev keeps the winner, x changes it
+		for j := 0; j < sensitivityBisections; j++ {
+			mid := (prev + x) / 2
+			midWinnerId, err := a.winner(ctx, value, mid)
+			if err != nil {
+				return nil, err
+			}
+			if midWinnerId == a.winnerId {
+				prev = mid
+			} else {
+				x, winnerId = mid, midWinnerId
+			}
+		}
+		return &domain.BreakEven{Value: kit.Round10000(x), WinnerId: winnerId}, nil
+	}
+	return nil, nil
+}
+
+// rate rates options with the param set to x, then restores the param
+func (a *sensitivityAnalyzer) rate(ctx context.Context, value *float64, x float64) (domain.DecisionResult, error) {
+	v := *value
+	defer func() { *value = v }()
+	*value = x
+	return a.calculate(ctx)
+}
+
+// calculate rates options of the problem, the analysis is stopped if the context is done or evaluations are exhausted
+func (a *sensitivityAnalyzer) calculate(ctx context.Context) (domain.DecisionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.DecisionResult{}, err
+	}
+	if a.evaluations >= maxSensitivityEvaluations {
+		return domain.DecisionResult{}, errors.ErrDecisionSensitivityEvaluationsExceeded(ctx, maxSensitivityEvaluations)
+	}
+	a.evaluations++
+	return a.method.Calculate(ctx, a.problem)
+}
+
+func (a *sensitivityAnalyzer) winner(ctx context.Context, value *float64, x float64) (string, error) {
+	res, err := a.rate(ctx, value, x)
+	if err != nil {
+		return "", err
+	}
+	return a.leader(res.OptionsRating), nil
+}
+
+func (a *sensitivityAnalyzer) leader(rating map[string]float64) string {
+	return leader(a.problem, rating)
+}
+
+// leader returns the top-ranked option, the first one in case of tie
+func leader(problem *domain.Problem, rating map[string]float64) string {
+	var r string
+	max := math.Inf(-1)
+	for _, op := range problem.Options {
+		if v, ok := rating[op.Id]; ok && v > max {
+			r, max = op.Id, v
+		}
+	}
+	return r
+}
@@ -0,0 +1,84 @@
+package impl
+
+import (
+	"context"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+)
+
+func (s *decisionTestSuite) Test_AnalyzeSensitivity() {
+	problem := s.problem()
+	r, err := s.svc.AnalyzeSensitivity(s.Ctx, problem, nil)
+	s.NoError(err)
+	first, second := problem.Options[0], problem.Options[1]
+	s.Equal(first.Id, r.WinnerId)
+	s.Len(r.Qualities, 8)
+
+	// ordered by swing
+	for i := 1; i < len(r.Qualities); i++ {
+		s.True(r.Qualities[i-1].Swing >= r.Qualities[i].Swing)
+	}
+
+	var pro *domain.QualitySensitivity
+	for _, q := range r.Qualities {
+		if q.QualityId == first.Pros[0].Id && q.Param == domain.ParamImportance {
+			pro = q
+		}
+	}
+	s.NotNil(pro)
+	s.Equal(domain.QualityKindPro, pro.Kind)
+	s.Equal(8.0, pro.Low)
+	s.Equal(12.0, pro.High)
+	s.Equal(1.6, pro.RatingLow)
+	s.Equal(2.4, pro.RatingHigh)
+	s.Equal(0.8, pro.Swing)
+	s.NotNil(pro.BreakEvenDown)
+	s.InDelta(2.475, pro.BreakEvenDown.Value, 0.01)
+	s.Equal(second.Id, pro.BreakEvenDown.WinnerId)
+	// increasing pro of the winner never changes the winner
+	s.Nil(pro.BreakEvenUp)
+
+	// problem isn't changed by analysis
+	s.Equal(10.0, first.Pros[0].Importance)
+}
+
+func (s *decisionTestSuite) Test_AnalyzeSensitivity_InvalidRange() {
+	_, err := s.svc.AnalyzeSensitivity(s.Ctx, s.problem(), &domain.SensitivityParams{Range: 2})
+	s.AssertAppErr(err, errors.ErrCodeDecisionSensitivityRangeInvalid)
+}
+
+func (s *decisionTestSuite) Test_AnalyzeSensitivity_Empty() {
+	_, err := s.svc.AnalyzeSensitivity(s.Ctx, nil, nil)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemEmpty)
+}
+
+func (s *decisionTestSuite) Test_AnalyzeSensitivity_MethodUnsupported() {
+	problem := s.problem()
+	problem.Method = domain.MethodAhp
+	_, err := s.svc.AnalyzeSensitivity(s.Ctx, problem, nil)
+	s.AssertAppErr(err, errors.ErrCodeDecisionSensitivityMethodUnsupported)
+}
+
+func (s *decisionTestSuite) Test_AnalyzeSensitivity_QualitiesExceeded() {
+	problem := s.problem()
+	for i := 0; i < maxSensitivityQualities; i++ {
+		problem.Options[0].Pros = append(problem.Options[0].Pros, &domain.Quality{Name: "pro", Importance: 1, Probability: 1})
+	}
+	_, err := s.svc.AnalyzeSensitivity(s.Ctx, problem, nil)
+	s.AssertAppErr(err, errors.ErrCodeDecisionSensitivityQualitiesExceeded)
+}
+
+func (s *decisionTestSuite) Test_AnalyzeSensitivity_EvaluationsExceeded() {
+	problem := s.problem()
+	setIds(problem)
+	a := &sensitivityAnalyzer{method: NewProsConsMethod(), problem: problem, evaluations: maxSensitivityEvaluations - 10}
+	_, err := a.analyze(s.Ctx, defaultSensitivityRange)
+	s.AssertAppErr(err, errors.ErrCodeDecisionSensitivityEvaluationsExceeded)
+}
+
+func (s *decisionTestSuite) Test_AnalyzeSensitivity_Cancelled() {
+	ctx, cancel := context.WithCancel(s.Ctx)
+	cancel()
+	_, err := s.svc.AnalyzeSensitivity(ctx, s.problem(), nil)
+	s.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,233 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/goroutine"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	defaultSimulationIterations = 1000
+	maxSimulationIterations     = 100000
+	// simulationWorkers iterations are split among the fixed number of workers,
+	// so that results are reproducible for the same seed regardless of hardware
+	simulationWorkers = 8
+)
+
+// simulate runs Monte Carlo simulation of the problem
+// on each iteration qualities with distributions are sampled and options are rated by the method
+func simulate(ctx context.Context, method domain.DecisionMethod, problem *domain.Problem) (*domain.SimulationResult, error) {
+
+	// only importance and probability of qualities are random, they affect pros-cons rating only
+	if method.Code() != domain.MethodProsCons {
+		return nil, errors.ErrDecisionSimulationMethodUnsupported(ctx, method.Code())
+	}
+
+	settings := problem.Simulation
+	iterations := settings.Iterations
+	if iterations == 0 {
+		iterations = defaultSimulationIterations
+	}
+	if iterations < 0 || iterations > maxSimulationIterations {
+		return nil, errors.ErrDecisionSimulationIterationsInvalid(ctx, maxSimulationIterations)
+	}
+	for _, op := range problem.Options {
+		for _, q := range qualities(op) {
+			if err := validateDistribution(ctx, q.Id, q.ImportanceDist); err != nil {
+				return nil, err
+			}
+			if err := validateDistribution(ctx, q.Id, q.ProbabilityDist); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// default smoothing depends on sampled values (whether some option has zero cons),
+	// so it's resolved once for all iterations, otherwise ratings of iterations aren't comparable
+	if problem.ProsCons == nil || problem.ProsCons.Smoothing == nil {
+		pinned := *problem
+		pinned.ProsCons = &domain.ProsCons{Smoothing: kit.Float64Ptr(simulationSmoothing(problem))}
+		if problem.ProsCons != nil {
+			pinned.ProsCons.Scoring = problem.ProsCons.Scoring
+		}
+		problem = &pinned
+	}
+	seed := time.Now().UnixNano()
+	if settings.Seed != nil {
+		seed = *settings.Seed
+	}
+
+	// ratings[iteration][option]
+	ratings := make([][]float64, iterations)
+
+	workers := simulationWorkers
+	if iterations < workers {
+		workers = iterations
+	}
+	chunk := (iterations + workers - 1) / workers
+
+	eg := goroutine.NewGroup(ctx).WithLoggerFn(decision.LF()).Cmp("decision-svc").Mth("simulate")
+	for w := 0; w < workers; w++ {
+		from, to := w*chunk, (w+1)*chunk
+		if to > iterations {
+			to = iterations
+		}
+		rnd := rand.New(rand.NewSource(seed + int64(w)))
+		eg.Go(func() error {
+			for i := from; i < to; i++ {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				res, err := method.Calculate(ctx, sampleProblem(rnd, problem))
+				if err != nil {
+					return err
+				}
+				ratings[i] = make([]float64, len(problem.Options))
+				for j, op := range problem.Options {
+					ratings[i][j] = res.OptionsRating[op.Id]
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return simulationStats(problem, ratings, seed), nil
+}
+
+// simulationSmoothing resolves default smoothing of pros-cons method for all iterations of simulation
+// smoothing is applied to ratio if cons of some option are zero or can be sampled to zero
+func simulationSmoothing(problem *domain.Problem) float64 {
+	if problem.ProsCons != nil && problem.ProsCons.Scoring == domain.ScoringNet {
+		return 0
+	}
+	for _, op := range problem.Options {
+		zero := true
+		for _, con := range op.Cons {
+			if !mayBeZero(con.Importance, con.ImportanceDist) && !mayBeZero(con.Probability, con.ProbabilityDist) {
+				zero = false
+				break
+			}
+		}
+		if zero {
+			return defaultRatioSmoothing
+		}
+	}
+	return 0
+}
+
+// mayBeZero checks if the value or its samples (clamped to non-negative) can be zero
+func mayBeZero(value float64, d *domain.Distribution) bool {
+	if d == nil {
+		return value == 0
+	}
+	if d.Type == domain.DistNormal {
+		return d.StdDev > 0 || d.Mean <= 0
+	}
+	return d.Min <= 0
+}
+
+// sampleProblem copies the problem with qualities sampled from their distributions
+func sampleProblem(rnd *rand.Rand, problem *domain.Problem) *domain.Problem {
+	r := *problem
+	r.Options = make([]*domain.Option, 0, len(problem.Options))
+	for _, op := range problem.Options {
+		o := &domain.Option{Id: op.Id, Name: op.Name}
+		o.Pros = sampleQualities(rnd, op.Pros)
+		o.Cons = sampleQualities(rnd, op.Cons)
+		r.Options = append(r.Options, o)
+	}
+	return &r
+}
+
+func sampleQualities(rnd *rand.Rand, qualities []*domain.Quality) []*domain.Quality {
+	r := make([]*domain.Quality, 0, len(qualities))
+	for _, q := range qualities {
+		s := &domain.Quality{Id: q.Id, Name: q.Name, Importance: q.Importance, Probability: q.Probability}
+		if q.ImportanceDist != nil {
+			s.Importance = math.Max(sample(rnd, q.ImportanceDist), 0)
+		}
+		if q.ProbabilityDist != nil {
+			s.Probability = math.Min(math.Max(sample(rnd, q.ProbabilityDist), 0), 1)
+		}
+		r = append(r, s)
+	}
+	return r
+}
+
+// simulationStats calculates rating statistics of each option
+func simulationStats(problem *domain.Problem, ratings [][]float64, seed int64) *domain.SimulationResult {
+
+	r := &domain.SimulationResult{
+		Iterations: len(ratings),
+		Seed:       seed,
+		Options:    make(map[string]*domain.OptionSimulation, len(problem.Options)),
+	}
+
+	// probability to rank first, ties share the iteration
+	best := make([]float64, len(problem.Options))
+	for _, it := range ratings {
+		max := math.Inf(-1)
+		var leaders []int
+		for j, v := range it {
+			switch {
+			case v > max:
+				max, leaders = v, []int{j}
+			case v == max:
+				leaders = append(leaders, j)
+			}
+		}
+		for _, j := range leaders {
+			best[j] += 1 / float64(len(leaders))
+		}
+	}
+
+	n := float64(len(ratings))
+	for j, op := range problem.Options {
+		values := make([]float64, len(ratings))
+		sum := 0.0
+		for i := range ratings {
+			values[i] = ratings[i][j]
+			sum += values[i]
+		}
+		sort.Float64s(values)
+		mean := sum / n
+		variance := 0.0
+		for _, v := range values {
+			variance += (v - mean) * (v - mean)
+		}
+		r.Options[op.Id] = &domain.OptionSimulation{
+			Mean:            kit.Round10000(mean),
+			StdDev:          kit.Round10000(math.Sqrt(variance / n)),
+			P5:              kit.Round10000(percentile(values, 5)),
+			P25:             kit.Round10000(percentile(values, 25)),
+			P50:             kit.Round10000(percentile(values, 50)),
+			P75:             kit.Round10000(percentile(values, 75)),
+			P95:             kit.Round10000(percentile(values, 95)),
+			ProbabilityBest: kit.Round10000(best[j] / n),
+		}
+	}
+	return r
+}
+
+// percentile calculates percentile of sorted values with linear interpolation
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
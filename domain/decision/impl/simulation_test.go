@@ -0,0 +1,144 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"testing"
+)
+
+type simulationTestSuite struct {
+	kit.Suite
+	method domain.DecisionMethod
+}
+
+func (s *simulationTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+	s.method = NewProsConsMethod()
+}
+
+func TestSimulationSuite(t *testing.T) {
+	suite.Run(t, new(simulationTestSuite))
+}
+
+func (s *simulationTestSuite) problem() *domain.Problem {
+	return &domain.Problem{
+		Id:   kit.NewId(),
+		Name: "problem",
+		Options: []*domain.Option{
+			{
+				Id:   kit.NewId(),
+				Name: "first",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 10, Probability: 0.5,
+					ProbabilityDist: &domain.Distribution{Type: domain.DistUniform, Min: 0.2, Max: 0.8}}},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 5, Probability: 0.5,
+					ImportanceDist: &domain.Distribution{Type: domain.DistTriangular, Min: 3, Mode: 5, Max: 10}}},
+			},
+			{
+				Id:   kit.NewId(),
+				Name: "second",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 8, Probability: 0.5,
+					ImportanceDist: &domain.Distribution{Type: domain.DistNormal, Mean: 8, StdDev: 2}}},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 5, Probability: 0.5,
+					ProbabilityDist: &domain.Distribution{Type: domain.DistBeta, Alpha: 2, Beta: 5}}},
+			},
+		},
+		Simulation: &domain.Simulation{Iterations: 2000, Seed: kit.Int64Ptr(42)},
+	}
+}
+
+func (s *simulationTestSuite) Test_Reproducible() {
+	problem := s.problem()
+	r1, err := simulate(s.Ctx, s.method, problem)
+	s.NoError(err)
+	r2, err := simulate(s.Ctx, s.method, problem)
+	s.NoError(err)
+	s.Equal(r1, r2)
+	s.Equal(2000, r1.Iterations)
+	s.Equal(int64(42), r1.Seed)
+	total := 0.0
+	for _, op := range problem.Options {
+		o := r1.Options[op.Id]
+		s.True(o.P5 <= o.P25 && o.P25 <= o.P50 && o.P50 <= o.P75 && o.P75 <= o.P95)
+		s.True(o.StdDev > 0)
+		total += o.ProbabilityBest
+	}
+	s.InDelta(1.0, total, 1e-3)
+}
+
+func (s *simulationTestSuite) Test_NoDistributions() {
+	problem := s.problem()
+	for _, op := range problem.Options {
+		for _, q := range qualities(op) {
+			q.ImportanceDist, q.ProbabilityDist = nil, nil
+		}
+	}
+	r, err := simulate(s.Ctx, s.method, problem)
+	s.NoError(err)
+	first, second := r.Options[problem.Options[0].Id], r.Options[problem.Options[1].Id]
+	s.Equal(2.0, first.Mean)
+	s.Equal(0.0, first.StdDev)
+	s.Equal(1.0, first.ProbabilityBest)
+	s.Equal(0.0, second.ProbabilityBest)
+}
+
+func (s *simulationTestSuite) Test_SmoothingPinned() {
+	// cons of the first option are sampled to zero in some iterations, so smoothing is applied in all of them
+	problem := s.problem()
+	first, second := problem.Options[0], problem.Options[1]
+	first.Pros[0].ProbabilityDist = nil
+	first.Cons[0].ImportanceDist = &domain.Distribution{Type: domain.DistNormal, Mean: 1, StdDev: 2}
+	second.Pros[0].ImportanceDist, second.Cons[0].ProbabilityDist = nil, nil
+	r, err := simulate(s.Ctx, s.method, problem)
+	s.NoError(err)
+	// the second option has no distributions, so its rating is the same in every iteration
+	o := r.Options[second.Id]
+	s.Equal(0.0, o.StdDev)
+	s.Equal(1.43, o.Mean)
+	s.True(r.Options[first.Id].StdDev > 0)
+}
+
+func (s *simulationTestSuite) Test_MethodUnsupported() {
+	_, err := simulate(s.Ctx, NewAhpMethod(), s.problem())
+	s.AssertAppErr(err, errors.ErrCodeDecisionSimulationMethodUnsupported)
+}
+
+func (s *simulationTestSuite) Test_InvalidDistribution() {
+	problem := s.problem()
+	problem.Options[0].Pros[0].ProbabilityDist = &domain.Distribution{Type: domain.DistUniform, Min: 1, Max: 0}
+	_, err := simulate(s.Ctx, s.method, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionDistributionInvalid)
+}
+
+func (s *simulationTestSuite) Test_TooManyIterations() {
+	problem := s.problem()
+	problem.Simulation.Iterations = maxSimulationIterations + 1
+	_, err := simulate(s.Ctx, s.method, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionSimulationIterationsInvalid)
+}
+
+func (s *simulationTestSuite) Test_Sample_Bounds() {
+	rnd := rand.New(rand.NewSource(1))
+	dists := []*domain.Distribution{
+		{Type: domain.DistUniform, Min: 1, Max: 2},
+		{Type: domain.DistTriangular, Min: 1, Mode: 1.2, Max: 2},
+		{Type: domain.DistBeta, Alpha: 0.5, Beta: 0.5, Min: 1, Max: 2},
+	}
+	for _, d := range dists {
+		for i := 0; i < 1000; i++ {
+			v := sample(rnd, d)
+			s.True(v >= 1 && v <= 2, d.Type)
+		}
+	}
+}
+
+func (s *simulationTestSuite) Test_Percentile() {
+	values := []float64{1, 2, 3, 4, 5}
+	s.Equal(1.0, percentile(values, 0))
+	s.Equal(3.0, percentile(values, 50))
+	s.Equal(4.5, percentile(values, 87.5))
+	s.Equal(5.0, percentile(values, 100))
+}
@@ -0,0 +1,241 @@
+package impl
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"math"
+	"strconv"
+)
+
+// fieldValidators checks option field values by field type
+var fieldValidators = map[string]func(string) bool{
+	domain.FieldTypeString: func(string) bool { return true },
+	domain.FieldTypeNumber: func(v string) bool {
+		f, err := strconv.ParseFloat(v, 64)
+		return err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+	},
+	domain.FieldTypeUrl:          kit.IsUrlValid,
+	domain.FieldTypeEmail:        kit.IsEmailValid,
+	domain.FieldTypePhone:        kit.IsPhoneValid,
+	domain.FieldTypeVin:          kit.IsVINValid,
+	domain.FieldTypeCarRegNumber: kit.IsCarRegNumberValid,
+}
+
+type templateServiceImpl struct {
+	problemService  domain.ProblemService
+	templateStorage domain.TemplateStorage
+}
+
+func NewTemplateService(problemService domain.ProblemService, templateStorage domain.TemplateStorage) domain.TemplateService {
+	return &templateServiceImpl{
+		problemService:  problemService,
+		templateStorage: templateStorage,
+	}
+}
+
+func (s *templateServiceImpl) l() kit.CLogger {
+	return decision.L().Cmp("template-svc")
+}
+
+func (s *templateServiceImpl) CreateTemplate(ctx context.Context, userId string, template *domain.Template) (*domain.Template, error) {
+	s.l().C(ctx).Mth("create").Dbg()
+
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	template.Id = kit.NewId()
+	template.UserId = userId
+	template.CreatedAt, template.UpdatedAt = kit.Now(), kit.Now()
+
+	if err := s.templateStorage.CreateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *templateServiceImpl) UpdateTemplate(ctx context.Context, userId string, template *domain.Template) (*domain.Template, error) {
+	s.l().C(ctx).Mth("update").Dbg()
+
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.getUserTemplate(ctx, userId, template.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored.Name = template.Name
+	stored.Description = template.Description
+	stored.Category = template.Category
+	stored.Criteria = template.Criteria
+	stored.OptionFields = template.OptionFields
+	stored.UpdatedAt = kit.Now()
+
+	if err := s.templateStorage.UpdateTemplate(ctx, stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (s *templateServiceImpl) GetTemplate(ctx context.Context, userId, templateId string) (*domain.Template, error) {
+	s.l().C(ctx).Mth("get").Dbg()
+
+	template, err := s.getTemplate(ctx, templateId)
+	if err != nil {
+		return nil, err
+	}
+	if !template.System() && template.UserId != userId {
+		return nil, errors.ErrDecisionTemplateForbidden(ctx, templateId)
+	}
+	return template, nil
+}
+
+func (s *templateServiceImpl) SearchTemplates(ctx context.Context, criteria *domain.TemplateSearchCriteria) (*domain.TemplateSearchResponse, error) {
+	s.l().C(ctx).Mth("search").Dbg()
+
+	if criteria.Size <= 0 {
+		criteria.Size = defaultPageSize
+	}
+	if criteria.Size > maxPageSize {
+		criteria.Size = maxPageSize
+	}
+	if criteria.Index <= 0 {
+		criteria.Index = 1
+	}
+
+	return s.templateStorage.SearchTemplates(ctx, criteria)
+}
+
+func (s *templateServiceImpl) DeleteTemplate(ctx context.Context, userId, templateId string) error {
+	s.l().C(ctx).Mth("delete").Dbg()
+
+	if _, err := s.getUserTemplate(ctx, userId, templateId); err != nil {
+		return err
+	}
+
+	return s.templateStorage.DeleteTemplate(ctx, templateId)
+}
+
+func (s *templateServiceImpl) CreateProblemFromTemplate(ctx context.Context, userId, templateId string, problem *domain.Problem) (*domain.Problem, error) {
+	s.l().C(ctx).Mth("create-problem").Dbg()
+
+	if problem == nil {
+		return nil, errors.ErrDecisionProblemEmpty(ctx)
+	}
+
+	template, err := s.GetTemplate(ctx, userId, templateId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range problem.Options {
+		if op == nil || op.Name == "" {
+			return nil, errors.ErrDecisionOptionNameEmpty(ctx)
+		}
+		if err := s.validateOptionFields(ctx, template, op); err != nil {
+			return nil, err
+		}
+		op.Pros, op.Cons = nil, nil
+		for _, c := range template.Criteria {
+			q := &domain.Quality{Name: c.Name, Importance: c.Importance, Probability: c.Probability}
+			if c.Kind == domain.QualityKindPro {
+				op.Pros = append(op.Pros, q)
+			} else {
+				op.Cons = append(op.Cons, q)
+			}
+		}
+	}
+
+	return s.problemService.CreateProblem(ctx, userId, problem)
+}
+
+// getTemplate retrieves the template and checks it exists
+func (s *templateServiceImpl) getTemplate(ctx context.Context, templateId string) (*domain.Template, error) {
+	if err := kit.ValidateUUIDs(templateId); err != nil {
+		return nil, errors.ErrDecisionTemplateInvalidId(ctx, templateId)
+	}
+	template, err := s.templateStorage.GetTemplate(ctx, templateId)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, errors.ErrDecisionTemplateNotFound(ctx, templateId)
+	}
+	return template, nil
+}
+
+// getUserTemplate retrieves the template and checks it belongs to the user, so that it can be modified
+func (s *templateServiceImpl) getUserTemplate(ctx context.Context, userId, templateId string) (*domain.Template, error) {
+	template, err := s.getTemplate(ctx, templateId)
+	if err != nil {
+		return nil, err
+	}
+	if template.System() || template.UserId != userId {
+		return nil, errors.ErrDecisionTemplateForbidden(ctx, templateId)
+	}
+	return template, nil
+}
+
+func (s *templateServiceImpl) validateTemplate(ctx context.Context, template *domain.Template) error {
+	if template == nil || template.Name == "" {
+		return errors.ErrDecisionTemplateNameEmpty(ctx)
+	}
+	if len(template.Criteria) == 0 {
+		return errors.ErrDecisionTemplateCriteriaEmpty(ctx)
+	}
+	criteria := make(map[string]struct{}, len(template.Criteria))
+	for _, c := range template.Criteria {
+		if c == nil {
+			return errors.ErrDecisionTemplateCriterionInvalid(ctx, "")
+		}
+		if _, ok := criteria[c.Name]; ok || c.Name == "" ||
+			(c.Kind != domain.QualityKindPro && c.Kind != domain.QualityKindCon) ||
+			validateQualityValues(ctx, &domain.Quality{Importance: c.Importance, Probability: c.Probability}) != nil {
+			return errors.ErrDecisionTemplateCriterionInvalid(ctx, c.Name)
+		}
+		criteria[c.Name] = struct{}{}
+	}
+	fields := make(map[string]struct{}, len(template.OptionFields))
+	for _, f := range template.OptionFields {
+		if f == nil {
+			return errors.ErrDecisionTemplateFieldInvalid(ctx, "", "")
+		}
+		if f.Type == "" {
+			f.Type = domain.FieldTypeString
+		}
+		if _, ok := fields[f.Name]; ok || f.Name == "" || fieldValidators[f.Type] == nil {
+			return errors.ErrDecisionTemplateFieldInvalid(ctx, f.Name, f.Type)
+		}
+		fields[f.Name] = struct{}{}
+	}
+	return nil
+}
+
+// validateOptionFields checks option fields conform to the template
+func (s *templateServiceImpl) validateOptionFields(ctx context.Context, template *domain.Template, option *domain.Option) error {
+	defined := make(map[string]struct{}, len(template.OptionFields))
+	for _, f := range template.OptionFields {
+		defined[f.Name] = struct{}{}
+		v, ok := option.Fields[f.Name]
+		if !ok || v == "" {
+			if f.Required {
+				return errors.ErrDecisionOptionFieldRequired(ctx, option.Name, f.Name)
+			}
+			continue
+		}
+		if validate, ok := fieldValidators[f.Type]; ok && !validate(v) {
+			return errors.ErrDecisionOptionFieldInvalid(ctx, option.Name, f.Name, f.Type)
+		}
+	}
+	for name := range option.Fields {
+		if _, ok := defined[name]; !ok {
+			return errors.ErrDecisionOptionFieldUnknown(ctx, option.Name, name)
+		}
+	}
+	return nil
+}
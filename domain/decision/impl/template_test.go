@@ -0,0 +1,182 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type templateTestSuite struct {
+	kit.Suite
+	problemStorage  *mocks.ProblemStorage
+	templateStorage *mocks.TemplateStorage
+	svc             domain.TemplateService
+}
+
+func (s *templateTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *templateTestSuite) SetupTest() {
+	s.problemStorage = &mocks.ProblemStorage{}
+	s.templateStorage = &mocks.TemplateStorage{}
+	s.svc = NewTemplateService(NewProblemService(builtInRegistry(s.Ctx), s.problemStorage), s.templateStorage)
+}
+
+func TestTemplateSuite(t *testing.T) {
+	suite.Run(t, new(templateTestSuite))
+}
+
+func (s *templateTestSuite) template(userId string) *domain.Template {
+	return &domain.Template{
+		Id:       kit.NewId(),
+		UserId:   userId,
+		Name:     "cars",
+		Category: "vehicles",
+		Criteria: []*domain.TemplateCriterion{
+			{Name: "range", Kind: domain.QualityKindPro, Importance: 8, Probability: 1},
+			{Name: "price", Kind: domain.QualityKindCon, Importance: 9, Probability: 1},
+		},
+		OptionFields: []*domain.TemplateField{
+			{Name: "vin", Type: domain.FieldTypeVin, Required: true},
+			{Name: "regNumber", Type: domain.FieldTypeCarRegNumber},
+		},
+	}
+}
+
+func (s *templateTestSuite) Test_CreateTemplate_Ok() {
+	userId := kit.NewId()
+	template := s.template("")
+	template.Id = "any"
+	template.OptionFields[1].Type = ""
+	s.templateStorage.On("CreateTemplate", mock.Anything, template).Return(nil)
+	r, err := s.svc.CreateTemplate(s.Ctx, userId, template)
+	s.NoError(err)
+	s.NoError(kit.ValidateUUIDs(r.Id))
+	s.Equal(userId, r.UserId)
+	s.Equal(domain.FieldTypeString, r.OptionFields[1].Type)
+	s.NotEmpty(r.CreatedAt)
+	s.templateStorage.AssertExpectations(s.T())
+}
+
+func (s *templateTestSuite) Test_CreateTemplate_Invalid() {
+	tests := []struct {
+		name   string
+		modify func(t *domain.Template)
+		code   string
+	}{
+		{"name empty", func(t *domain.Template) { t.Name = "" }, errors.ErrCodeDecisionTemplateNameEmpty},
+		{"criteria empty", func(t *domain.Template) { t.Criteria = nil }, errors.ErrCodeDecisionTemplateCriteriaEmpty},
+		{"criterion kind", func(t *domain.Template) { t.Criteria[0].Kind = "any" }, errors.ErrCodeDecisionTemplateCriterionInvalid},
+		{"criterion duplicate", func(t *domain.Template) { t.Criteria[1].Name = t.Criteria[0].Name }, errors.ErrCodeDecisionTemplateCriterionInvalid},
+		{"criterion probability", func(t *domain.Template) { t.Criteria[0].Probability = 2 }, errors.ErrCodeDecisionTemplateCriterionInvalid},
+		{"field type", func(t *domain.Template) { t.OptionFields[0].Type = "any" }, errors.ErrCodeDecisionTemplateFieldInvalid},
+		{"field duplicate", func(t *domain.Template) { t.OptionFields[1].Name = t.OptionFields[0].Name }, errors.ErrCodeDecisionTemplateFieldInvalid},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			template := s.template("")
+			tt.modify(template)
+			_, err := s.svc.CreateTemplate(s.Ctx, kit.NewId(), template)
+			s.AssertAppErr(err, tt.code)
+		})
+	}
+	s.templateStorage.AssertNotCalled(s.T(), "CreateTemplate", mock.Anything, mock.Anything)
+}
+
+func (s *templateTestSuite) Test_GetTemplate_System() {
+	stored := s.template("")
+	s.templateStorage.On("GetTemplate", mock.Anything, stored.Id).Return(stored, nil)
+	r, err := s.svc.GetTemplate(s.Ctx, kit.NewId(), stored.Id)
+	s.NoError(err)
+	s.Equal(stored, r)
+}
+
+func (s *templateTestSuite) Test_GetTemplate_AnotherUser() {
+	stored := s.template(kit.NewId())
+	s.templateStorage.On("GetTemplate", mock.Anything, stored.Id).Return(stored, nil)
+	_, err := s.svc.GetTemplate(s.Ctx, kit.NewId(), stored.Id)
+	s.AssertAppErr(err, errors.ErrCodeDecisionTemplateForbidden)
+}
+
+func (s *templateTestSuite) Test_GetTemplate_NotFound() {
+	templateId := kit.NewId()
+	s.templateStorage.On("GetTemplate", mock.Anything, templateId).Return(nil, nil)
+	_, err := s.svc.GetTemplate(s.Ctx, kit.NewId(), templateId)
+	s.AssertAppErr(err, errors.ErrCodeDecisionTemplateNotFound)
+}
+
+func (s *templateTestSuite) Test_UpdateTemplate_SystemReadOnly() {
+	stored := s.template("")
+	s.templateStorage.On("GetTemplate", mock.Anything, stored.Id).Return(stored, nil)
+	template := s.template("")
+	template.Id = stored.Id
+	_, err := s.svc.UpdateTemplate(s.Ctx, kit.NewId(), template)
+	s.AssertAppErr(err, errors.ErrCodeDecisionTemplateForbidden)
+	s.templateStorage.AssertNotCalled(s.T(), "UpdateTemplate", mock.Anything, mock.Anything)
+}
+
+func (s *templateTestSuite) Test_DeleteTemplate_Owner() {
+	userId := kit.NewId()
+	stored := s.template(userId)
+	s.templateStorage.On("GetTemplate", mock.Anything, stored.Id).Return(stored, nil)
+	s.templateStorage.On("DeleteTemplate", mock.Anything, stored.Id).Return(nil)
+	s.NoError(s.svc.DeleteTemplate(s.Ctx, userId, stored.Id))
+	s.templateStorage.AssertExpectations(s.T())
+}
+
+func (s *templateTestSuite) Test_CreateProblemFromTemplate_Ok() {
+	userId := kit.NewId()
+	stored := s.template("")
+	s.templateStorage.On("GetTemplate", mock.Anything, stored.Id).Return(stored, nil)
+	s.problemStorage.On("CreateProblem", mock.Anything, mock.Anything).Return(nil)
+	problem := &domain.Problem{
+		Name: "my car",
+		Options: []*domain.Option{
+			{Name: "first", Fields: map[string]string{"vin": "WVWZZZ1JZXW000001", "regNumber": "A123BC77"}},
+			{Name: "second", Fields: map[string]string{"vin": "WVWZZZ1JZXW000002"}, Cons: []*domain.Quality{{Name: "ignored"}}},
+		},
+	}
+	r, err := s.svc.CreateProblemFromTemplate(s.Ctx, userId, stored.Id, problem)
+	s.NoError(err)
+	s.Equal(userId, r.UserId)
+	s.Equal(domain.MethodProsCons, r.Method)
+	for _, op := range r.Options {
+		s.Len(op.Pros, 1)
+		s.Len(op.Cons, 1)
+		s.Equal("range", op.Pros[0].Name)
+		s.Equal(8.0, op.Pros[0].Importance)
+		s.Equal("price", op.Cons[0].Name)
+		s.NoError(kit.ValidateUUIDs(op.Id, op.Pros[0].Id, op.Cons[0].Id))
+	}
+	s.Equal("A123BC77", r.Options[0].Fields["regNumber"])
+	s.problemStorage.AssertExpectations(s.T())
+}
+
+func (s *templateTestSuite) Test_CreateProblemFromTemplate_FieldsInvalid() {
+	tests := []struct {
+		name   string
+		fields map[string]string
+		code   string
+	}{
+		{"required", map[string]string{"regNumber": "A123BC77"}, errors.ErrCodeDecisionOptionFieldRequired},
+		{"invalid vin", map[string]string{"vin": "WVWZZZ1JZXW00000I"}, errors.ErrCodeDecisionOptionFieldInvalid},
+		{"invalid reg number", map[string]string{"vin": "WVWZZZ1JZXW000001", "regNumber": "a1"}, errors.ErrCodeDecisionOptionFieldInvalid},
+		{"unknown", map[string]string{"vin": "WVWZZZ1JZXW000001", "color": "red"}, errors.ErrCodeDecisionOptionFieldUnknown},
+	}
+	stored := s.template("")
+	s.templateStorage.On("GetTemplate", mock.Anything, stored.Id).Return(stored, nil)
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			problem := &domain.Problem{Name: "my car", Options: []*domain.Option{{Name: "first", Fields: tt.fields}}}
+			_, err := s.svc.CreateProblemFromTemplate(s.Ctx, kit.NewId(), stored.Id, problem)
+			s.AssertAppErr(err, tt.code)
+		})
+	}
+	s.problemStorage.AssertNotCalled(s.T(), "CreateProblem", mock.Anything, mock.Anything)
+}
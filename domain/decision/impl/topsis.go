@@ -0,0 +1,154 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"math"
+)
+
+// topsisMethod rates options by Technique for Order Preference by Similarity to Ideal Solution
+// scores are vector normalized and weighted, then an option rating is its closeness coefficient:
+// distance to the negative ideal solution divided by the sum of distances to the positive and negative ideal solutions
+type topsisMethod struct{}
+
+func NewTopsisMethod() domain.DecisionMethod {
+	return &topsisMethod{}
+}
+
+func (m *topsisMethod) Code() string {
+	return domain.MethodTopsis
+}
+
+func (m *topsisMethod) Describe() *domain.MethodDescription {
+	return &domain.MethodDescription{
+		Code:        domain.MethodTopsis,
+		Name:        "TOPSIS",
+		Description: "an option is rated by closeness of its weighted normalized scores to the ideal solution",
+		Params: map[string]*domain.ParamSchema{
+			"topsis": objectParam("decision matrix", map[string]*domain.ParamSchema{
+				"criteria": arrayParam("criteria", objectParam("", map[string]*domain.ParamSchema{
+					"name":      stringParam("criterion name"),
+					"weight":    numberParam("criterion weight", kit.Float64Ptr(0), nil),
+					"direction": stringParam("whether bigger or smaller score is better", domain.TopsisBenefit, domain.TopsisCost),
+				}, "weight")),
+				"scores": arrayParam("scores of options (in order of options) by each criterion", arrayParam("", numberParam("score", kit.Float64Ptr(0), nil))),
+			}, "criteria", "scores"),
+		},
+	}
+}
+
+func (m *topsisMethod) Calculate(ctx context.Context, problem *domain.Problem) (domain.DecisionResult, error) {
+
+	r := domain.DecisionResult{
+		Method:        domain.MethodTopsis,
+		OptionsRating: make(map[string]float64, len(problem.Options)),
+	}
+
+	t := problem.Topsis
+	if t == nil || len(t.Criteria) == 0 {
+		return r, errors.ErrDecisionTopsisEmpty(ctx)
+	}
+	weights, err := m.weights(ctx, t.Criteria)
+	if err != nil {
+		return r, err
+	}
+	if err := m.validateScores(ctx, t, len(problem.Options)); err != nil {
+		return r, err
+	}
+
+	// weighted normalized matrix
+	v := make([][]float64, len(t.Scores))
+	for i := range v {
+		v[i] = make([]float64, len(t.Criteria))
+	}
+	for j := range t.Criteria {
+		norm := 0.0
+		for i := range t.Scores {
+			norm += t.Scores[i][j] * t.Scores[i][j]
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			continue
+		}
+		for i := range t.Scores {
+			v[i][j] = weights[j] * t.Scores[i][j] / norm
+		}
+	}
+
+	// positive and negative ideal solutions
+	best, worst := make([]float64, len(t.Criteria)), make([]float64, len(t.Criteria))
+	for j, c := range t.Criteria {
+		best[j], worst[j] = v[0][j], v[0][j]
+		for i := range v {
+			if c.Direction == domain.TopsisCost {
+				best[j], worst[j] = math.Min(best[j], v[i][j]), math.Max(worst[j], v[i][j])
+			} else {
+				best[j], worst[j] = math.Max(best[j], v[i][j]), math.Min(worst[j], v[i][j])
+			}
+		}
+	}
+
+	// closeness coefficients
+	for i, op := range problem.Options {
+		dBest, dWorst := 0.0, 0.0
+		for j := range t.Criteria {
+			dBest += (v[i][j] - best[j]) * (v[i][j] - best[j])
+			dWorst += (v[i][j] - worst[j]) * (v[i][j] - worst[j])
+		}
+		dBest, dWorst = math.Sqrt(dBest), math.Sqrt(dWorst)
+		// all options are equal
+		closeness := 0.5
+		if dBest+dWorst > 0 {
+			closeness = dWorst / (dBest + dWorst)
+		}
+		r.OptionsRating[op.Id] = kit.Round10000(closeness)
+	}
+
+	return r, nil
+}
+
+// weights validates criteria and returns normalized weights
+func (m *topsisMethod) weights(ctx context.Context, criteria []*domain.TopsisCriterion) ([]float64, error) {
+	r := make([]float64, len(criteria))
+	sum := 0.0
+	for i, c := range criteria {
+		if c == nil {
+			return nil, errors.ErrDecisionTopsisInvalid(ctx, fmt.Sprintf("criterion %d is empty", i))
+		}
+		if c.Direction != "" && c.Direction != domain.TopsisBenefit && c.Direction != domain.TopsisCost {
+			return nil, errors.ErrDecisionTopsisInvalid(ctx, fmt.Sprintf("criterion %d direction is invalid", i))
+		}
+		if c.Weight < 0 || math.IsInf(c.Weight, 0) || math.IsNaN(c.Weight) {
+			return nil, errors.ErrDecisionTopsisInvalid(ctx, fmt.Sprintf("criterion %d weight is invalid", i))
+		}
+		r[i] = c.Weight
+		sum += c.Weight
+	}
+	if sum == 0 {
+		return nil, errors.ErrDecisionTopsisInvalid(ctx, "all weights are zero")
+	}
+	for i := range r {
+		r[i] /= sum
+	}
+	return r, nil
+}
+
+func (m *topsisMethod) validateScores(ctx context.Context, t *domain.Topsis, options int) error {
+	if options == 0 || len(t.Scores) != options {
+		return errors.ErrDecisionTopsisInvalid(ctx, "scores must be specified for each option")
+	}
+	for i, row := range t.Scores {
+		if len(row) != len(t.Criteria) {
+			return errors.ErrDecisionTopsisInvalid(ctx, fmt.Sprintf("option %d scores must be specified for each criterion", i))
+		}
+		for _, v := range row {
+			if v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+				return errors.ErrDecisionTopsisInvalid(ctx, fmt.Sprintf("option %d scores must be non-negative", i))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package impl
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type topsisTestSuite struct {
+	kit.Suite
+	method domain.DecisionMethod
+}
+
+func (s *topsisTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+	s.method = NewTopsisMethod()
+}
+
+func TestTopsisSuite(t *testing.T) {
+	suite.Run(t, new(topsisTestSuite))
+}
+
+func (s *topsisTestSuite) problem() *domain.Problem {
+	return &domain.Problem{
+		Id:     kit.NewId(),
+		Name:   "phone",
+		Method: domain.MethodTopsis,
+		Options: []*domain.Option{
+			{Id: kit.NewId(), Name: "first"},
+			{Id: kit.NewId(), Name: "second"},
+			{Id: kit.NewId(), Name: "third"},
+		},
+		Topsis: &domain.Topsis{
+			Criteria: []*domain.TopsisCriterion{
+				{Name: "price", Weight: 1, Direction: domain.TopsisCost},
+				{Name: "storage", Weight: 1},
+			},
+			Scores: [][]float64{
+				{250, 16},
+				{200, 16},
+				{300, 32},
+			},
+		},
+	}
+}
+
+func (s *topsisTestSuite) Test_Topsis() {
+	problem := s.problem()
+	r, err := s.method.Calculate(s.Ctx, problem)
+	s.NoError(err)
+	s.Equal(domain.MethodTopsis, r.Method)
+	s.Equal(0.2119, r.OptionsRating[problem.Options[0].Id])
+	s.Equal(0.3583, r.OptionsRating[problem.Options[1].Id])
+	s.Equal(0.6417, r.OptionsRating[problem.Options[2].Id])
+}
+
+func (s *topsisTestSuite) Test_Topsis_EqualOptions() {
+	problem := s.problem()
+	problem.Topsis.Scores = [][]float64{{1, 1}, {1, 1}, {1, 1}}
+	r, err := s.method.Calculate(s.Ctx, problem)
+	s.NoError(err)
+	for _, op := range problem.Options {
+		s.Equal(0.5, r.OptionsRating[op.Id])
+	}
+}
+
+func (s *topsisTestSuite) Test_Topsis_Empty() {
+	problem := s.problem()
+	problem.Topsis = nil
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionTopsisEmpty)
+}
+
+func (s *topsisTestSuite) Test_Topsis_ZeroWeights() {
+	problem := s.problem()
+	for _, c := range problem.Topsis.Criteria {
+		c.Weight = 0
+	}
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionTopsisInvalid)
+}
+
+func (s *topsisTestSuite) Test_Topsis_InvalidDirection() {
+	problem := s.problem()
+	problem.Topsis.Criteria[0].Direction = "up"
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionTopsisInvalid)
+}
+
+func (s *topsisTestSuite) Test_Topsis_ScoresMismatch() {
+	problem := s.problem()
+	problem.Topsis.Scores = problem.Topsis.Scores[:2]
+	_, err := s.method.Calculate(s.Ctx, problem)
+	s.AssertAppErr(err, errors.ErrCodeDecisionTopsisInvalid)
+}
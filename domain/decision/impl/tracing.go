@@ -0,0 +1,120 @@
+package impl
+
+import (
+	"context"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/kit/tracing"
+)
+
+// tracedDecisionService wraps every call of decision service with a span
+type tracedDecisionService struct {
+	svc domain.DecisionService
+}
+
+func (t *tracedDecisionService) MakeDecision(ctx context.Context, userId string, problem *domain.Problem) (*domain.Decision, error) {
+	ctx, span := tracing.StartSpan(ctx, "decision-svc.make-decision")
+	defer span.End()
+	r, err := t.svc.MakeDecision(ctx, userId, problem)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedDecisionService) MakeDecisionByProblem(ctx context.Context, userId, problemId string) (*domain.Decision, error) {
+	ctx, span := tracing.StartSpan(ctx, "decision-svc.make-decision-by-problem")
+	defer span.End()
+	span.SetAttr("problemId", problemId)
+	r, err := t.svc.MakeDecisionByProblem(ctx, userId, problemId)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedDecisionService) GetDecision(ctx context.Context, decisionId string) (*domain.Decision, error) {
+	ctx, span := tracing.StartSpan(ctx, "decision-svc.get-decision")
+	defer span.End()
+	span.SetAttr("decisionId", decisionId)
+	r, err := t.svc.GetDecision(ctx, decisionId)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedDecisionService) AnalyzeSensitivity(ctx context.Context, problem *domain.Problem, params *domain.SensitivityParams) (*domain.SensitivityResult, error) {
+	ctx, span := tracing.StartSpan(ctx, "decision-svc.analyze-sensitivity")
+	defer span.End()
+	r, err := t.svc.AnalyzeSensitivity(ctx, problem, params)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedDecisionService) GetMethods(ctx context.Context) []*domain.MethodDescription {
+	ctx, span := tracing.StartSpan(ctx, "decision-svc.get-methods")
+	defer span.End()
+	return t.svc.GetMethods(ctx)
+}
+
+func (t *tracedDecisionService) Close(ctx context.Context) error {
+	return t.svc.Close(ctx)
+}
+
+// tracedGroupService wraps every call of group service with a span
+type tracedGroupService struct {
+	svc domain.GroupService
+}
+
+func (t *tracedGroupService) SetMembers(ctx context.Context, userId, problemId string, members []*domain.Member) ([]*domain.Member, error) {
+	ctx, span := tracing.StartSpan(ctx, "group-svc.set-members")
+	defer span.End()
+	span.SetAttr("problemId", problemId)
+	r, err := t.svc.SetMembers(ctx, userId, problemId, members)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedGroupService) GetMembers(ctx context.Context, userId, problemId string) ([]*domain.Member, error) {
+	ctx, span := tracing.StartSpan(ctx, "group-svc.get-members")
+	defer span.End()
+	span.SetAttr("problemId", problemId)
+	r, err := t.svc.GetMembers(ctx, userId, problemId)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedGroupService) SetAssessments(ctx context.Context, userId, problemId string, assessments []*domain.Assessment) ([]*domain.Assessment, error) {
+	ctx, span := tracing.StartSpan(ctx, "group-svc.set-assessments")
+	defer span.End()
+	span.SetAttr("problemId", problemId)
+	r, err := t.svc.SetAssessments(ctx, userId, problemId, assessments)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedGroupService) GetAssessments(ctx context.Context, userId, problemId string) ([]*domain.Assessment, error) {
+	ctx, span := tracing.StartSpan(ctx, "group-svc.get-assessments")
+	defer span.End()
+	span.SetAttr("problemId", problemId)
+	r, err := t.svc.GetAssessments(ctx, userId, problemId)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedGroupService) MakeGroupDecision(ctx context.Context, userId, problemId, aggregation string) (*domain.Decision, error) {
+	ctx, span := tracing.StartSpan(ctx, "group-svc.make-group-decision")
+	defer span.End()
+	span.SetAttr("problemId", problemId)
+	span.SetAttr("aggregation", aggregation)
+	r, err := t.svc.MakeGroupDecision(ctx, userId, problemId, aggregation)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedGroupService) GetDecisions(ctx context.Context, userId, problemId string) ([]*domain.Decision, error) {
+	ctx, span := tracing.StartSpan(ctx, "group-svc.get-decisions")
+	defer span.End()
+	span.SetAttr("problemId", problemId)
+	r, err := t.svc.GetDecisions(ctx, userId, problemId)
+	span.SetError(err)
+	return r, err
+}
+
+func (t *tracedGroupService) Close(ctx context.Context) error {
+	return t.svc.Close(ctx)
+}
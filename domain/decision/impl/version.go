@@ -0,0 +1,189 @@
+package impl
+
+import (
+	"context"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"reflect"
+)
+
+func (s *problemServiceImpl) GetProblemVersions(ctx context.Context, userId, problemId string) ([]*domain.ProblemVersion, error) {
+	s.l().C(ctx).Mth("get-versions").Dbg()
+
+	if _, err := s.GetProblem(ctx, userId, problemId); err != nil {
+		return nil, err
+	}
+
+	return s.problemStorage.GetProblemVersions(ctx, problemId)
+}
+
+func (s *problemServiceImpl) GetProblemVersion(ctx context.Context, userId, problemId string, version int) (*domain.Problem, error) {
+	s.l().C(ctx).Mth("get-version").Dbg()
+
+	if _, err := s.GetProblem(ctx, userId, problemId); err != nil {
+		return nil, err
+	}
+
+	return s.getVersion(ctx, problemId, version)
+}
+
+func (s *problemServiceImpl) DiffProblemVersions(ctx context.Context, userId, problemId string, from, to int) (*domain.ProblemDiff, error) {
+	s.l().C(ctx).Mth("diff-versions").Dbg()
+
+	if _, err := s.GetProblem(ctx, userId, problemId); err != nil {
+		return nil, err
+	}
+
+	pFrom, err := s.getVersion(ctx, problemId, from)
+	if err != nil {
+		return nil, err
+	}
+	pTo, err := s.getVersion(ctx, problemId, to)
+	if err != nil {
+		return nil, err
+	}
+
+	ratingFrom, ratingTo := s.rate(ctx, pFrom), s.rate(ctx, pTo)
+
+	r := &domain.ProblemDiff{
+		ProblemId:  problemId,
+		From:       from,
+		To:         to,
+		NameFrom:   pFrom.Name,
+		NameTo:     pTo.Name,
+		MethodFrom: pFrom.Method,
+		MethodTo:   pTo.Method,
+		WinnerFrom: leader(pFrom, ratingFrom),
+		WinnerTo:   leader(pTo, ratingTo),
+	}
+
+	// options of the from version go first in their order, then added ones
+	toOptions := make(map[string]*domain.Option, len(pTo.Options))
+	for _, op := range pTo.Options {
+		toOptions[op.Id] = op
+	}
+	fromOptions := make(map[string]*domain.Option, len(pFrom.Options))
+	for _, op := range pFrom.Options {
+		fromOptions[op.Id] = op
+		r.Options = append(r.Options, diffOption(op, toOptions[op.Id], ratingFrom, ratingTo))
+	}
+	for _, op := range pTo.Options {
+		if _, ok := fromOptions[op.Id]; !ok {
+			r.Options = append(r.Options, diffOption(nil, op, ratingFrom, ratingTo))
+		}
+	}
+
+	return r, nil
+}
+
+func (s *problemServiceImpl) getVersion(ctx context.Context, problemId string, version int) (*domain.Problem, error) {
+	r, err := s.problemStorage.GetProblemVersion(ctx, problemId, version)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, errors.ErrDecisionProblemVersionNotFound(ctx, problemId, version)
+	}
+	return r, nil
+}
+
+// rate rates options of the problem version, returns nil if the version can't be rated (e.g. comparisons are incomplete)
+func (s *problemServiceImpl) rate(ctx context.Context, problem *domain.Problem) map[string]float64 {
+	method, err := s.methodRegistry.Get(ctx, problem.Method)
+	if err != nil {
+		s.l().C(ctx).Mth("rate").E(err).Warn("version can't be rated")
+		return nil
+	}
+	res, err := method.Calculate(ctx, problem)
+	if err != nil {
+		s.l().C(ctx).Mth("rate").E(err).Warn("version can't be rated")
+		return nil
+	}
+	return res.OptionsRating
+}
+
+// diffOption compares option versions, either of them can be nil if the option is added or removed
+func diffOption(from, to *domain.Option, ratingFrom, ratingTo map[string]float64) *domain.OptionDiff {
+	r := &domain.OptionDiff{}
+	switch {
+	case from == nil:
+		r.OptionId, r.Name, r.Change = to.Id, to.Name, domain.ChangeAdded
+		from = &domain.Option{}
+	case to == nil:
+		r.OptionId, r.Name, r.Change = from.Id, from.Name, domain.ChangeRemoved
+		to = &domain.Option{}
+	default:
+		r.OptionId, r.Name = to.Id, to.Name
+		if from.Name != to.Name {
+			r.Change, r.NameFrom = domain.ChangeChanged, from.Name
+		}
+	}
+	if v, ok := ratingFrom[r.OptionId]; ok {
+		r.RatingFrom = kit.Float64Ptr(v)
+	}
+	if v, ok := ratingTo[r.OptionId]; ok {
+		r.RatingTo = kit.Float64Ptr(v)
+	}
+	r.Qualities = append(diffQualities(domain.QualityKindPro, from.Pros, to.Pros), diffQualities(domain.QualityKindCon, from.Cons, to.Cons)...)
+	if r.Change == "" && len(r.Qualities) > 0 {
+		r.Change = domain.ChangeChanged
+	}
+	return r
+}
+
+// diffQualities returns changed qualities only
+func diffQualities(kind string, from, to []*domain.Quality) []*domain.QualityDiff {
+	var r []*domain.QualityDiff
+	toMap := make(map[string]*domain.Quality, len(to))
+	for _, q := range to {
+		toMap[q.Id] = q
+	}
+	fromMap := make(map[string]*domain.Quality, len(from))
+	for _, q := range from {
+		fromMap[q.Id] = q
+		t, ok := toMap[q.Id]
+		if !ok {
+			r = append(r, &domain.QualityDiff{
+				QualityId:       q.Id,
+				Kind:            kind,
+				Name:            q.Name,
+				Change:          domain.ChangeRemoved,
+				ImportanceFrom:  q.Importance,
+				ProbabilityFrom: q.Probability,
+			})
+			continue
+		}
+		if q.Name == t.Name && q.Importance == t.Importance && q.Probability == t.Probability &&
+			reflect.DeepEqual(q.ImportanceDist, t.ImportanceDist) && reflect.DeepEqual(q.ProbabilityDist, t.ProbabilityDist) {
+			continue
+		}
+		d := &domain.QualityDiff{
+			QualityId:       q.Id,
+			Kind:            kind,
+			Name:            t.Name,
+			Change:          domain.ChangeChanged,
+			ImportanceFrom:  q.Importance,
+			ImportanceTo:    t.Importance,
+			ProbabilityFrom: q.Probability,
+			ProbabilityTo:   t.Probability,
+		}
+		if q.Name != t.Name {
+			d.NameFrom = q.Name
+		}
+		r = append(r, d)
+	}
+	for _, q := range to {
+		if _, ok := fromMap[q.Id]; !ok {
+			r = append(r, &domain.QualityDiff{
+				QualityId:     q.Id,
+				Kind:          kind,
+				Name:          q.Name,
+				Change:        domain.ChangeAdded,
+				ImportanceTo:  q.Importance,
+				ProbabilityTo: q.Probability,
+			})
+		}
+	}
+	return r
+}
@@ -0,0 +1,103 @@
+package impl
+
+import (
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/mock"
+)
+
+// versions mocks two versions of the stored problem:
+// in the second one the first option's pro importance is decreased, the second option is renamed and the third option is added
+func (s *problemTestSuite) versions(userId string) (*domain.Problem, *domain.Problem) {
+	v1 := s.storedProblem(userId)
+	v1.Version = 1
+	v1.Options = append(v1.Options, &domain.Option{
+		Id:   kit.NewId(),
+		Name: "second",
+		Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 5, Probability: 0.5}},
+		Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 5, Probability: 0.5}},
+	})
+	v2 := copyProblem(v1, func(q *domain.Quality) {})
+	v2.Version = 2
+	v2.Options[0].Pros[0].Importance = 2
+	v2.Options[1].Name = "second renamed"
+	v2.Options = append(v2.Options, &domain.Option{Id: kit.NewId(), Name: "third"})
+	s.problemStorage.On("GetProblem", mock.Anything, v1.Id).Return(v2, nil)
+	s.problemStorage.On("GetProblemVersion", mock.Anything, v1.Id, 1).Return(v1, nil)
+	s.problemStorage.On("GetProblemVersion", mock.Anything, v1.Id, 2).Return(v2, nil)
+	return v1, v2
+}
+
+func (s *problemTestSuite) Test_DiffProblemVersions() {
+	userId := kit.NewId()
+	v1, v2 := s.versions(userId)
+	r, err := s.svc.DiffProblemVersions(s.Ctx, userId, v1.Id, 1, 2)
+	s.NoError(err)
+	s.Equal(1, r.From)
+	s.Equal(2, r.To)
+	s.Equal(v1.Options[0].Id, r.WinnerFrom)
+	s.Equal(v1.Options[1].Id, r.WinnerTo)
+	s.Len(r.Options, 3)
+
+	first := r.Options[0]
+	s.Equal(domain.ChangeChanged, first.Change)
+	s.Empty(first.NameFrom)
+	s.Equal(2.0, *first.RatingFrom)
+	// the third option has no cons, so ratio smoothing applies: (1 + 1) / (2.5 + 1)
+	s.Equal(0.57, *first.RatingTo)
+	s.Len(first.Qualities, 1)
+	s.Equal(domain.ChangeChanged, first.Qualities[0].Change)
+	s.Equal(domain.QualityKindPro, first.Qualities[0].Kind)
+	s.Equal(10.0, first.Qualities[0].ImportanceFrom)
+	s.Equal(2.0, first.Qualities[0].ImportanceTo)
+
+	second := r.Options[1]
+	s.Equal(domain.ChangeChanged, second.Change)
+	s.Equal("second", second.NameFrom)
+	s.Equal("second renamed", second.Name)
+	s.Empty(second.Qualities)
+
+	third := r.Options[2]
+	s.Equal(domain.ChangeAdded, third.Change)
+	s.Nil(third.RatingFrom)
+	s.NotNil(third.RatingTo)
+	s.Equal(v2.Options[2].Id, third.OptionId)
+}
+
+func (s *problemTestSuite) Test_DiffProblemVersions_Removed() {
+	userId := kit.NewId()
+	v1, v2 := s.versions(userId)
+	r, err := s.svc.DiffProblemVersions(s.Ctx, userId, v1.Id, 2, 1)
+	s.NoError(err)
+	s.Len(r.Options, 3)
+	s.Equal(domain.ChangeRemoved, r.Options[2].Change)
+	s.Equal(v2.Options[2].Id, r.Options[2].OptionId)
+	s.Nil(r.Options[2].RatingTo)
+}
+
+func (s *problemTestSuite) Test_DiffProblemVersions_Unchanged() {
+	userId := kit.NewId()
+	v1, _ := s.versions(userId)
+	r, err := s.svc.DiffProblemVersions(s.Ctx, userId, v1.Id, 1, 1)
+	s.NoError(err)
+	s.Len(r.Options, 2)
+	for _, op := range r.Options {
+		s.Empty(op.Change)
+		s.Empty(op.Qualities)
+	}
+}
+
+func (s *problemTestSuite) Test_DiffProblemVersions_VersionNotFound() {
+	userId := kit.NewId()
+	v1, _ := s.versions(userId)
+	s.problemStorage.On("GetProblemVersion", mock.Anything, v1.Id, 5).Return(nil, nil)
+	_, err := s.svc.DiffProblemVersions(s.Ctx, userId, v1.Id, 1, 5)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemVersionNotFound)
+}
+
+func (s *problemTestSuite) Test_GetProblemVersion_Forbidden() {
+	v1, _ := s.versions(kit.NewId())
+	_, err := s.svc.GetProblemVersion(s.Ctx, kit.NewId(), v1.Id, 1)
+	s.AssertAppErr(err, errors.ErrCodeDecisionProblemForbidden)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "context"
+
+const (
+	ParamTypeObject = "object"
+	ParamTypeArray  = "array"
+	ParamTypeNumber = "number"
+	ParamTypeString = "string"
+)
+
+// ParamSchema describes input parameters of a decision method (subset of JSON schema)
+type ParamSchema struct {
+	Type        string                  // Type ParamType* constant
+	Description string                  // Description human-readable description
+	Properties  map[string]*ParamSchema // Properties properties of object
+	Required    []string                // Required required properties of object
+	Items       *ParamSchema            // Items schema of array items
+	Enum        []string                // Enum allowed values
+	Minimum     *float64                // Minimum min allowed value of number
+	Maximum     *float64                // Maximum max allowed value of number
+}
+
+// MethodDescription describes a decision method
+type MethodDescription struct {
+	Code        string                  // Code unique method code
+	Name        string                  // Name method name
+	Description string                  // Description human-readable description
+	Params      map[string]*ParamSchema // Params input parameters by problem attribute
+}
+
+// DecisionMethod calculates rating of the problem options
+type DecisionMethod interface {
+	// Code unique method code
+	Code() string
+	// Describe returns method description with parameter schemas
+	Describe() *MethodDescription
+	// Calculate rates the problem options
+	Calculate(ctx context.Context, problem *Problem) (DecisionResult, error)
+}
+
+// MethodRegistry keeps registered decision methods
+type MethodRegistry interface {
+	// Register registers a new method, method code must be unique
+	Register(ctx context.Context, method DecisionMethod) error
+	// Get retrieves a method by code
+	// returns the default method if code is empty
+	Get(ctx context.Context, code string) (DecisionMethod, error)
+	// List lists all registered methods ordered by code
+	List(ctx context.Context) []DecisionMethod
+}
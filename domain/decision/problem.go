@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+)
+
+const (
+	QualityKindPro = "pro"
+	QualityKindCon = "con"
+)
+
+// ProblemSearchCriteria specifies criteria to search problems
+type ProblemSearchCriteria struct {
+	kit.PagingRequest
+	UserId string
+}
+
+// ProblemSearchResponse search response
+// Note, options aren't populated
+type ProblemSearchResponse struct {
+	kit.PagingResponse
+	Problems []*Problem
+}
+
+type ProblemService interface {
+	// CreateProblem creates a new problem with options and qualities for the user
+	CreateProblem(ctx context.Context, userId string, problem *Problem) (*Problem, error)
+	// UpdateProblem updates problem attributes
+	UpdateProblem(ctx context.Context, userId string, problem *Problem) (*Problem, error)
+	// GetProblem retrieves the user's problem by id
+	GetProblem(ctx context.Context, userId, problemId string) (*Problem, error)
+	// SearchProblems searches problems by criteria
+	SearchProblems(ctx context.Context, criteria *ProblemSearchCriteria) (*ProblemSearchResponse, error)
+	// DeleteProblem deletes the user's problem (soft)
+	DeleteProblem(ctx context.Context, userId, problemId string) error
+	// AddOption adds a new option to the problem
+	// it's not allowed if the problem has comparisons of options (AHP, TOPSIS)
+	AddOption(ctx context.Context, userId, problemId string, option *Option) (*Option, error)
+	// UpdateOption updates option attributes
+	UpdateOption(ctx context.Context, userId, problemId string, option *Option) (*Option, error)
+	// DeleteOption deletes the option (soft), its comparisons (AHP, TOPSIS) are removed as well
+	DeleteOption(ctx context.Context, userId, problemId, optionId string) error
+	// AddQuality adds a new quality of the given kind (pro, con) to the option
+	AddQuality(ctx context.Context, userId, problemId, optionId, kind string, quality *Quality) (*Quality, error)
+	// UpdateQuality updates quality attributes
+	UpdateQuality(ctx context.Context, userId, problemId, optionId, kind string, quality *Quality) (*Quality, error)
+	// DeleteQuality deletes the quality (soft)
+	DeleteQuality(ctx context.Context, userId, problemId, optionId, kind, qualityId string) error
+	// GetProblemVersions retrieves all versions of the user's problem
+	GetProblemVersions(ctx context.Context, userId, problemId string) ([]*ProblemVersion, error)
+	// GetProblemVersion retrieves the problem as it was in the given version
+	GetProblemVersion(ctx context.Context, userId, problemId string, version int) (*Problem, error)
+	// DiffProblemVersions compares two versions of the problem and their ratings
+	DiffProblemVersions(ctx context.Context, userId, problemId string, from, to int) (*ProblemDiff, error)
+}
+
+// ProblemStorage stores problems
+// each change of a problem, its options or qualities creates a new version of the problem
+type ProblemStorage interface {
+	// CreateProblem creates a new problem with all its options and qualities
+	// the problem's version is set to the created one
+	CreateProblem(ctx context.Context, problem *Problem) error
+	// UpdateProblem updates the problem, its options and qualities by id
+	// new options and qualities are created, missing ones are deleted along with group assessments of them
+	// the problem's version is set to the created one
+	UpdateProblem(ctx context.Context, problem *Problem) error
+	// GetProblem retrieves a problem by id
+	// returns nil if not found
+	GetProblem(ctx context.Context, problemId string) (*Problem, error)
+	// ProblemExists checks if a problem with the id exists, deleted ones included
+	ProblemExists(ctx context.Context, problemId string) (bool, error)
+	// GetForeignIds retrieves the given option and quality ids which belong to other problems, deleted ones included
+	GetForeignIds(ctx context.Context, problemId string, optionIds, qualityIds []string) ([]string, error)
+	// SearchProblems searches problems by criteria
+	SearchProblems(ctx context.Context, criteria *ProblemSearchCriteria) (*ProblemSearchResponse, error)
+	// DeleteProblem deletes problem with all options and qualities (soft)
+	DeleteProblem(ctx context.Context, problemId string) error
+	// CreateOption creates a new option of the problem
+	CreateOption(ctx context.Context, problemId string, option *Option) error
+	// UpdateOption updates option attributes
+	UpdateOption(ctx context.Context, option *Option) error
+	// DeleteOption deletes option with all qualities (soft)
+	// method params of the problem are stored along with, since comparisons of options are indexed by option order
+	DeleteOption(ctx context.Context, problem *Problem, optionId string) error
+	// CreateQuality creates a new quality of the given kind
+	CreateQuality(ctx context.Context, problemId, optionId, kind string, quality *Quality) error
+	// UpdateQuality updates quality attributes
+	UpdateQuality(ctx context.Context, quality *Quality) error
+	// DeleteQuality deletes quality (soft)
+	DeleteQuality(ctx context.Context, qualityId string) error
+	// GetProblemVersions retrieves all versions of the problem
+	GetProblemVersions(ctx context.Context, problemId string) ([]*ProblemVersion, error)
+	// GetProblemVersion retrieves the problem snapshot of the given version
+	// returns nil if not found
+	GetProblemVersion(ctx context.Context, problemId string, version int) (*Problem, error)
+}
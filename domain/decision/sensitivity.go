@@ -0,0 +1,40 @@
+package domain
+
+const (
+	ParamImportance  = "importance"
+	ParamProbability = "probability"
+)
+
+// SensitivityParams specifies sensitivity analysis params
+type SensitivityParams struct {
+	Range float64 // Range relative perturbation of each param for tornado data, 0.2 (±20%) by default
+}
+
+// BreakEven a param value at which the top-ranked option changes
+type BreakEven struct {
+	Value    float64 // Value param value
+	WinnerId string  // WinnerId option ranked first beyond the value
+}
+
+// QualitySensitivity describes how the quality param affects the decision
+type QualitySensitivity struct {
+	OptionId      string
+	QualityId     string
+	Kind          string     // Kind QualityKindPro or QualityKindCon
+	Param         string     // Param ParamImportance or ParamProbability
+	Value         float64    // Value current param value
+	Low           float64    // Low decreased param value
+	High          float64    // High increased param value
+	RatingLow     float64    // RatingLow option rating when the param is decreased
+	RatingHigh    float64    // RatingHigh option rating when the param is increased
+	Swing         float64    // Swing absolute rating change between Low and High
+	BreakEvenDown *BreakEven // BreakEvenDown the nearest break-even below the current value, nil if the winner doesn't change
+	BreakEvenUp   *BreakEven // BreakEvenUp the nearest break-even above the current value, nil if the winner doesn't change
+}
+
+// SensitivityResult sensitivity analysis result
+type SensitivityResult struct {
+	WinnerId      string                // WinnerId option ranked first
+	OptionsRating map[string]float64    // OptionsRating base rating by option id
+	Qualities     []*QualitySensitivity // Qualities sensitivity of quality params ordered by swing (tornado chart)
+}
@@ -0,0 +1,49 @@
+package domain
+
+const (
+	// DistUniform uniform distribution over [Min, Max]
+	DistUniform = "uniform"
+	// DistTriangular triangular distribution over [Min, Max] with Mode
+	DistTriangular = "triangular"
+	// DistNormal normal distribution with Mean and StdDev
+	DistNormal = "normal"
+	// DistBeta beta distribution with Alpha and Beta shapes scaled to [Min, Max] ([0, 1] if not specified)
+	DistBeta = "beta"
+)
+
+// Distribution specifies a random value distribution
+type Distribution struct {
+	Type   string
+	Min    float64
+	Max    float64
+	Mode   float64
+	Mean   float64
+	StdDev float64
+	Alpha  float64
+	Beta   float64
+}
+
+// Simulation specifies Monte Carlo simulation params
+type Simulation struct {
+	Iterations int    // Iterations number of simulations
+	Seed       *int64 // Seed random seed, results are reproducible for the same seed; random if empty
+}
+
+// OptionSimulation simulated rating statistics of an option
+type OptionSimulation struct {
+	Mean            float64
+	StdDev          float64
+	P5              float64
+	P25             float64
+	P50             float64
+	P75             float64
+	P95             float64
+	ProbabilityBest float64 // ProbabilityBest probability the option ranks first
+}
+
+// SimulationResult Monte Carlo simulation result
+type SimulationResult struct {
+	Iterations int
+	Seed       int64
+	Options    map[string]*OptionSimulation // Options statistics by option id
+}
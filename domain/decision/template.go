@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+	"time"
+)
+
+const (
+	// FieldTypeString any text
+	FieldTypeString = "string"
+	// FieldTypeNumber decimal number
+	FieldTypeNumber = "number"
+	// FieldTypeUrl web link
+	FieldTypeUrl = "url"
+	// FieldTypeEmail email address
+	FieldTypeEmail = "email"
+	// FieldTypePhone phone number
+	FieldTypePhone = "phone"
+	// FieldTypeVin vehicle identification number
+	FieldTypeVin = "vin"
+	// FieldTypeCarRegNumber car registration number
+	FieldTypeCarRegNumber = "car-reg-number"
+)
+
+// TemplateCriterion a criterion every option of a problem created from the template is assessed by
+// it becomes a pro or a con of each option with default importance and probability
+type TemplateCriterion struct {
+	Name        string
+	Kind        string  // Kind QualityKindPro or QualityKindCon
+	Importance  float64 // Importance default importance
+	Probability float64 // Probability default probability
+}
+
+// TemplateField an attribute options of a problem created from the template are described with (e.g. VIN of a car)
+type TemplateField struct {
+	Name     string
+	Type     string // Type one of FieldType*, FieldTypeString if empty
+	Required bool   // Required if true, an option must specify the field value
+}
+
+// Template a predefined set of criteria for a typical problem (vendors, job offers, cars)
+type Template struct {
+	Id           string
+	UserId       string // UserId template owner, empty for system templates
+	Name         string
+	Description  string
+	Category     string
+	Criteria     []*TemplateCriterion
+	OptionFields []*TemplateField
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// System returns true if the template is a system-wide one
+func (t *Template) System() bool {
+	return t.UserId == ""
+}
+
+// TemplateSearchCriteria specifies criteria to search templates
+type TemplateSearchCriteria struct {
+	kit.PagingRequest
+	UserId   string // UserId if specified, system templates and templates of the user are searched, otherwise system templates only
+	Category string
+}
+
+// TemplateSearchResponse search response
+type TemplateSearchResponse struct {
+	kit.PagingResponse
+	Templates []*Template
+}
+
+type TemplateService interface {
+	// CreateTemplate creates a template owned by the user
+	CreateTemplate(ctx context.Context, userId string, template *Template) (*Template, error)
+	// UpdateTemplate updates the user's template, system templates are read only
+	UpdateTemplate(ctx context.Context, userId string, template *Template) (*Template, error)
+	// GetTemplate retrieves a system template or a template of the user
+	GetTemplate(ctx context.Context, userId, templateId string) (*Template, error)
+	// SearchTemplates searches system templates and templates of the user
+	SearchTemplates(ctx context.Context, criteria *TemplateSearchCriteria) (*TemplateSearchResponse, error)
+	// DeleteTemplate deletes the user's template (soft)
+	DeleteTemplate(ctx context.Context, userId, templateId string) error
+	// CreateProblemFromTemplate creates a problem of the user from the template
+	// every option gets the template criteria as pros and cons, qualities specified for the options are ignored
+	// option fields are validated against the template fields
+	CreateProblemFromTemplate(ctx context.Context, userId, templateId string, problem *Problem) (*Problem, error)
+}
+
+type TemplateStorage interface {
+	// CreateTemplate creates a new template
+	CreateTemplate(ctx context.Context, template *Template) error
+	// UpdateTemplate updates the template
+	UpdateTemplate(ctx context.Context, template *Template) error
+	// GetTemplate retrieves a template by id, returns nil if not found
+	GetTemplate(ctx context.Context, templateId string) (*Template, error)
+	// SearchTemplates searches templates by criteria
+	SearchTemplates(ctx context.Context, criteria *TemplateSearchCriteria) (*TemplateSearchResponse, error)
+	// DeleteTemplate deletes the template (soft)
+	DeleteTemplate(ctx context.Context, templateId string) error
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// ProblemVersion immutable version of a problem
+// each change of the problem, its options or qualities creates a new version
+type ProblemVersion struct {
+	ProblemId string
+	Version   int
+	CreatedAt time.Time
+}
+
+// QualityDiff change of a quality between versions
+type QualityDiff struct {
+	QualityId       string
+	Kind            string // Kind pro or con
+	Name            string
+	Change          string // Change added, removed or changed
+	NameFrom        string
+	ImportanceFrom  float64
+	ImportanceTo    float64
+	ProbabilityFrom float64
+	ProbabilityTo   float64
+}
+
+// OptionDiff change of an option between versions
+// Change is changed if either the option name or any of its qualities is changed, empty if nothing is changed
+type OptionDiff struct {
+	OptionId   string
+	Name       string
+	Change     string // Change added, removed, changed or empty if unchanged
+	NameFrom   string
+	RatingFrom *float64 // RatingFrom rating in the from version, nil if option is absent or the version can't be rated
+	RatingTo   *float64 // RatingTo rating in the to version, nil if option is absent or the version can't be rated
+	Qualities  []*QualityDiff
+}
+
+// ProblemDiff difference between two versions of a problem and their ratings
+type ProblemDiff struct {
+	ProblemId  string
+	From       int
+	To         int
+	NameFrom   string
+	NameTo     string
+	MethodFrom string
+	MethodTo   string
+	WinnerFrom string // WinnerFrom top-ranked option in the from version
+	WinnerTo   string // WinnerTo top-ranked option in the to version
+	Options    []*OptionDiff
+}
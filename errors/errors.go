@@ -0,0 +1,407 @@
+package errors
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+	"google.golang.org/grpc/codes"
+	"net/http"
+	"strings"
+)
+
+const (
+	ErrCodeDecisionProblemEmpty                            = "DEC-001"
+	ErrCodeDecisionProblemInvalidId                        = "DEC-002"
+	ErrCodeDecisionProblemNotFound                         = "DEC-003"
+	ErrCodeDecisionProblemForbidden                        = "DEC-004"
+	ErrCodeDecisionNotFound                                = "DEC-005"
+	ErrCodeDecisionOptionNotFound                          = "DEC-006"
+	ErrCodeDecisionQualityNotFound                         = "DEC-007"
+	ErrCodeDecisionQualityKindInvalid                      = "DEC-008"
+	ErrCodeDecisionProblemNameEmpty                        = "DEC-009"
+	ErrCodeDecisionOptionNameEmpty                         = "DEC-010"
+	ErrCodeDecisionQualityNameEmpty                        = "DEC-011"
+	ErrCodeDecisionMethodInvalid                           = "DEC-012"
+	ErrCodeDecisionAhpEmpty                                = "DEC-013"
+	ErrCodeDecisionAhpMatrixInvalid                        = "DEC-014"
+	ErrCodeDecisionAhpInconsistent                         = "DEC-015"
+	ErrCodeDecisionTopsisEmpty                             = "DEC-016"
+	ErrCodeDecisionTopsisInvalid                           = "DEC-017"
+	ErrCodeDecisionMethodEmpty                             = "DEC-018"
+	ErrCodeDecisionMethodAlreadyRegistered                 = "DEC-019"
+	ErrCodeDecisionDistributionInvalid                     = "DEC-020"
+	ErrCodeDecisionSimulationIterationsInvalid             = "DEC-021"
+	ErrCodeDecisionSensitivityRangeInvalid                 = "DEC-022"
+	ErrCodeDecisionQualityImportanceInvalid                = "DEC-023"
+	ErrCodeDecisionQualityProbabilityInvalid               = "DEC-024"
+	ErrCodeDecisionScoringInvalid                          = "DEC-025"
+	ErrCodeDecisionSmoothingInvalid                        = "DEC-026"
+	ErrCodeDecisionRatioUndefined                          = "DEC-027"
+	ErrCodeDecisionMemberInvalid                           = "DEC-028"
+	ErrCodeDecisionMemberDuplicate                         = "DEC-029"
+	ErrCodeDecisionAssessmentQualityInvalid                = "DEC-030"
+	ErrCodeDecisionAggregationInvalid                      = "DEC-031"
+	ErrCodeDecisionProblemOwnerOnly                        = "DEC-032"
+	ErrCodeDecisionProblemVersionNotFound                  = "DEC-033"
+	ErrCodeDecisionServiceClosed                           = "DEC-034"
+	ErrCodeDecisionTemplateInvalidId                       = "DEC-035"
+	ErrCodeDecisionTemplateNotFound                        = "DEC-036"
+	ErrCodeDecisionTemplateForbidden                       = "DEC-037"
+	ErrCodeDecisionTemplateNameEmpty                       = "DEC-038"
+	ErrCodeDecisionTemplateCriteriaEmpty                   = "DEC-039"
+	ErrCodeDecisionTemplateCriterionInvalid                = "DEC-040"
+	ErrCodeDecisionTemplateFieldInvalid                    = "DEC-041"
+	ErrCodeDecisionOptionFieldRequired                     = "DEC-042"
+	ErrCodeDecisionOptionFieldInvalid                      = "DEC-043"
+	ErrCodeDecisionOptionFieldUnknown                      = "DEC-044"
+	ErrCodeDecisionExchangeFormatInvalid                   = "DEC-045"
+	ErrCodeDecisionImportParse                             = "DEC-046"
+	ErrCodeDecisionImportVersionUnsupported                = "DEC-047"
+	ErrCodeDecisionImportValueInvalid                      = "DEC-048"
+	ErrCodeDecisionImportCsvHeaderInvalid                  = "DEC-049"
+	ErrCodeDecisionImportCsvCellInvalid                    = "DEC-050"
+	ErrCodeDecisionImportCsvEmpty                          = "DEC-051"
+	ErrCodeDecisionExportCsvQualityDuplicate               = "DEC-052"
+	ErrCodeDecisionExportEncode                            = "DEC-053"
+	ErrCodeDecisionOptionComparisonsSet                    = "DEC-054"
+	ErrCodeDecisionOptionIdDuplicate                       = "DEC-055"
+	ErrCodeDecisionQualityIdDuplicate                      = "DEC-056"
+	ErrCodeDecisionSensitivityMethodUnsupported            = "DEC-057"
+	ErrCodeDecisionSensitivityQualitiesExceeded            = "DEC-058"
+	ErrCodeDecisionSensitivityEvaluationsExceeded          = "DEC-059"
+	ErrCodeDecisionSimulationMethodUnsupported             = "DEC-060"
+	ErrCodeDecisionProblemForeignIds                       = "DEC-061"
+	ErrCodeDecisionImportTooLarge                          = "DEC-062"
+	ErrCodeStorageInvalidConfig                            = "DEC-ST-001"
+	ErrCodeStorageProblemCreate                            = "DEC-ST-002"
+	ErrCodeStorageProblemUpdate                            = "DEC-ST-003"
+	ErrCodeStorageProblemGet                               = "DEC-ST-004"
+	ErrCodeStorageDecisionCreate                           = "DEC-ST-005"
+	ErrCodeStorageDecisionGet                              = "DEC-ST-006"
+	ErrCodeStorageDecisionMarshal                          = "DEC-ST-007"
+	ErrCodeStorageDecisionUnmarshal                        = "DEC-ST-008"
+	ErrCodeStorageProblemSearch                            = "DEC-ST-009"
+	ErrCodeStorageProblemDelete                            = "DEC-ST-010"
+	ErrCodeStorageOptionCreate                             = "DEC-ST-011"
+	ErrCodeStorageOptionUpdate                             = "DEC-ST-012"
+	ErrCodeStorageOptionDelete                             = "DEC-ST-013"
+	ErrCodeStorageQualityCreate                            = "DEC-ST-014"
+	ErrCodeStorageQualityUpdate                            = "DEC-ST-015"
+	ErrCodeStorageQualityDelete                            = "DEC-ST-016"
+	ErrCodeStorageProblemMarshal                           = "DEC-ST-017"
+	ErrCodeStorageProblemUnmarshal                         = "DEC-ST-018"
+	ErrCodeStorageMembersSet                               = "DEC-ST-019"
+	ErrCodeStorageMembersGet                               = "DEC-ST-020"
+	ErrCodeStorageAssessmentsSet                           = "DEC-ST-021"
+	ErrCodeStorageAssessmentsGet                           = "DEC-ST-022"
+	ErrCodeStorageProblemVersionGet                        = "DEC-ST-023"
+	ErrCodeStorageTemplateCreate                           = "DEC-ST-024"
+	ErrCodeStorageTemplateUpdate                           = "DEC-ST-025"
+	ErrCodeStorageTemplateGet                              = "DEC-ST-026"
+	ErrCodeStorageTemplateSearch                           = "DEC-ST-027"
+	ErrCodeStorageTemplateDelete                           = "DEC-ST-028"
+	ErrCodeRouteBuilderUrlEmpty                            = "DEC-HTTP-001"
+	ErrCodeRouteBuilderVerbEmpty                           = "DEC-HTTP-002"
+	ErrCodeRouteBuilderBothHandleFuncAndHandlerEmpty       = "DEC-HTTP-003"
+	ErrCodeRouteBuilderSpecialMiddlewaresRequireSubrouting = "DEC-HTTP-004"
+	ErrCodeRouteBuilderDuplicate                           = "DEC-HTTP-005"
+	ErrCodeRouteBuilderRolesRequireAuth                    = "DEC-HTTP-006"
+	ErrCodeGrpcRequestInvalid                              = "DEC-GRPC-001"
+	ErrCodeGrpcUserForbidden                               = "DEC-GRPC-002"
+)
+
+var (
+	ErrDecisionProblemEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionProblemEmpty, "problem is empty").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionProblemInvalidId = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionProblemInvalidId, "invalid id").F(kit.KV{"id": id}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionProblemNotFound = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionProblemNotFound, "problem not found").F(kit.KV{"problemId": id}).Business().C(ctx).HttpSt(http.StatusNotFound).Err()
+	}
+	ErrDecisionProblemForbidden = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionProblemForbidden, "problem belongs to another user").F(kit.KV{"problemId": id}).Business().C(ctx).HttpSt(http.StatusForbidden).Err()
+	}
+	ErrDecisionNotFound = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionNotFound, "decision not found").F(kit.KV{"decisionId": id}).Business().C(ctx).HttpSt(http.StatusNotFound).Err()
+	}
+	ErrDecisionOptionNotFound = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionOptionNotFound, "option not found").F(kit.KV{"optionId": id}).Business().C(ctx).HttpSt(http.StatusNotFound).Err()
+	}
+	ErrDecisionQualityNotFound = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionQualityNotFound, "quality not found").F(kit.KV{"qualityId": id}).Business().C(ctx).HttpSt(http.StatusNotFound).Err()
+	}
+	ErrDecisionQualityKindInvalid = func(ctx context.Context, kind string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionQualityKindInvalid, "invalid quality kind").F(kit.KV{"kind": kind}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionProblemNameEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionProblemNameEmpty, "problem name is empty").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionOptionNameEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionOptionNameEmpty, "option name is empty").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionQualityNameEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionQualityNameEmpty, "quality name is empty").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrStorageInvalidConfig = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageInvalidConfig, "invalid storage config").C(ctx).Err()
+	}
+	ErrStorageProblemCreate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageProblemCreate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageProblemUpdate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageProblemUpdate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageProblemGet = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageProblemGet, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageDecisionCreate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageDecisionCreate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageDecisionGet = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageDecisionGet, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageDecisionMarshal = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageDecisionMarshal, "marshal result").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageDecisionUnmarshal = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageDecisionUnmarshal, "unmarshal result").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageProblemSearch = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageProblemSearch, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageProblemDelete = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageProblemDelete, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageOptionCreate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageOptionCreate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageOptionUpdate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageOptionUpdate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageOptionDelete = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageOptionDelete, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageQualityCreate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageQualityCreate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageQualityUpdate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageQualityUpdate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageQualityDelete = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageQualityDelete, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrDecisionMethodInvalid = func(ctx context.Context, method string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionMethodInvalid, "invalid decision method").F(kit.KV{"method": method}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionAhpEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionAhpEmpty, "AHP comparisons are empty").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionAhpMatrixInvalid = func(ctx context.Context, matrix, reason string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionAhpMatrixInvalid, "invalid comparison matrix: %s", reason).F(kit.KV{"matrix": matrix}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionAhpInconsistent = func(ctx context.Context, matrix string, cr float64) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionAhpInconsistent, "comparisons are too inconsistent").F(kit.KV{"matrix": matrix, "cr": cr}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrStorageProblemMarshal = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageProblemMarshal, "marshal problem").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageProblemUnmarshal = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageProblemUnmarshal, "unmarshal problem").Wrap(cause).C(ctx).Err()
+	}
+	ErrDecisionTopsisEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTopsisEmpty, "TOPSIS decision matrix is empty").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionTopsisInvalid = func(ctx context.Context, reason string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTopsisInvalid, "invalid TOPSIS decision matrix: %s", reason).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionMethodEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionMethodEmpty, "decision method or its code is empty").C(ctx).Err()
+	}
+	ErrDecisionMethodAlreadyRegistered = func(ctx context.Context, code string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionMethodAlreadyRegistered, "decision method already registered").F(kit.KV{"method": code}).C(ctx).Err()
+	}
+	ErrDecisionDistributionInvalid = func(ctx context.Context, qualityId, reason string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionDistributionInvalid, "invalid distribution: %s", reason).F(kit.KV{"qualityId": qualityId}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionSimulationIterationsInvalid = func(ctx context.Context, max int) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionSimulationIterationsInvalid, "simulation iterations must be in range [1, %d]", max).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionSensitivityRangeInvalid = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionSensitivityRangeInvalid, "sensitivity range must be in range (0, 1]").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionQualityImportanceInvalid = func(ctx context.Context, qualityId string, importance float64) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionQualityImportanceInvalid, "importance must be non-negative").F(kit.KV{"qualityId": qualityId, "importance": importance}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionQualityProbabilityInvalid = func(ctx context.Context, qualityId string, probability float64) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionQualityProbabilityInvalid, "probability must be in range [0, 1]").F(kit.KV{"qualityId": qualityId, "probability": probability}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionScoringInvalid = func(ctx context.Context, scoring string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionScoringInvalid, "invalid scoring model").F(kit.KV{"scoring": scoring}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionSmoothingInvalid = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionSmoothingInvalid, "smoothing must be non-negative").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionRatioUndefined = func(ctx context.Context, optionId string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionRatioUndefined, "ratio is undefined for option with zero cons, specify smoothing or use net scoring").F(kit.KV{"optionId": optionId}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionMemberInvalid = func(ctx context.Context, userId string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionMemberInvalid, "member must have valid user id and positive weight").F(kit.KV{"userId": userId}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionMemberDuplicate = func(ctx context.Context, userId string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionMemberDuplicate, "duplicate member").F(kit.KV{"userId": userId}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionAssessmentQualityInvalid = func(ctx context.Context, qualityId string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionAssessmentQualityInvalid, "assessed quality doesn't belong to the problem or assessed twice").F(kit.KV{"qualityId": qualityId}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionAggregationInvalid = func(ctx context.Context, aggregation string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionAggregationInvalid, "invalid aggregation").F(kit.KV{"aggregation": aggregation}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionProblemOwnerOnly = func(ctx context.Context, problemId string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionProblemOwnerOnly, "operation is allowed for the problem owner only").F(kit.KV{"problemId": problemId}).Business().C(ctx).HttpSt(http.StatusForbidden).Err()
+	}
+	ErrStorageMembersSet = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageMembersSet, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageMembersGet = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageMembersGet, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageAssessmentsSet = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageAssessmentsSet, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageAssessmentsGet = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageAssessmentsGet, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageProblemVersionGet = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageProblemVersionGet, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrDecisionProblemVersionNotFound = func(ctx context.Context, problemId string, version int) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionProblemVersionNotFound, "problem version not found").F(kit.KV{"problemId": problemId, "version": version}).Business().C(ctx).HttpSt(http.StatusNotFound).Err()
+	}
+	ErrDecisionTemplateInvalidId = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTemplateInvalidId, "invalid template id").F(kit.KV{"id": id}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionTemplateNotFound = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTemplateNotFound, "template not found").F(kit.KV{"templateId": id}).Business().C(ctx).HttpSt(http.StatusNotFound).Err()
+	}
+	ErrDecisionTemplateForbidden = func(ctx context.Context, id string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTemplateForbidden, "template is a system one or belongs to another user").F(kit.KV{"templateId": id}).Business().C(ctx).HttpSt(http.StatusForbidden).Err()
+	}
+	ErrDecisionTemplateNameEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTemplateNameEmpty, "template name empty").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionTemplateCriteriaEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTemplateCriteriaEmpty, "template must have at least one criterion").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionTemplateCriterionInvalid = func(ctx context.Context, name string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTemplateCriterionInvalid, "criterion must have unique name, valid kind, non-negative importance and probability in range [0, 1]").F(kit.KV{"name": name}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionTemplateFieldInvalid = func(ctx context.Context, name, fieldType string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionTemplateFieldInvalid, "field must have unique name and valid type").F(kit.KV{"name": name, "type": fieldType}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionOptionFieldRequired = func(ctx context.Context, option, field string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionOptionFieldRequired, "option field required").F(kit.KV{"option": option, "field": field}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionOptionFieldInvalid = func(ctx context.Context, option, field, fieldType string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionOptionFieldInvalid, "option field value invalid").F(kit.KV{"option": option, "field": field, "type": fieldType}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionOptionFieldUnknown = func(ctx context.Context, option, field string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionOptionFieldUnknown, "option field isn't defined by the template").F(kit.KV{"option": option, "field": field}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionExchangeFormatInvalid = func(ctx context.Context, format string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionExchangeFormatInvalid, "unsupported format").F(kit.KV{"format": format}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionImportParse = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionImportParse, "content can't be parsed").F(kit.KV{"details": cause.Error()}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionImportVersionUnsupported = func(ctx context.Context, version int) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionImportVersionUnsupported, "unsupported document version").F(kit.KV{"version": version}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionImportValueInvalid = func(ctx context.Context, path string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionImportValueInvalid, "invalid value").F(kit.KV{"path": path}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionImportCsvHeaderInvalid = func(ctx context.Context, column int, value string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionImportCsvHeaderInvalid, "invalid header").F(kit.KV{"column": column, "value": value}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionImportCsvCellInvalid = func(ctx context.Context, row, column int, value string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionImportCsvCellInvalid, "invalid value").F(kit.KV{"row": row, "column": column, "value": value}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionImportCsvEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionImportCsvEmpty, "header is missing").Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionExportCsvQualityDuplicate = func(ctx context.Context, option, quality string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionExportCsvQualityDuplicate, "option has several qualities of the same kind and name").F(kit.KV{"option": option, "quality": quality}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionExportEncode = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionExportEncode, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrDecisionOptionComparisonsSet = func(ctx context.Context, problemId string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionOptionComparisonsSet, "option can't be added, since the problem has comparisons of options").F(kit.KV{"problemId": problemId}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionOptionIdDuplicate = func(ctx context.Context, optionId string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionOptionIdDuplicate, "duplicate option id").F(kit.KV{"optionId": optionId}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionQualityIdDuplicate = func(ctx context.Context, qualityId string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionQualityIdDuplicate, "duplicate quality id").F(kit.KV{"qualityId": qualityId}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionSensitivityMethodUnsupported = func(ctx context.Context, method string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionSensitivityMethodUnsupported, "sensitivity analysis isn't supported by the method").F(kit.KV{"method": method}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionSensitivityQualitiesExceeded = func(ctx context.Context, max int) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionSensitivityQualitiesExceeded, "too many qualities for sensitivity analysis").F(kit.KV{"max": max}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionSensitivityEvaluationsExceeded = func(ctx context.Context, max int) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionSensitivityEvaluationsExceeded, "too many evaluations for sensitivity analysis").F(kit.KV{"max": max}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionSimulationMethodUnsupported = func(ctx context.Context, method string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionSimulationMethodUnsupported, "simulation isn't supported by the method").F(kit.KV{"method": method}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionProblemForeignIds = func(ctx context.Context, ids []string) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionProblemForeignIds, "ids belong to another problem").F(kit.KV{"ids": strings.Join(ids, ", ")}).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrDecisionImportTooLarge = func(ctx context.Context, max int) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionImportTooLarge, "content is too large").F(kit.KV{"max": max}).Business().C(ctx).HttpSt(http.StatusRequestEntityTooLarge).Err()
+	}
+	ErrStorageTemplateCreate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageTemplateCreate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageTemplateUpdate = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageTemplateUpdate, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageTemplateGet = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageTemplateGet, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageTemplateSearch = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageTemplateSearch, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrStorageTemplateDelete = func(ctx context.Context, cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeStorageTemplateDelete, "").Wrap(cause).C(ctx).Err()
+	}
+	ErrRouteBuilderUrlEmpty = func() error {
+		return kit.NewAppErrBuilder(ErrCodeRouteBuilderUrlEmpty, "route url empty").Err()
+	}
+	ErrRouteBuilderVerbEmpty = func(url string) error {
+		return kit.NewAppErrBuilder(ErrCodeRouteBuilderVerbEmpty, "route verb empty").F(kit.KV{"url": url}).Err()
+	}
+	ErrRouteBuilderBothHandleFuncAndHandlerEmpty = func(url string) error {
+		return kit.NewAppErrBuilder(ErrCodeRouteBuilderBothHandleFuncAndHandlerEmpty, "route handle function and handler empty").F(kit.KV{"url": url}).Err()
+	}
+	ErrRouteBuilderSpecialMiddlewaresRequireSubrouting = func(url string) error {
+		return kit.NewAppErrBuilder(ErrCodeRouteBuilderSpecialMiddlewaresRequireSubrouting, "route middlewares require subrouting").F(kit.KV{"url": url}).Err()
+	}
+	ErrRouteBuilderDuplicate = func(method, url string) error {
+		return kit.NewAppErrBuilder(ErrCodeRouteBuilderDuplicate, "route registered twice").F(kit.KV{"method": method, "url": url}).Err()
+	}
+	ErrRouteBuilderRolesRequireAuth = func(url string) error {
+		return kit.NewAppErrBuilder(ErrCodeRouteBuilderRolesRequireAuth, "route roles and permissions require authentication").F(kit.KV{"url": url}).Err()
+	}
+	ErrDecisionServiceClosed = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeDecisionServiceClosed, "service is closing").C(ctx).HttpSt(http.StatusServiceUnavailable).Err()
+	}
+	ErrGrpcRequestInvalid = func(ctx context.Context, details kit.KV) error {
+		return kit.NewAppErrBuilder(ErrCodeGrpcRequestInvalid, "request invalid").F(details).Business().C(ctx).GrpcSt(uint32(codes.InvalidArgument)).Err()
+	}
+	ErrGrpcUserForbidden = func(ctx context.Context, userId string) error {
+		return kit.NewAppErrBuilder(ErrCodeGrpcUserForbidden, "request on behalf of another user").F(kit.KV{"userId": userId}).Business().C(ctx).GrpcSt(uint32(codes.PermissionDenied)).Err()
+	}
+)
@@ -0,0 +1,15 @@
+package errors
+
+import (
+	"embed"
+	"github.com/mikhailbolshakov/decision/kit/i18n"
+)
+
+// locales are catalogs of decision errors and request validation messages
+//
+//go:embed locales/*.yaml
+var locales embed.FS
+
+func init() {
+	i18n.Default.MustLoad(locales, "locales")
+}
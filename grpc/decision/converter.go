@@ -0,0 +1,201 @@
+package decision
+
+import (
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/kit"
+	pb "github.com/mikhailbolshakov/decision/proto/decision"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) toProblemDomain(problem *pb.Problem) *domain.Problem {
+	if problem == nil {
+		return nil
+	}
+	r := &domain.Problem{
+		Id:      problem.Id,
+		Name:    problem.Name,
+		Method:  problem.Method,
+		Options: make([]*domain.Option, 0, len(problem.Options)),
+	}
+	if problem.ProsCons != nil {
+		r.ProsCons = &domain.ProsCons{
+			Scoring:   problem.ProsCons.Scoring,
+			Smoothing: problem.ProsCons.Smoothing,
+		}
+	}
+	if problem.Ahp != nil {
+		r.Ahp = &domain.Ahp{
+			Criteria:            problem.Ahp.Criteria,
+			CriteriaComparisons: s.toMatrixDomain(problem.Ahp.CriteriaComparisons),
+		}
+		for _, m := range problem.Ahp.OptionComparisons {
+			r.Ahp.OptionComparisons = append(r.Ahp.OptionComparisons, s.toMatrixDomain(m))
+		}
+	}
+	if problem.Topsis != nil {
+		r.Topsis = &domain.Topsis{Scores: s.toMatrixDomain(problem.Topsis.Scores)}
+		for _, cr := range problem.Topsis.Criteria {
+			r.Topsis.Criteria = append(r.Topsis.Criteria, &domain.TopsisCriterion{Name: cr.Name, Weight: cr.Weight, Direction: cr.Direction})
+		}
+	}
+	if problem.Simulation != nil {
+		r.Simulation = &domain.Simulation{
+			Iterations: int(problem.Simulation.Iterations),
+			Seed:       problem.Simulation.Seed,
+		}
+	}
+	for _, op := range problem.Options {
+		r.Options = append(r.Options, &domain.Option{
+			Id:     op.Id,
+			Name:   op.Name,
+			Fields: op.Fields,
+			Pros:   s.toQualitiesDomain(op.Pros),
+			Cons:   s.toQualitiesDomain(op.Cons),
+		})
+	}
+	return r
+}
+
+func (s *Server) toMatrixDomain(m *pb.Matrix) [][]float64 {
+	if m == nil {
+		return nil
+	}
+	r := make([][]float64, 0, len(m.Rows))
+	for _, row := range m.Rows {
+		r = append(r, row.GetValues())
+	}
+	return r
+}
+
+func (s *Server) toQualitiesDomain(qualities []*pb.Quality) []*domain.Quality {
+	r := make([]*domain.Quality, 0, len(qualities))
+	for _, q := range qualities {
+		r = append(r, &domain.Quality{
+			Id:              q.Id,
+			Name:            q.Name,
+			Importance:      q.Importance,
+			Probability:     q.Probability,
+			ImportanceDist:  s.toDistributionDomain(q.ImportanceDist),
+			ProbabilityDist: s.toDistributionDomain(q.ProbabilityDist),
+		})
+	}
+	return r
+}
+
+func (s *Server) toDistributionDomain(d *pb.Distribution) *domain.Distribution {
+	if d == nil {
+		return nil
+	}
+	return &domain.Distribution{
+		Type:   d.Type,
+		Min:    d.Min,
+		Max:    d.Max,
+		Mode:   d.Mode,
+		Mean:   d.Mean,
+		StdDev: d.StdDev,
+		Alpha:  d.Alpha,
+		Beta:   d.Beta,
+	}
+}
+
+func (s *Server) toDistributionPb(d *domain.Distribution) *pb.Distribution {
+	if d == nil {
+		return nil
+	}
+	return &pb.Distribution{
+		Type:   d.Type,
+		Min:    d.Min,
+		Max:    d.Max,
+		Mode:   d.Mode,
+		Mean:   d.Mean,
+		StdDev: d.StdDev,
+		Alpha:  d.Alpha,
+		Beta:   d.Beta,
+	}
+}
+
+func (s *Server) toDecisionPb(d *domain.Decision) *pb.Decision {
+	if d == nil {
+		return nil
+	}
+	r := &pb.Decision{
+		Id:               d.Id,
+		ProblemId:        d.ProblemId,
+		ProblemVersion:   int32(d.ProblemVersion),
+		UserId:           d.UserId,
+		Method:           d.Result.Method,
+		OptionsRating:    d.Result.OptionsRating,
+		Scoring:          d.Result.Scoring,
+		Smoothing:        d.Result.Smoothing,
+		ConsistencyRatio: d.Result.ConsistencyRatio,
+		Simulation:       s.toSimulationResultPb(d.Result.Simulation),
+		Explanation:      s.toExplanationPb(d.Result.Explanation),
+	}
+	if !d.CreatedAt.IsZero() {
+		r.CreatedAt = timestamppb.New(d.CreatedAt)
+	}
+	return r
+}
+
+func (s *Server) toSimulationResultPb(sr *domain.SimulationResult) *pb.SimulationResult {
+	if sr == nil {
+		return nil
+	}
+	r := &pb.SimulationResult{
+		Iterations: int32(sr.Iterations),
+		Seed:       sr.Seed,
+		Options:    make(map[string]*pb.OptionSimulation, len(sr.Options)),
+	}
+	for id, o := range sr.Options {
+		r.Options[id] = &pb.OptionSimulation{
+			Mean:            o.Mean,
+			StdDev:          o.StdDev,
+			P5:              o.P5,
+			P25:             o.P25,
+			P50:             o.P50,
+			P75:             o.P75,
+			P95:             o.P95,
+			ProbabilityBest: o.ProbabilityBest,
+		}
+	}
+	return r
+}
+
+func (s *Server) toExplanationPb(e *domain.Explanation) *pb.Explanation {
+	if e == nil {
+		return nil
+	}
+	r := &pb.Explanation{
+		Ranking: make([]*pb.OptionExplanation, 0, len(e.Ranking)),
+		Margin:  e.Margin,
+		Summary: e.Summary,
+	}
+	for _, oe := range e.Ranking {
+		o := &pb.OptionExplanation{
+			OptionId:  oe.OptionId,
+			Name:      oe.Name,
+			Rank:      int32(oe.Rank),
+			Rating:    oe.Rating,
+			ProsTotal: oe.ProsTotal,
+			ConsTotal: oe.ConsTotal,
+		}
+		for _, c := range oe.Contributions {
+			o.Contributions = append(o.Contributions, &pb.QualityContribution{
+				QualityId: c.QualityId,
+				Name:      c.Name,
+				Kind:      c.Kind,
+				Weight:    c.Weight,
+				Share:     c.Share,
+			})
+		}
+		r.Ranking = append(r.Ranking, o)
+	}
+	return r
+}
+
+func (s *Server) toErrorPb(err error) *pb.Error {
+	if appErr, ok := kit.IsAppErr(err); ok {
+		return &pb.Error{Code: appErr.Code(), Message: appErr.Message()}
+	}
+	return &pb.Error{Message: err.Error()}
+}
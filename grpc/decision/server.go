@@ -0,0 +1,97 @@
+package decision
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	pb "github.com/mikhailbolshakov/decision/proto/decision"
+	"io"
+)
+
+// Server implements gRPC decision service
+type Server struct {
+	pb.UnimplementedDecisionServiceServer
+	decisionService domain.DecisionService
+	adminRole       string
+}
+
+// NewServer creates gRPC decision service
+// adminRole allows making decisions on behalf of any user, if empty no one has such access
+func NewServer(decisionService domain.DecisionService, adminRole string) *Server {
+	return &Server{decisionService: decisionService, adminRole: adminRole}
+}
+
+func (s *Server) l() kit.CLogger {
+	return decision.L().Pr("grpc").Cmp("decision-srv")
+}
+
+func (s *Server) MakeDecision(ctx context.Context, rq *pb.MakeDecisionRequest) (*pb.Decision, error) {
+	s.l().C(ctx).Mth("make-decision").Dbg()
+	return s.makeDecision(ctx, rq)
+}
+
+// EvaluateBatch makes a decision for each request of the stream
+// requests are processed sequentially, an error of a request is sent as its result
+func (s *Server) EvaluateBatch(stream pb.DecisionService_EvaluateBatchServer) error {
+	ctx := stream.Context()
+	l := s.l().C(ctx).Mth("evaluate-batch")
+	var index int32
+	for {
+		rq, err := stream.Recv()
+		if err == io.EOF {
+			l.F(kit.KV{"count": index}).Dbg("completed")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rs := &pb.BatchResult{Index: index}
+		if d, err := s.makeDecision(ctx, rq); err != nil {
+			rs.Result = &pb.BatchResult_Error{Error: s.toErrorPb(err)}
+		} else {
+			rs.Result = &pb.BatchResult_Decision{Decision: d}
+		}
+		if err := stream.Send(rs); err != nil {
+			return err
+		}
+		index++
+	}
+}
+
+func (s *Server) makeDecision(ctx context.Context, rq *pb.MakeDecisionRequest) (*pb.Decision, error) {
+	if err := s.validate(ctx, rq); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, rq.UserId); err != nil {
+		return nil, err
+	}
+	d, err := s.decisionService.MakeDecision(ctx, rq.UserId, s.toProblemDomain(rq.Problem))
+	if err != nil {
+		return nil, err
+	}
+	return s.toDecisionPb(d), nil
+}
+
+// authorize checks a decision is stored for the authenticated user, unless the caller is admin
+func (s *Server) authorize(ctx context.Context, userId string) error {
+	if userId == "" {
+		return nil
+	}
+	rCtx, ok := kit.Request(ctx)
+	if !ok {
+		return errors.ErrGrpcUserForbidden(ctx, userId)
+	}
+	if rCtx.GetUserId() == userId {
+		return nil
+	}
+	if s.adminRole != "" {
+		for _, role := range rCtx.GetRoles() {
+			if role == s.adminRole {
+				return nil
+			}
+		}
+	}
+	return errors.ErrGrpcUserForbidden(ctx, userId)
+}
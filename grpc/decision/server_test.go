@@ -0,0 +1,342 @@
+package decision
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/domain/decision/impl"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/auth"
+	kitGrpc "github.com/mikhailbolshakov/decision/kit/grpc"
+	"github.com/mikhailbolshakov/decision/mocks"
+	pb "github.com/mikhailbolshakov/decision/proto/decision"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+const adminRole = "admin"
+
+type serverTestSuite struct {
+	kit.Suite
+	decisionService *mocks.DecisionService
+	verifier        *mocks.Verifier
+	client          pb.DecisionServiceClient
+	userId          string
+}
+
+func (s *serverTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *serverTestSuite) SetupTest() {
+	s.decisionService = &mocks.DecisionService{}
+	s.userId = kit.NewId()
+	s.verifier = &mocks.Verifier{}
+	s.verifier.On("Verify", mock.Anything, "user").Return(&auth.Claims{Subject: s.userId}, nil)
+	s.verifier.On("Verify", mock.Anything, "admin").Return(&auth.Claims{Subject: kit.NewId(), Roles: []string{adminRole}}, nil)
+	s.client = s.serve(s.decisionService)
+}
+
+// serve starts a server backed by the decision service and returns a client connected to it
+func (s *serverTestSuite) serve(decisionService domain.DecisionService) pb.DecisionServiceClient {
+	srv := kitGrpc.NewServer(&kitGrpc.Config{}, decision.LF()).WithAuth(s.verifier)
+	pb.RegisterDecisionServiceServer(srv.Srv, NewServer(decisionService, adminRole))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	s.NoError(err)
+	go func() { _ = srv.Srv.Serve(ln) }()
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.NoError(err)
+	s.T().Cleanup(func() {
+		_ = conn.Close()
+		srv.Close()
+	})
+	return pb.NewDecisionServiceClient(conn)
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(serverTestSuite))
+}
+
+// serveImpl starts a server backed by the real decision service, so that only guest decisions can be made
+func (s *serverTestSuite) serveImpl() pb.DecisionServiceClient {
+	registry := impl.NewMethodRegistry()
+	for _, m := range impl.BuiltInMethods() {
+		s.NoError(registry.Register(s.Ctx, m))
+	}
+	return s.serve(impl.NewDecisionService(registry, &mocks.ProblemStorage{}, &mocks.DecisionStorage{}))
+}
+
+// token returns a context the call is authenticated with the token
+func (s *serverTestSuite) token(token string) context.Context {
+	return metadata.AppendToOutgoingContext(s.Ctx, kitGrpc.MDAuthorization, "Bearer "+token)
+}
+
+func (s *serverTestSuite) request(name string) *pb.MakeDecisionRequest {
+	return &pb.MakeDecisionRequest{
+		UserId: s.userId,
+		Problem: &pb.Problem{
+			Name:   name,
+			Method: domain.MethodProsCons,
+			Options: []*pb.Option{
+				{Name: "first", Pros: []*pb.Quality{{Name: "pro", Importance: 5, Probability: 1}}},
+				{Name: "second", Cons: []*pb.Quality{{Name: "con", Importance: 3, Probability: 0.5}}},
+			},
+		},
+	}
+}
+
+func (s *serverTestSuite) decision(rq *pb.MakeDecisionRequest) *domain.Decision {
+	return &domain.Decision{
+		Id:     kit.NewId(),
+		UserId: rq.UserId,
+		Result: domain.DecisionResult{
+			Method:        rq.Problem.Method,
+			OptionsRating: map[string]float64{"first": 1, "second": 0},
+		},
+		CreatedAt: kit.Now(),
+	}
+}
+
+func (s *serverTestSuite) Test_MakeDecision_Ok() {
+	rq := s.request("problem")
+	d := s.decision(rq)
+	s.decisionService.On("MakeDecision", mock.Anything, rq.UserId, mock.MatchedBy(func(p *domain.Problem) bool {
+		return p.Name == "problem" && len(p.Options) == 2 && p.Options[0].Pros[0].Importance == 5
+	})).Return(d, nil)
+
+	rs, err := s.client.MakeDecision(s.token("user"), rq)
+	s.NoError(err)
+	s.Equal(d.Id, rs.Id)
+	s.Equal(d.UserId, rs.UserId)
+	s.Equal(d.Result.Method, rs.Method)
+	s.Equal(d.Result.OptionsRating, rs.OptionsRating)
+	s.Equal(d.CreatedAt.UnixMilli(), rs.CreatedAt.AsTime().UnixMilli())
+}
+
+func (s *serverTestSuite) Test_MakeDecision_Ahp() {
+	rq := s.request("problem")
+	rq.Problem.Method = domain.MethodAhp
+	rq.Problem.Options[0].Fields = map[string]string{"price": "10"}
+	rq.Problem.Ahp = &pb.Ahp{
+		Criteria:            []string{"price"},
+		CriteriaComparisons: &pb.Matrix{Rows: []*pb.Matrix_Row{{Values: []float64{1}}}},
+		OptionComparisons:   []*pb.Matrix{{Rows: []*pb.Matrix_Row{{Values: []float64{1, 3}}, {Values: []float64{1.0 / 3, 1}}}}},
+	}
+	rq.Problem.Simulation = &pb.Simulation{Iterations: 10}
+	d := s.decision(rq)
+	d.Result.ConsistencyRatio = 0.05
+	d.Result.Simulation = &domain.SimulationResult{Iterations: 10, Seed: 1, Options: map[string]*domain.OptionSimulation{"first": {Mean: 0.75, ProbabilityBest: 1}}}
+	d.Result.Explanation = &domain.Explanation{
+		Ranking: []*domain.OptionExplanation{{OptionId: "first", Rank: 1, Rating: 0.75, Contributions: []*domain.QualityContribution{{QualityId: "q", Kind: domain.QualityKindPro, Share: 1}}}},
+		Margin:  0.5,
+		Summary: "first wins",
+	}
+	var problem *domain.Problem
+	s.decisionService.On("MakeDecision", mock.Anything, rq.UserId, mock.Anything).
+		Run(func(args mock.Arguments) { problem = args.Get(2).(*domain.Problem) }).
+		Return(d, nil)
+
+	rs, err := s.client.MakeDecision(s.token("user"), rq)
+	s.NoError(err)
+	s.Equal(0.05, rs.ConsistencyRatio)
+	s.Equal(int32(10), rs.Simulation.Iterations)
+	s.Equal(0.75, rs.Simulation.Options["first"].Mean)
+	s.Equal(1.0, rs.Simulation.Options["first"].ProbabilityBest)
+	s.Equal("first wins", rs.Explanation.Summary)
+	s.Equal(0.5, rs.Explanation.Margin)
+	s.Equal(int32(1), rs.Explanation.Ranking[0].Rank)
+	s.Equal("q", rs.Explanation.Ranking[0].Contributions[0].QualityId)
+	s.Equal([]string{"price"}, problem.Ahp.Criteria)
+	s.Equal([][]float64{{1}}, problem.Ahp.CriteriaComparisons)
+	s.Equal([][][]float64{{{1, 3}, {1.0 / 3, 1}}}, problem.Ahp.OptionComparisons)
+	s.Equal("10", problem.Options[0].Fields["price"])
+	s.Equal(10, problem.Simulation.Iterations)
+}
+
+func (s *serverTestSuite) Test_MakeDecision_Topsis() {
+	rq := s.request("problem")
+	rq.Problem.Method = domain.MethodTopsis
+	rq.Problem.Topsis = &pb.Topsis{
+		Criteria: []*pb.TopsisCriterion{{Name: "price", Weight: 1, Direction: domain.TopsisCost}},
+		Scores:   &pb.Matrix{Rows: []*pb.Matrix_Row{{Values: []float64{10}}, {Values: []float64{20}}}},
+	}
+	var problem *domain.Problem
+	s.decisionService.On("MakeDecision", mock.Anything, rq.UserId, mock.Anything).
+		Run(func(args mock.Arguments) { problem = args.Get(2).(*domain.Problem) }).
+		Return(s.decision(rq), nil)
+
+	_, err := s.client.MakeDecision(s.token("user"), rq)
+	s.NoError(err)
+	s.Equal(domain.TopsisCost, problem.Topsis.Criteria[0].Direction)
+	s.Equal([][]float64{{10}, {20}}, problem.Topsis.Scores)
+}
+
+func (s *serverTestSuite) Test_MakeDecision_TopsisInvalid() {
+	rq := s.request("problem")
+	rq.UserId = ""
+	rq.Problem.Method = domain.MethodTopsis
+	rq.Problem.Topsis = &pb.Topsis{
+		Criteria: []*pb.TopsisCriterion{{Weight: -1, Direction: "any"}},
+		Scores:   &pb.Matrix{Rows: []*pb.Matrix_Row{{Values: []float64{10}}, {Values: []float64{20}}}},
+	}
+
+	var trailer metadata.MD
+	_, err := s.serveImpl().MakeDecision(s.token("user"), rq, grpc.Trailer(&trailer))
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	s.Equal([]string{errors.ErrCodeDecisionTopsisInvalid}, trailer.Get(kitGrpc.MDErrorCode))
+}
+
+func (s *serverTestSuite) Test_MakeDecision_Invalid() {
+	rq := s.request("problem")
+	rq.UserId = "invalid"
+	rq.Problem.Options[1].Cons[0].Probability = 2
+
+	var trailer metadata.MD
+	_, err := s.client.MakeDecision(s.token("user"), rq, grpc.Trailer(&trailer))
+	s.Error(err)
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	s.Equal([]string{errors.ErrCodeGrpcRequestInvalid}, trailer.Get(kitGrpc.MDErrorCode))
+	s.decisionService.AssertNotCalled(s.T(), "MakeDecision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *serverTestSuite) Test_MakeDecision_ServiceError() {
+	rq := s.request("problem")
+	s.decisionService.On("MakeDecision", mock.Anything, rq.UserId, mock.Anything).
+		Return(nil, errors.ErrDecisionServiceClosed(s.Ctx))
+
+	_, err := s.client.MakeDecision(s.token("user"), rq)
+	s.Error(err)
+	s.Equal(codes.Unavailable, status.Code(err))
+}
+
+func (s *serverTestSuite) Test_MakeDecision_Unauthenticated() {
+	_, err := s.client.MakeDecision(s.Ctx, s.request("problem"))
+	s.Equal(codes.Unauthenticated, status.Code(err))
+	s.decisionService.AssertNotCalled(s.T(), "MakeDecision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *serverTestSuite) Test_MakeDecision_AnotherUser() {
+	rq := s.request("problem")
+	rq.UserId = kit.NewId()
+
+	var trailer metadata.MD
+	_, err := s.client.MakeDecision(s.token("user"), rq, grpc.Trailer(&trailer))
+	s.Equal(codes.PermissionDenied, status.Code(err))
+	s.Equal([]string{errors.ErrCodeGrpcUserForbidden}, trailer.Get(kitGrpc.MDErrorCode))
+	s.decisionService.AssertNotCalled(s.T(), "MakeDecision", mock.Anything, mock.Anything, mock.Anything)
+
+	// admin makes decisions on behalf of any user
+	s.decisionService.On("MakeDecision", mock.Anything, rq.UserId, mock.Anything).Return(s.decision(rq), nil)
+	_, err = s.client.MakeDecision(s.token("admin"), rq)
+	s.NoError(err)
+}
+
+func (s *serverTestSuite) Test_MakeDecision_Guest() {
+	rq := s.request("problem")
+	rq.UserId = ""
+	s.decisionService.On("MakeDecision", mock.Anything, "", mock.Anything).Return(s.decision(rq), nil)
+	_, err := s.client.MakeDecision(s.token("user"), rq)
+	s.NoError(err)
+	s.decisionService.AssertExpectations(s.T())
+}
+
+func (s *serverTestSuite) Test_MakeDecision_SimulationDistributions() {
+	client := s.serveImpl()
+
+	rq := s.request("problem")
+	rq.UserId = ""
+	rq.Problem.Options[0].Pros[0].ImportanceDist = &pb.Distribution{Type: domain.DistUniform, Min: 1, Max: 9}
+	rq.Problem.Options[1].Cons[0].ProbabilityDist = &pb.Distribution{Type: domain.DistTriangular, Min: 0.1, Mode: 0.5, Max: 0.9}
+	seed := int64(1)
+	rq.Problem.Simulation = &pb.Simulation{Iterations: 100, Seed: &seed}
+
+	rs, err := client.MakeDecision(s.token("user"), rq)
+	s.NoError(err)
+	s.NotNil(rs.Simulation)
+	s.Len(rs.Simulation.Options, 2)
+	for _, o := range rs.Simulation.Options {
+		s.Greater(o.StdDev, 0.0)
+		s.Greater(o.P95, o.P5)
+	}
+}
+
+func (s *serverTestSuite) Test_MakeDecision_DistributionInvalid() {
+	rq := s.request("problem")
+	rq.UserId = ""
+	rq.Problem.Options[0].Pros[0].ImportanceDist = &pb.Distribution{Type: "unknown"}
+
+	var trailer metadata.MD
+	_, err := s.serveImpl().MakeDecision(s.token("user"), rq, grpc.Trailer(&trailer))
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	s.Equal([]string{errors.ErrCodeDecisionDistributionInvalid}, trailer.Get(kitGrpc.MDErrorCode))
+}
+
+func (s *serverTestSuite) Test_MakeDecision_DuplicateIds() {
+	rq := s.request("problem")
+	rq.UserId = ""
+	id := kit.NewId()
+	rq.Problem.Options[0].Id, rq.Problem.Options[1].Id = id, id
+
+	var trailer metadata.MD
+	_, err := s.serveImpl().MakeDecision(s.token("user"), rq, grpc.Trailer(&trailer))
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	s.Equal([]string{errors.ErrCodeDecisionOptionIdDuplicate}, trailer.Get(kitGrpc.MDErrorCode))
+
+	rq.Problem.Options[1].Id = ""
+	rq.Problem.Options[0].Pros[0].Id, rq.Problem.Options[1].Cons[0].Id = id, id
+	_, err = s.serveImpl().MakeDecision(s.token("user"), rq, grpc.Trailer(&trailer))
+	s.Equal(codes.InvalidArgument, status.Code(err))
+	s.Equal([]string{errors.ErrCodeDecisionQualityIdDuplicate}, trailer.Get(kitGrpc.MDErrorCode))
+}
+
+func (s *serverTestSuite) Test_EvaluateBatch() {
+	ok1, ok2 := s.request("first"), s.request("second")
+	invalid := s.request("invalid")
+	invalid.Problem.Options = nil
+	failed := s.request("failed")
+	d1, d2 := s.decision(ok1), s.decision(ok2)
+	named := func(name string) interface{} {
+		return mock.MatchedBy(func(p *domain.Problem) bool { return p.Name == name })
+	}
+	s.decisionService.On("MakeDecision", mock.Anything, s.userId, named("first")).Return(d1, nil)
+	s.decisionService.On("MakeDecision", mock.Anything, s.userId, named("second")).Return(d2, nil)
+	s.decisionService.On("MakeDecision", mock.Anything, s.userId, named("failed")).
+		Return(nil, errors.ErrDecisionServiceClosed(s.Ctx))
+
+	ctx, cancel := context.WithTimeout(s.token("user"), time.Second*5)
+	defer cancel()
+	stream, err := s.client.EvaluateBatch(ctx)
+	s.NoError(err)
+	for _, rq := range []*pb.MakeDecisionRequest{ok1, invalid, failed, ok2} {
+		s.NoError(stream.Send(rq))
+	}
+	s.NoError(stream.CloseSend())
+
+	var results []*pb.BatchResult
+	for {
+		rs, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		s.NoError(err)
+		results = append(results, rs)
+	}
+	s.Len(results, 4)
+	for i, rs := range results {
+		s.Equal(int32(i), rs.Index)
+	}
+	s.Equal(d1.Id, results[0].GetDecision().Id)
+	s.Equal(errors.ErrCodeGrpcRequestInvalid, results[1].GetError().Code)
+	s.Equal(errors.ErrCodeDecisionServiceClosed, results[2].GetError().Code)
+	s.Equal(d2.Id, results[3].GetDecision().Id)
+}
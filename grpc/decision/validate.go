@@ -0,0 +1,39 @@
+package decision
+
+import (
+	"context"
+	"fmt"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	pb "github.com/mikhailbolshakov/decision/proto/decision"
+)
+
+// validate checks the request envelope, the problem itself is validated by the decision service
+// the same way for all transports
+func (s *Server) validate(ctx context.Context, rq *pb.MakeDecisionRequest) error {
+	details := kit.KV{}
+	if rq.UserId != "" {
+		if err := kit.ValidateUUIDs(rq.UserId); err != nil {
+			details["userId"] = "must be a valid UUID"
+		}
+	}
+	if rq.Problem == nil {
+		details["problem"] = "is required"
+	} else {
+		if len(rq.Problem.Name) > 256 {
+			details["problem.name"] = "must be at most 256 characters"
+		}
+		if len(rq.Problem.Options) == 0 {
+			details["problem.options"] = "is required"
+		}
+		for i, op := range rq.Problem.Options {
+			if op == nil {
+				details[fmt.Sprintf("problem.options[%d]", i)] = "is required"
+			}
+		}
+	}
+	if len(details) > 0 {
+		return errors.ErrGrpcRequestInvalid(ctx, details)
+	}
+	return nil
+}
@@ -1,26 +1,86 @@
 package decision
 
 import (
+	"bytes"
 	"github.com/mikhailbolshakov/decision"
 	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
 	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"io"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
+const (
+	maxPageSize   = 100
+	maxImportSize = 10 << 20
+)
+
+// qualityKinds maps URL path segment to quality kind
+var qualityKinds = map[string]string{
+	"pros": domain.QualityKindPro,
+	"cons": domain.QualityKindCon,
+}
+
 type Controller interface {
 	kitHttp.Controller
 	MakeDecision(http.ResponseWriter, *http.Request)
 	MakeDecisionGuest(http.ResponseWriter, *http.Request)
+	MakeDecisionByProblem(http.ResponseWriter, *http.Request)
+	AnalyzeSensitivity(http.ResponseWriter, *http.Request)
+	GetDecisionsByProblem(http.ResponseWriter, *http.Request)
+	GetMethods(http.ResponseWriter, *http.Request)
+	CreateProblem(http.ResponseWriter, *http.Request)
+	UpdateProblem(http.ResponseWriter, *http.Request)
+	GetProblem(http.ResponseWriter, *http.Request)
+	SearchProblems(http.ResponseWriter, *http.Request)
+	DeleteProblem(http.ResponseWriter, *http.Request)
+	AddOption(http.ResponseWriter, *http.Request)
+	UpdateOption(http.ResponseWriter, *http.Request)
+	DeleteOption(http.ResponseWriter, *http.Request)
+	AddQuality(http.ResponseWriter, *http.Request)
+	UpdateQuality(http.ResponseWriter, *http.Request)
+	DeleteQuality(http.ResponseWriter, *http.Request)
+	SetMembers(http.ResponseWriter, *http.Request)
+	GetMembers(http.ResponseWriter, *http.Request)
+	SetAssessments(http.ResponseWriter, *http.Request)
+	GetAssessments(http.ResponseWriter, *http.Request)
+	MakeGroupDecision(http.ResponseWriter, *http.Request)
+	GetProblemVersions(http.ResponseWriter, *http.Request)
+	GetProblemVersion(http.ResponseWriter, *http.Request)
+	DiffProblemVersions(http.ResponseWriter, *http.Request)
+	CreateTemplate(http.ResponseWriter, *http.Request)
+	UpdateTemplate(http.ResponseWriter, *http.Request)
+	GetTemplate(http.ResponseWriter, *http.Request)
+	SearchTemplates(http.ResponseWriter, *http.Request)
+	DeleteTemplate(http.ResponseWriter, *http.Request)
+	CreateProblemFromTemplate(http.ResponseWriter, *http.Request)
+	ExportProblem(http.ResponseWriter, *http.Request)
+	ImportProblem(http.ResponseWriter, *http.Request)
 }
 
 type ctrlImpl struct {
 	kitHttp.BaseController
 	decisionService domain.DecisionService
+	problemService  domain.ProblemService
+	groupService    domain.GroupService
+	templateService domain.TemplateService
+	exchangeService domain.ExchangeService
+	codec           domain.ProblemCodec
 }
 
-func NewController(decisionService domain.DecisionService) Controller {
+func NewController(decisionService domain.DecisionService, problemService domain.ProblemService, groupService domain.GroupService,
+	templateService domain.TemplateService, exchangeService domain.ExchangeService, codec domain.ProblemCodec) Controller {
 	return &ctrlImpl{
 		decisionService: decisionService,
+		problemService:  problemService,
+		groupService:    groupService,
+		templateService: templateService,
+		exchangeService: exchangeService,
+		codec:           codec,
 		BaseController:  kitHttp.BaseController{Logger: decision.LF()},
 	}
 }
@@ -39,6 +99,10 @@ func (c *ctrlImpl) MakeDecision(w http.ResponseWriter, r *http.Request) {
 		c.RespondError(w, err)
 		return
 	}
+	if err = c.validateDecisionProblem(ctx, "options", rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
 
 	res, err := c.decisionService.MakeDecision(ctx, userId, c.toProblemDomain(rq))
 	if err != nil {
@@ -57,8 +121,36 @@ func (c *ctrlImpl) MakeDecisionGuest(w http.ResponseWriter, r *http.Request) {
 		c.RespondError(w, err)
 		return
 	}
+	if err := c.validateDecisionProblem(ctx, "options", rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.decisionService.MakeDecision(ctx, "", c.toProblemDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toDecisionResultApi(res))
+}
+
+func (c *ctrlImpl) MakeDecisionByProblem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
 
-	res, err := c.decisionService.MakeDecision(ctx, "", nil)
+	res, err := c.decisionService.MakeDecisionByProblem(ctx, userId, problemId)
 	if err != nil {
 		c.RespondError(w, err)
 		return
@@ -66,3 +158,921 @@ func (c *ctrlImpl) MakeDecisionGuest(w http.ResponseWriter, r *http.Request) {
 
 	c.RespondOK(w, c.toDecisionResultApi(res))
 }
+
+func (c *ctrlImpl) AnalyzeSensitivity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if _, err := c.UserIdVar(ctx, r, "userId"); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &SensitivityRequest{}
+	if err := c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+	if err := c.validateDecisionProblem(ctx, "problem.options", rq.Problem); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.decisionService.AnalyzeSensitivity(ctx, c.toProblemDomain(rq.Problem), &domain.SensitivityParams{Range: rq.Range})
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toSensitivityResultApi(res))
+}
+
+func (c *ctrlImpl) GetDecisionsByProblem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	// decisions are available for the owner and group members
+	res, err := c.groupService.GetDecisions(ctx, userId, problemId)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toDecisionsApi(res))
+}
+
+func (c *ctrlImpl) GetMethods(w http.ResponseWriter, r *http.Request) {
+	c.RespondOK(w, c.toMethodsApi(c.decisionService.GetMethods(r.Context())))
+}
+
+func (c *ctrlImpl) CreateProblem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &Problem{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.problemService.CreateProblem(ctx, userId, c.toProblemDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemApi(res))
+}
+
+func (c *ctrlImpl) UpdateProblem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &Problem{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+	rq.Id = problemId
+
+	res, err := c.problemService.UpdateProblem(ctx, userId, c.toProblemDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemApi(res))
+}
+
+func (c *ctrlImpl) GetProblem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.problemService.GetProblem(ctx, userId, problemId)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemApi(res))
+}
+
+func (c *ctrlImpl) SearchProblems(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	size, index, err := c.FormPaging(ctx, r, kit.IntPtr(maxPageSize))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	sortBy, err := c.FormSort(ctx, r, "sortBy", true)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	criteria := &domain.ProblemSearchCriteria{
+		UserId: userId,
+	}
+	criteria.SortBy = sortBy
+	if size != nil {
+		criteria.Size = *size
+	}
+	if index != nil {
+		criteria.Index = *index
+	}
+
+	res, err := c.problemService.SearchProblems(ctx, criteria)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemSearchResponseApi(res))
+}
+
+func (c *ctrlImpl) DeleteProblem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	if err = c.problemService.DeleteProblem(ctx, userId, problemId); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, kitHttp.EmptyOkResponse)
+}
+
+func (c *ctrlImpl) AddOption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &Option{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.problemService.AddOption(ctx, userId, problemId, c.toOptionDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toOptionApi(res))
+}
+
+func (c *ctrlImpl) UpdateOption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	optionId, err := c.VarUUID(ctx, r, "optionId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &Option{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+	rq.Id = optionId
+
+	res, err := c.problemService.UpdateOption(ctx, userId, problemId, c.toOptionDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toOptionApi(res))
+}
+
+func (c *ctrlImpl) DeleteOption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	optionId, err := c.VarUUID(ctx, r, "optionId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	if err = c.problemService.DeleteOption(ctx, userId, problemId, optionId); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, kitHttp.EmptyOkResponse)
+}
+
+func (c *ctrlImpl) AddQuality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	optionId, err := c.VarUUID(ctx, r, "optionId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	kind, err := c.Var(ctx, r, "kind", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &Quality{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.problemService.AddQuality(ctx, userId, problemId, optionId, qualityKinds[kind], c.toQualityDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toQualityApi(res))
+}
+
+func (c *ctrlImpl) UpdateQuality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	optionId, err := c.VarUUID(ctx, r, "optionId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	kind, err := c.Var(ctx, r, "kind", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	qualityId, err := c.VarUUID(ctx, r, "qualityId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &Quality{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+	rq.Id = qualityId
+
+	res, err := c.problemService.UpdateQuality(ctx, userId, problemId, optionId, qualityKinds[kind], c.toQualityDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toQualityApi(res))
+}
+
+func (c *ctrlImpl) DeleteQuality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	optionId, err := c.VarUUID(ctx, r, "optionId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	kind, err := c.Var(ctx, r, "kind", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	qualityId, err := c.VarUUID(ctx, r, "qualityId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	if err = c.problemService.DeleteQuality(ctx, userId, problemId, optionId, qualityKinds[kind], qualityId); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, kitHttp.EmptyOkResponse)
+}
+
+func (c *ctrlImpl) SetMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	var rq []*Member
+	if err = c.DecodeRequest(ctx, r, &rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.groupService.SetMembers(ctx, userId, problemId, c.toMembersDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toMembersApi(res))
+}
+
+func (c *ctrlImpl) GetMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.groupService.GetMembers(ctx, userId, problemId)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toMembersApi(res))
+}
+
+func (c *ctrlImpl) SetAssessments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	var rq []*Assessment
+	if err = c.DecodeRequest(ctx, r, &rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.groupService.SetAssessments(ctx, userId, problemId, c.toAssessmentsDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toAssessmentsApi(res))
+}
+
+func (c *ctrlImpl) GetAssessments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.groupService.GetAssessments(ctx, userId, problemId)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toAssessmentsApi(res))
+}
+
+func (c *ctrlImpl) MakeGroupDecision(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &GroupDecisionRequest{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.groupService.MakeGroupDecision(ctx, userId, problemId, rq.Aggregation)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toDecisionResultApi(res))
+}
+
+func (c *ctrlImpl) GetProblemVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.problemService.GetProblemVersions(ctx, userId, problemId)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemVersionsApi(res))
+}
+
+func (c *ctrlImpl) GetProblemVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	// route allows digits only
+	v, err := c.Var(ctx, r, "version", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+	version, _ := strconv.Atoi(v)
+
+	res, err := c.problemService.GetProblemVersion(ctx, userId, problemId, version)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemApi(res))
+}
+
+func (c *ctrlImpl) DiffProblemVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	from, err := c.FormValInt(ctx, r, "from", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	to, err := c.FormValInt(ctx, r, "to", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.problemService.DiffProblemVersions(ctx, userId, problemId, *from, *to)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemDiffApi(res))
+}
+
+func (c *ctrlImpl) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &Template{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.templateService.CreateTemplate(ctx, userId, c.toTemplateDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toTemplateApi(res))
+}
+
+func (c *ctrlImpl) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	templateId, err := c.VarUUID(ctx, r, "templateId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &Template{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+	rq.Id = templateId
+
+	res, err := c.templateService.UpdateTemplate(ctx, userId, c.toTemplateDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toTemplateApi(res))
+}
+
+func (c *ctrlImpl) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	templateId, err := c.VarUUID(ctx, r, "templateId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.templateService.GetTemplate(ctx, userId, templateId)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toTemplateApi(res))
+}
+
+func (c *ctrlImpl) SearchTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	size, index, err := c.FormPaging(ctx, r, kit.IntPtr(maxPageSize))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	category, err := c.FormVal(ctx, r, "category", true)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	criteria := &domain.TemplateSearchCriteria{
+		UserId:   userId,
+		Category: category,
+	}
+	if size != nil {
+		criteria.Size = *size
+	}
+	if index != nil {
+		criteria.Index = *index
+	}
+
+	res, err := c.templateService.SearchTemplates(ctx, criteria)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toTemplateSearchResponseApi(res))
+}
+
+func (c *ctrlImpl) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	templateId, err := c.VarUUID(ctx, r, "templateId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	if err = c.templateService.DeleteTemplate(ctx, userId, templateId); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, kitHttp.EmptyOkResponse)
+}
+
+func (c *ctrlImpl) CreateProblemFromTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	templateId, err := c.VarUUID(ctx, r, "templateId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	rq := &TemplateProblemRequest{}
+	if err = c.DecodeRequest(ctx, r, rq); err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	res, err := c.templateService.CreateProblemFromTemplate(ctx, userId, templateId, c.toTemplateProblemDomain(rq))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemApi(res))
+}
+
+func (c *ctrlImpl) ExportProblem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	problemId, err := c.VarUUID(ctx, r, "problemId", false)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	format, err := c.FormVal(ctx, r, "format", true)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+	if format == "" {
+		format = domain.ExchangeFormatJson
+	}
+
+	content, err := c.exchangeService.ExportProblem(ctx, userId, problemId, format)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondContent(w, r, kitHttp.ResponseContentOpts{
+		Filename:    problemId + "." + format,
+		ContentType: c.codec.ContentType(format),
+		ContentSize: len(content),
+		Download:    true,
+	}, content)
+}
+
+func (c *ctrlImpl) ImportProblem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := c.UserIdVar(ctx, r, "userId")
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	// query params are taken from URL explicitly, since parsing a form would consume the multipart body
+	query := r.URL.Query()
+	format, name := query.Get("format"), query.Get("name")
+
+	content, filename, err := c.GetUploadFileMultipartContent(ctx, r)
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	// format and name are taken from the file unless specified explicitly
+	if format == "" {
+		if format = c.codec.Format(filename); format == "" {
+			c.RespondError(w, errors.ErrDecisionExchangeFormatInvalid(ctx, filepath.Ext(filename)))
+			return
+		}
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+
+	// read one byte over the limit to tell oversized content from content of exactly the limit size
+	data, err := io.ReadAll(io.LimitReader(content, maxImportSize+1))
+	if err != nil {
+		c.RespondError(w, errors.ErrDecisionImportParse(ctx, err))
+		return
+	}
+	if len(data) > maxImportSize {
+		c.RespondError(w, errors.ErrDecisionImportTooLarge(ctx, maxImportSize))
+		return
+	}
+
+	res, err := c.exchangeService.ImportProblem(ctx, userId, format, name, bytes.NewReader(data))
+	if err != nil {
+		c.RespondError(w, err)
+		return
+	}
+
+	c.RespondOK(w, c.toProblemApi(res))
+}
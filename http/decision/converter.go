@@ -1,11 +1,560 @@
 package decision
 
-import domain "github.com/mikhailbolshakov/decision/domain/decision"
+import (
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"time"
+)
 
 func (c *ctrlImpl) toDecisionResultApi(res *domain.Decision) *Decision {
-	return nil
+	if res == nil {
+		return nil
+	}
+	return &Decision{
+		Id:             res.Id,
+		ProblemId:      res.ProblemId,
+		ProblemVersion: res.ProblemVersion,
+		UserId:         res.UserId,
+		Result: Result{
+			Method:           res.Result.Method,
+			OptionsRating:    res.Result.OptionsRating,
+			Scoring:          res.Result.Scoring,
+			Smoothing:        res.Result.Smoothing,
+			ConsistencyRatio: res.Result.ConsistencyRatio,
+			Simulation:       c.toSimulationResultApi(res.Result.Simulation),
+			Group:            c.toGroupResultApi(res.Result.Group),
+			Explanation:      c.toExplanationApi(res.Result.Explanation),
+		},
+		CreatedAt: timePtr(res.CreatedAt),
+	}
+}
+
+func (c *ctrlImpl) toDecisionsApi(res []*domain.Decision) []*Decision {
+	r := make([]*Decision, 0, len(res))
+	for _, d := range res {
+		r = append(r, c.toDecisionResultApi(d))
+	}
+	return r
 }
 
 func (c *ctrlImpl) toProblemDomain(problem *Problem) *domain.Problem {
-	return nil
+	if problem == nil {
+		return nil
+	}
+	r := &domain.Problem{
+		Id:     problem.Id,
+		Name:   problem.Name,
+		Method: problem.Method,
+	}
+	if problem.ProsCons != nil {
+		r.ProsCons = &domain.ProsCons{Scoring: problem.ProsCons.Scoring, Smoothing: problem.ProsCons.Smoothing}
+	}
+	if problem.Ahp != nil {
+		r.Ahp = &domain.Ahp{
+			Criteria:            problem.Ahp.Criteria,
+			CriteriaComparisons: problem.Ahp.CriteriaComparisons,
+			OptionComparisons:   problem.Ahp.OptionComparisons,
+		}
+	}
+	if problem.Topsis != nil {
+		r.Topsis = &domain.Topsis{Scores: problem.Topsis.Scores}
+		for _, cr := range problem.Topsis.Criteria {
+			if cr == nil {
+				r.Topsis.Criteria = append(r.Topsis.Criteria, nil)
+				continue
+			}
+			r.Topsis.Criteria = append(r.Topsis.Criteria, &domain.TopsisCriterion{Name: cr.Name, Weight: cr.Weight, Direction: cr.Direction})
+		}
+	}
+	if problem.Simulation != nil {
+		r.Simulation = &domain.Simulation{
+			Iterations: problem.Simulation.Iterations,
+			Seed:       problem.Simulation.Seed,
+		}
+	}
+	for _, op := range problem.Options {
+		r.Options = append(r.Options, c.toOptionDomain(op))
+	}
+	return r
+}
+
+func (c *ctrlImpl) toOptionDomain(option *Option) *domain.Option {
+	if option == nil {
+		return nil
+	}
+	return &domain.Option{
+		Id:     option.Id,
+		Name:   option.Name,
+		Pros:   c.toQualitiesDomain(option.Pros),
+		Cons:   c.toQualitiesDomain(option.Cons),
+		Fields: option.Fields,
+	}
+}
+
+func (c *ctrlImpl) toQualitiesDomain(qualities []*Quality) []*domain.Quality {
+	var r []*domain.Quality
+	for _, q := range qualities {
+		r = append(r, c.toQualityDomain(q))
+	}
+	return r
+}
+
+func (c *ctrlImpl) toQualityDomain(quality *Quality) *domain.Quality {
+	if quality == nil {
+		return nil
+	}
+	return &domain.Quality{
+		Id:              quality.Id,
+		Name:            quality.Name,
+		Importance:      quality.Importance,
+		Probability:     quality.Probability,
+		ImportanceDist:  c.toDistributionDomain(quality.ImportanceDist),
+		ProbabilityDist: c.toDistributionDomain(quality.ProbabilityDist),
+	}
+}
+
+func (c *ctrlImpl) toProblemApi(problem *domain.Problem) *Problem {
+	if problem == nil {
+		return nil
+	}
+	r := &Problem{
+		Id:        problem.Id,
+		Name:      problem.Name,
+		Method:    problem.Method,
+		Version:   problem.Version,
+		CreatedAt: timePtr(problem.CreatedAt),
+		UpdatedAt: timePtr(problem.UpdatedAt),
+	}
+	if problem.ProsCons != nil {
+		r.ProsCons = &ProsCons{Scoring: problem.ProsCons.Scoring, Smoothing: problem.ProsCons.Smoothing}
+	}
+	if problem.Ahp != nil {
+		r.Ahp = &Ahp{
+			Criteria:            problem.Ahp.Criteria,
+			CriteriaComparisons: problem.Ahp.CriteriaComparisons,
+			OptionComparisons:   problem.Ahp.OptionComparisons,
+		}
+	}
+	if problem.Topsis != nil {
+		r.Topsis = &Topsis{Scores: problem.Topsis.Scores}
+		for _, cr := range problem.Topsis.Criteria {
+			r.Topsis.Criteria = append(r.Topsis.Criteria, &TopsisCriterion{Name: cr.Name, Weight: cr.Weight, Direction: cr.Direction})
+		}
+	}
+	if problem.Simulation != nil {
+		r.Simulation = &Simulation{
+			Iterations: problem.Simulation.Iterations,
+			Seed:       problem.Simulation.Seed,
+		}
+	}
+	for _, op := range problem.Options {
+		r.Options = append(r.Options, c.toOptionApi(op))
+	}
+	return r
+}
+
+func (c *ctrlImpl) toOptionApi(option *domain.Option) *Option {
+	if option == nil {
+		return nil
+	}
+	return &Option{
+		Id:     option.Id,
+		Name:   option.Name,
+		Pros:   c.toQualitiesApi(option.Pros),
+		Cons:   c.toQualitiesApi(option.Cons),
+		Fields: option.Fields,
+	}
+}
+
+func (c *ctrlImpl) toQualitiesApi(qualities []*domain.Quality) []*Quality {
+	var r []*Quality
+	for _, q := range qualities {
+		r = append(r, c.toQualityApi(q))
+	}
+	return r
+}
+
+func (c *ctrlImpl) toQualityApi(quality *domain.Quality) *Quality {
+	if quality == nil {
+		return nil
+	}
+	return &Quality{
+		Id:              quality.Id,
+		Name:            quality.Name,
+		Importance:      quality.Importance,
+		Probability:     quality.Probability,
+		ImportanceDist:  c.toDistributionApi(quality.ImportanceDist),
+		ProbabilityDist: c.toDistributionApi(quality.ProbabilityDist),
+	}
+}
+
+func (c *ctrlImpl) toDistributionDomain(d *Distribution) *domain.Distribution {
+	if d == nil {
+		return nil
+	}
+	return &domain.Distribution{
+		Type:   d.Type,
+		Min:    d.Min,
+		Max:    d.Max,
+		Mode:   d.Mode,
+		Mean:   d.Mean,
+		StdDev: d.StdDev,
+		Alpha:  d.Alpha,
+		Beta:   d.Beta,
+	}
+}
+
+func (c *ctrlImpl) toDistributionApi(d *domain.Distribution) *Distribution {
+	if d == nil {
+		return nil
+	}
+	return &Distribution{
+		Type:   d.Type,
+		Min:    d.Min,
+		Max:    d.Max,
+		Mode:   d.Mode,
+		Mean:   d.Mean,
+		StdDev: d.StdDev,
+		Alpha:  d.Alpha,
+		Beta:   d.Beta,
+	}
+}
+
+func (c *ctrlImpl) toSimulationResultApi(sr *domain.SimulationResult) *SimulationResult {
+	if sr == nil {
+		return nil
+	}
+	r := &SimulationResult{
+		Iterations: sr.Iterations,
+		Seed:       sr.Seed,
+		Options:    make(map[string]*OptionSimulation, len(sr.Options)),
+	}
+	for id, o := range sr.Options {
+		r.Options[id] = &OptionSimulation{
+			Mean:            o.Mean,
+			StdDev:          o.StdDev,
+			P5:              o.P5,
+			P25:             o.P25,
+			P50:             o.P50,
+			P75:             o.P75,
+			P95:             o.P95,
+			ProbabilityBest: o.ProbabilityBest,
+		}
+	}
+	return r
+}
+
+func (c *ctrlImpl) toProblemSearchResponseApi(rs *domain.ProblemSearchResponse) *ProblemSearchResponse {
+	r := &ProblemSearchResponse{
+		Index:    rs.Index,
+		Total:    rs.Total,
+		Problems: make([]*Problem, 0, len(rs.Problems)),
+	}
+	for _, p := range rs.Problems {
+		r.Problems = append(r.Problems, c.toProblemApi(p))
+	}
+	return r
+}
+
+func (c *ctrlImpl) toTemplateDomain(t *Template) *domain.Template {
+	r := &domain.Template{
+		Id:          t.Id,
+		Name:        t.Name,
+		Description: t.Description,
+		Category:    t.Category,
+	}
+	for _, cr := range t.Criteria {
+		r.Criteria = append(r.Criteria, &domain.TemplateCriterion{
+			Name:        cr.Name,
+			Kind:        cr.Kind,
+			Importance:  cr.Importance,
+			Probability: cr.Probability,
+		})
+	}
+	for _, f := range t.OptionFields {
+		r.OptionFields = append(r.OptionFields, &domain.TemplateField{Name: f.Name, Type: f.Type, Required: f.Required})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toTemplateApi(t *domain.Template) *Template {
+	r := &Template{
+		Id:          t.Id,
+		System:      t.System(),
+		Name:        t.Name,
+		Description: t.Description,
+		Category:    t.Category,
+		Criteria:    make([]*TemplateCriterion, 0, len(t.Criteria)),
+		CreatedAt:   timePtr(t.CreatedAt),
+		UpdatedAt:   timePtr(t.UpdatedAt),
+	}
+	for _, cr := range t.Criteria {
+		r.Criteria = append(r.Criteria, &TemplateCriterion{
+			Name:        cr.Name,
+			Kind:        cr.Kind,
+			Importance:  cr.Importance,
+			Probability: cr.Probability,
+		})
+	}
+	for _, f := range t.OptionFields {
+		r.OptionFields = append(r.OptionFields, &TemplateField{Name: f.Name, Type: f.Type, Required: f.Required})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toTemplateSearchResponseApi(rs *domain.TemplateSearchResponse) *TemplateSearchResponse {
+	r := &TemplateSearchResponse{
+		Index:     rs.Index,
+		Total:     rs.Total,
+		Templates: make([]*Template, 0, len(rs.Templates)),
+	}
+	for _, t := range rs.Templates {
+		r.Templates = append(r.Templates, c.toTemplateApi(t))
+	}
+	return r
+}
+
+func (c *ctrlImpl) toTemplateProblemDomain(rq *TemplateProblemRequest) *domain.Problem {
+	r := &domain.Problem{
+		Name:   rq.Name,
+		Method: rq.Method,
+	}
+	for _, op := range rq.Options {
+		r.Options = append(r.Options, &domain.Option{Name: op.Name, Fields: op.Fields})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toMethodsApi(methods []*domain.MethodDescription) []*Method {
+	r := make([]*Method, 0, len(methods))
+	for _, m := range methods {
+		r = append(r, &Method{
+			Code:        m.Code,
+			Name:        m.Name,
+			Description: m.Description,
+			Params:      c.toParamSchemasApi(m.Params),
+		})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toParamSchemasApi(params map[string]*domain.ParamSchema) map[string]*ParamSchema {
+	if params == nil {
+		return nil
+	}
+	r := make(map[string]*ParamSchema, len(params))
+	for k, p := range params {
+		r[k] = c.toParamSchemaApi(p)
+	}
+	return r
+}
+
+func (c *ctrlImpl) toParamSchemaApi(p *domain.ParamSchema) *ParamSchema {
+	if p == nil {
+		return nil
+	}
+	return &ParamSchema{
+		Type:        p.Type,
+		Description: p.Description,
+		Properties:  c.toParamSchemasApi(p.Properties),
+		Required:    p.Required,
+		Items:       c.toParamSchemaApi(p.Items),
+		Enum:        p.Enum,
+		Minimum:     p.Minimum,
+		Maximum:     p.Maximum,
+	}
+}
+
+func (c *ctrlImpl) toSensitivityResultApi(sr *domain.SensitivityResult) *SensitivityResult {
+	r := &SensitivityResult{
+		WinnerId:      sr.WinnerId,
+		OptionsRating: sr.OptionsRating,
+		Qualities:     make([]*QualitySensitivity, 0, len(sr.Qualities)),
+	}
+	for _, q := range sr.Qualities {
+		r.Qualities = append(r.Qualities, &QualitySensitivity{
+			OptionId:      q.OptionId,
+			QualityId:     q.QualityId,
+			Kind:          q.Kind,
+			Param:         q.Param,
+			Value:         q.Value,
+			Low:           q.Low,
+			High:          q.High,
+			RatingLow:     q.RatingLow,
+			RatingHigh:    q.RatingHigh,
+			Swing:         q.Swing,
+			BreakEvenDown: c.toBreakEvenApi(q.BreakEvenDown),
+			BreakEvenUp:   c.toBreakEvenApi(q.BreakEvenUp),
+		})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toBreakEvenApi(be *domain.BreakEven) *BreakEven {
+	if be == nil {
+		return nil
+	}
+	return &BreakEven{
+		Value:    be.Value,
+		WinnerId: be.WinnerId,
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func (c *ctrlImpl) toMembersDomain(rq []*Member) []*domain.Member {
+	r := make([]*domain.Member, 0, len(rq))
+	for _, m := range rq {
+		if m == nil {
+			continue
+		}
+		r = append(r, &domain.Member{UserId: m.UserId, Weight: m.Weight})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toMembersApi(members []*domain.Member) []*Member {
+	r := make([]*Member, 0, len(members))
+	for _, m := range members {
+		r = append(r, &Member{UserId: m.UserId, Weight: m.Weight})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toAssessmentsDomain(rq []*Assessment) []*domain.Assessment {
+	r := make([]*domain.Assessment, 0, len(rq))
+	for _, a := range rq {
+		if a == nil {
+			continue
+		}
+		r = append(r, &domain.Assessment{
+			QualityId:   a.QualityId,
+			Importance:  a.Importance,
+			Probability: a.Probability,
+		})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toAssessmentsApi(assessments []*domain.Assessment) []*Assessment {
+	r := make([]*Assessment, 0, len(assessments))
+	for _, a := range assessments {
+		r = append(r, &Assessment{
+			UserId:      a.UserId,
+			QualityId:   a.QualityId,
+			Importance:  a.Importance,
+			Probability: a.Probability,
+		})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toGroupResultApi(g *domain.GroupResult) *GroupResult {
+	if g == nil {
+		return nil
+	}
+	r := &GroupResult{
+		Aggregation:  g.Aggregation,
+		Participants: c.toMembersApi(g.Participants),
+	}
+	for _, s := range g.Spread {
+		r.Spread = append(r.Spread, &QualitySpread{
+			QualityId:         s.QualityId,
+			ImportanceMin:     s.ImportanceMin,
+			ImportanceMax:     s.ImportanceMax,
+			ImportanceStdDev:  s.ImportanceStdDev,
+			ProbabilityMin:    s.ProbabilityMin,
+			ProbabilityMax:    s.ProbabilityMax,
+			ProbabilityStdDev: s.ProbabilityStdDev,
+		})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toExplanationApi(e *domain.Explanation) *Explanation {
+	if e == nil {
+		return nil
+	}
+	r := &Explanation{
+		Ranking: make([]*OptionExplanation, 0, len(e.Ranking)),
+		Margin:  e.Margin,
+		Summary: e.Summary,
+	}
+	for _, oe := range e.Ranking {
+		o := &OptionExplanation{
+			OptionId:  oe.OptionId,
+			Name:      oe.Name,
+			Rank:      oe.Rank,
+			Rating:    oe.Rating,
+			ProsTotal: oe.ProsTotal,
+			ConsTotal: oe.ConsTotal,
+		}
+		for _, q := range oe.Contributions {
+			o.Contributions = append(o.Contributions, &QualityContribution{
+				QualityId: q.QualityId,
+				Name:      q.Name,
+				Kind:      q.Kind,
+				Weight:    q.Weight,
+				Share:     q.Share,
+			})
+		}
+		r.Ranking = append(r.Ranking, o)
+	}
+	return r
+}
+
+func (c *ctrlImpl) toProblemVersionsApi(versions []*domain.ProblemVersion) []*ProblemVersion {
+	r := make([]*ProblemVersion, 0, len(versions))
+	for _, v := range versions {
+		r = append(r, &ProblemVersion{
+			ProblemId: v.ProblemId,
+			Version:   v.Version,
+			CreatedAt: timePtr(v.CreatedAt),
+		})
+	}
+	return r
+}
+
+func (c *ctrlImpl) toProblemDiffApi(d *domain.ProblemDiff) *ProblemDiff {
+	r := &ProblemDiff{
+		ProblemId:  d.ProblemId,
+		From:       d.From,
+		To:         d.To,
+		NameFrom:   d.NameFrom,
+		NameTo:     d.NameTo,
+		MethodFrom: d.MethodFrom,
+		MethodTo:   d.MethodTo,
+		WinnerFrom: d.WinnerFrom,
+		WinnerTo:   d.WinnerTo,
+		Options:    make([]*OptionDiff, 0, len(d.Options)),
+	}
+	for _, op := range d.Options {
+		o := &OptionDiff{
+			OptionId:   op.OptionId,
+			Name:       op.Name,
+			Change:     op.Change,
+			NameFrom:   op.NameFrom,
+			RatingFrom: op.RatingFrom,
+			RatingTo:   op.RatingTo,
+		}
+		for _, q := range op.Qualities {
+			o.Qualities = append(o.Qualities, &QualityDiff{
+				QualityId:       q.QualityId,
+				Kind:            q.Kind,
+				Name:            q.Name,
+				Change:          q.Change,
+				NameFrom:        q.NameFrom,
+				ImportanceFrom:  q.ImportanceFrom,
+				ImportanceTo:    q.ImportanceTo,
+				ProbabilityFrom: q.ProbabilityFrom,
+				ProbabilityTo:   q.ProbabilityTo,
+			})
+		}
+		r.Options = append(r.Options, o)
+	}
+	return r
 }
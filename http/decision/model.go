@@ -1,32 +1,306 @@
 package decision
 
+import "time"
+
+// Distribution random value distribution
+type Distribution struct {
+	Type   string  `json:"type" validate:"required,oneof=uniform triangular normal beta"` // Type uniform, triangular, normal, beta
+	Min    float64 `json:"min,omitempty"`                                                 // Min min value (uniform, triangular, beta)
+	Max    float64 `json:"max,omitempty"`                                                 // Max max value (uniform, triangular, beta)
+	Mode   float64 `json:"mode,omitempty"`                                                // Mode most likely value (triangular)
+	Mean   float64 `json:"mean,omitempty"`                                                // Mean mean value (normal)
+	StdDev float64 `json:"stdDev,omitempty"`                                              // StdDev standard deviation (normal)
+	Alpha  float64 `json:"alpha,omitempty"`                                               // Alpha shape (beta)
+	Beta   float64 `json:"beta,omitempty"`                                                // Beta shape (beta)
+}
+
 type Quality struct {
-	Id          string
-	Name        string
-	Importance  float64
-	Probability float64
+	Id              string        `json:"id,omitempty"`                       // Id quality id
+	Name            string        `json:"name" validate:"required,max=256"`   // Name quality name
+	Importance      float64       `json:"importance" validate:"gte=0"`        // Importance how important the quality is
+	Probability     float64       `json:"probability" validate:"gte=0,lte=1"` // Probability probability of the quality [0, 1]
+	ImportanceDist  *Distribution `json:"importanceDist,omitempty"`           // ImportanceDist importance distribution for simulation
+	ProbabilityDist *Distribution `json:"probabilityDist,omitempty"`          // ProbabilityDist probability distribution for simulation
 }
 
 type Option struct {
-	Id   string
-	Name string
-	Pros []*Quality
-	Cons []*Quality
+	Id     string            `json:"id,omitempty"`                            // Id option id
+	Name   string            `json:"name" validate:"required,max=256"`        // Name option name
+	Pros   []*Quality        `json:"pros,omitempty" validate:"dive,required"` // Pros positive qualities
+	Cons   []*Quality        `json:"cons,omitempty" validate:"dive,required"` // Cons negative qualities
+	Fields map[string]string `json:"fields,omitempty"`                        // Fields option attributes (e.g. defined by a template)
+}
+
+// ProsCons scoring model for pros-cons method
+type ProsCons struct {
+	Scoring   string   `json:"scoring,omitempty" validate:"omitempty,oneof=ratio net"` // Scoring ratio: (pros + smoothing) / (cons + smoothing), net: (pros - cons) / (pros + cons + smoothing); ratio by default
+	Smoothing *float64 `json:"smoothing,omitempty" validate:"omitempty,gte=0"`         // Smoothing additive smoothing; if empty, 1 is applied to ratio when some option has zero cons, otherwise 0
+}
+
+// Ahp pairwise comparisons for AHP method
+// a[i][j] specifies how much i is preferable to j on Saaty's scale (1..9), a[j][i] must be 1/a[i][j]
+type Ahp struct {
+	Criteria            []string      `json:"criteria,omitempty"`  // Criteria names of criteria
+	CriteriaComparisons [][]float64   `json:"criteriaComparisons"` // CriteriaComparisons pairwise comparisons of criteria
+	OptionComparisons   [][][]float64 `json:"optionComparisons"`   // OptionComparisons pairwise comparisons of options (in order of options) for each criterion
+}
+
+type TopsisCriterion struct {
+	Name      string  `json:"name,omitempty"`                                              // Name criterion name
+	Weight    float64 `json:"weight" validate:"gte=0"`                                     // Weight criterion weight
+	Direction string  `json:"direction,omitempty" validate:"omitempty,oneof=benefit cost"` // Direction benefit (bigger is better) or cost (smaller is better), benefit by default
+}
+
+// Topsis decision matrix for TOPSIS method
+type Topsis struct {
+	Criteria []*TopsisCriterion `json:"criteria" validate:"dive,required"` // Criteria list of criteria
+	Scores   [][]float64        `json:"scores"`                            // Scores scores of options (in order of options) by each criterion
+}
+
+// Simulation Monte Carlo simulation params
+type Simulation struct {
+	Iterations int    `json:"iterations,omitempty" validate:"gte=0"` // Iterations number of simulations, 1000 by default
+	Seed       *int64 `json:"seed,omitempty"`                        // Seed random seed for reproducible results
 }
 
 type Problem struct {
-	Id      string
-	Name    string
-	Options []*Option
+	Id         string      `json:"id,omitempty"`                               // Id problem id
+	Name       string      `json:"name" validate:"max=256"`                    // Name problem name
+	Method     string      `json:"method,omitempty"`                           // Method decision method (pros-cons, ahp, topsis), pros-cons by default
+	ProsCons   *ProsCons   `json:"prosCons,omitempty"`                         // ProsCons scoring model for pros-cons method
+	Ahp        *Ahp        `json:"ahp,omitempty"`                              // Ahp comparisons for AHP method
+	Topsis     *Topsis     `json:"topsis,omitempty"`                           // Topsis decision matrix for TOPSIS method
+	Simulation *Simulation `json:"simulation,omitempty"`                       // Simulation if specified, Monte Carlo simulation is run along with the decision (pros-cons method only)
+	Options    []*Option   `json:"options,omitempty" validate:"dive,required"` // Options list of options
+	Version    int         `json:"version,omitempty"`                          // Version current version of the problem (read only)
+	CreatedAt  *time.Time  `json:"createdAt,omitempty"`                        // CreatedAt when problem was created
+	UpdatedAt  *time.Time  `json:"updatedAt,omitempty"`                        // UpdatedAt when problem was updated
+}
+
+type ProblemSearchResponse struct {
+	Index    int        `json:"index"`    // Index page index
+	Total    int        `json:"total"`    // Total total number of found problems
+	Problems []*Problem `json:"problems"` // Problems found problems (options aren't populated)
+}
+
+type TemplateCriterion struct {
+	Name        string  `json:"name" validate:"required,max=256"`       // Name criterion name
+	Kind        string  `json:"kind" validate:"required,oneof=pro con"` // Kind pro or con
+	Importance  float64 `json:"importance" validate:"gte=0"`            // Importance default importance
+	Probability float64 `json:"probability" validate:"gte=0,lte=1"`     // Probability default probability
+}
+
+type TemplateField struct {
+	Name     string `json:"name" validate:"required,max=256"`                                                           // Name field name
+	Type     string `json:"type,omitempty" validate:"omitempty,oneof=string number url email phone vin car-reg-number"` // Type field type, string by default
+	Required bool   `json:"required,omitempty"`                                                                         // Required if true, options must specify the field value
+}
+
+type Template struct {
+	Id           string               `json:"id,omitempty"`                                     // Id template id
+	System       bool                 `json:"system,omitempty"`                                 // System if true, the template is a system-wide one (read only)
+	Name         string               `json:"name" validate:"required,max=256"`                 // Name template name
+	Description  string               `json:"description,omitempty" validate:"max=1024"`        // Description template description
+	Category     string               `json:"category,omitempty" validate:"max=256"`            // Category template category (e.g. business, career, vehicles)
+	Criteria     []*TemplateCriterion `json:"criteria" validate:"required,min=1,dive,required"` // Criteria criteria options are assessed by
+	OptionFields []*TemplateField     `json:"optionFields,omitempty" validate:"dive,required"`  // OptionFields attributes options are described with
+	CreatedAt    *time.Time           `json:"createdAt,omitempty"`                              // CreatedAt when template was created
+	UpdatedAt    *time.Time           `json:"updatedAt,omitempty"`                              // UpdatedAt when template was updated
+}
+
+type TemplateSearchResponse struct {
+	Index     int         `json:"index"`     // Index page index
+	Total     int         `json:"total"`     // Total total number of found templates
+	Templates []*Template `json:"templates"` // Templates found templates
+}
+
+// TemplateProblemRequest a request to create a problem from a template
+type TemplateProblemRequest struct {
+	Name    string    `json:"name" validate:"required,max=256"`           // Name problem name
+	Method  string    `json:"method,omitempty"`                           // Method decision method, pros-cons by default
+	Options []*Option `json:"options,omitempty" validate:"dive,required"` // Options options with names and field values, pros and cons are taken from the template
+}
+
+type OptionSimulation struct {
+	Mean            float64 `json:"mean"`            // Mean mean rating
+	StdDev          float64 `json:"stdDev"`          // StdDev standard deviation of rating
+	P5              float64 `json:"p5"`              // P5 5th percentile of rating
+	P25             float64 `json:"p25"`             // P25 25th percentile of rating
+	P50             float64 `json:"p50"`             // P50 median rating
+	P75             float64 `json:"p75"`             // P75 75th percentile of rating
+	P95             float64 `json:"p95"`             // P95 95th percentile of rating
+	ProbabilityBest float64 `json:"probabilityBest"` // ProbabilityBest probability the option ranks first
+}
+
+type SimulationResult struct {
+	Iterations int                          `json:"iterations"` // Iterations number of simulations
+	Seed       int64                        `json:"seed"`       // Seed random seed used
+	Options    map[string]*OptionSimulation `json:"options"`    // Options statistics by option id
 }
 
 type Result struct {
-	OptionsRating map[string]float64
+	Method           string             `json:"method,omitempty"`      // Method decision method
+	OptionsRating    map[string]float64 `json:"optionsRating"`         // OptionsRating rating by option id
+	Scoring          string             `json:"scoring,omitempty"`     // Scoring applied scoring model (pros-cons only)
+	Smoothing        float64            `json:"smoothing,omitempty"`   // Smoothing applied smoothing (pros-cons only)
+	ConsistencyRatio float64            `json:"cr,omitempty"`          // ConsistencyRatio the worst consistency ratio of comparisons (AHP only)
+	Simulation       *SimulationResult  `json:"simulation,omitempty"`  // Simulation Monte Carlo simulation result
+	Group            *GroupResult       `json:"group,omitempty"`       // Group group decision details
+	Explanation      *Explanation       `json:"explanation,omitempty"` // Explanation ranking of options and why the winner is chosen
 }
 
 type Decision struct {
-	Id        string
-	ProblemId string
-	UserId    string
-	Result    Result
+	Id             string     `json:"id"`                       // Id decision id
+	ProblemId      string     `json:"problemId"`                // ProblemId problem id
+	ProblemVersion int        `json:"problemVersion,omitempty"` // ProblemVersion version of the problem the decision was made on
+	UserId         string     `json:"userId,omitempty"`         // UserId user id
+	Result         Result     `json:"result"`                   // Result decision result
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`      // CreatedAt when decision was made
+}
+
+// ParamSchema describes input parameters of a decision method (subset of JSON schema)
+type ParamSchema struct {
+	Type        string                  `json:"type"`                  // Type object, array, number, string
+	Description string                  `json:"description,omitempty"` // Description human-readable description
+	Properties  map[string]*ParamSchema `json:"properties,omitempty"`  // Properties properties of object
+	Required    []string                `json:"required,omitempty"`    // Required required properties of object
+	Items       *ParamSchema            `json:"items,omitempty"`       // Items schema of array items
+	Enum        []string                `json:"enum,omitempty"`        // Enum allowed values
+	Minimum     *float64                `json:"minimum,omitempty"`     // Minimum min allowed value of number
+	Maximum     *float64                `json:"maximum,omitempty"`     // Maximum max allowed value of number
+}
+
+type Method struct {
+	Code        string                  `json:"code"`                  // Code method code, used as problem method
+	Name        string                  `json:"name"`                  // Name method name
+	Description string                  `json:"description,omitempty"` // Description human-readable description
+	Params      map[string]*ParamSchema `json:"params"`                // Params parameter schemas by problem attribute
+}
+
+type SensitivityRequest struct {
+	Problem *Problem `json:"problem" validate:"required"`            // Problem problem to analyze
+	Range   float64  `json:"range,omitempty" validate:"gte=0,lte=1"` // Range relative perturbation of each param for tornado data, 0.2 (±20%) by default
+}
+
+type BreakEven struct {
+	Value    float64 `json:"value"`    // Value param value
+	WinnerId string  `json:"winnerId"` // WinnerId option ranked first beyond the value
+}
+
+type QualitySensitivity struct {
+	OptionId      string     `json:"optionId"`                // OptionId option id
+	QualityId     string     `json:"qualityId"`               // QualityId quality id
+	Kind          string     `json:"kind"`                    // Kind pro or con
+	Param         string     `json:"param"`                   // Param importance or probability
+	Value         float64    `json:"value"`                   // Value current param value
+	Low           float64    `json:"low"`                     // Low decreased param value
+	High          float64    `json:"high"`                    // High increased param value
+	RatingLow     float64    `json:"ratingLow"`               // RatingLow option rating when the param is decreased
+	RatingHigh    float64    `json:"ratingHigh"`              // RatingHigh option rating when the param is increased
+	Swing         float64    `json:"swing"`                   // Swing absolute rating change between low and high
+	BreakEvenDown *BreakEven `json:"breakEvenDown,omitempty"` // BreakEvenDown the nearest break-even below the current value
+	BreakEvenUp   *BreakEven `json:"breakEvenUp,omitempty"`   // BreakEvenUp the nearest break-even above the current value
+}
+
+type SensitivityResult struct {
+	WinnerId      string                `json:"winnerId"`      // WinnerId option ranked first
+	OptionsRating map[string]float64    `json:"optionsRating"` // OptionsRating base rating by option id
+	Qualities     []*QualitySensitivity `json:"qualities"`     // Qualities sensitivity of quality params ordered by swing
+}
+
+type Member struct {
+	UserId string  `json:"userId" validate:"required,uuid"` // UserId member user id
+	Weight float64 `json:"weight" validate:"gt=0"`          // Weight member's weight in aggregation
+}
+
+type Assessment struct {
+	UserId      string  `json:"userId,omitempty"`                   // UserId user who assessed the quality
+	QualityId   string  `json:"qualityId" validate:"required,uuid"` // QualityId assessed quality id
+	Importance  float64 `json:"importance" validate:"gte=0"`        // Importance user's importance assessment
+	Probability float64 `json:"probability" validate:"gte=0,lte=1"` // Probability user's probability assessment
+}
+
+type GroupDecisionRequest struct {
+	Aggregation string `json:"aggregation,omitempty" validate:"omitempty,oneof=mean geometric borda"` // Aggregation mean (default), geometric or borda
+}
+
+type QualitySpread struct {
+	QualityId         string  `json:"qualityId"`         // QualityId quality id
+	ImportanceMin     float64 `json:"importanceMin"`     // ImportanceMin min importance among participants
+	ImportanceMax     float64 `json:"importanceMax"`     // ImportanceMax max importance among participants
+	ImportanceStdDev  float64 `json:"importanceStdDev"`  // ImportanceStdDev standard deviation of importance
+	ProbabilityMin    float64 `json:"probabilityMin"`    // ProbabilityMin min probability among participants
+	ProbabilityMax    float64 `json:"probabilityMax"`    // ProbabilityMax max probability among participants
+	ProbabilityStdDev float64 `json:"probabilityStdDev"` // ProbabilityStdDev standard deviation of probability
+}
+
+type GroupResult struct {
+	Aggregation  string           `json:"aggregation"`      // Aggregation aggregation method
+	Participants []*Member        `json:"participants"`     // Participants users whose assessments were aggregated
+	Spread       []*QualitySpread `json:"spread,omitempty"` // Spread disagreement per quality
+}
+
+type QualityContribution struct {
+	QualityId string  `json:"qualityId"`      // QualityId quality id
+	Name      string  `json:"name,omitempty"` // Name quality name
+	Kind      string  `json:"kind"`           // Kind pro or con
+	Weight    float64 `json:"weight"`         // Weight importance * probability
+	Share     float64 `json:"share"`          // Share weight divided by the weighted total of pros or cons of the option
+}
+
+type OptionExplanation struct {
+	OptionId      string                 `json:"optionId"`                // OptionId option id
+	Name          string                 `json:"name,omitempty"`          // Name option name
+	Rank          int                    `json:"rank"`                    // Rank position in the ranking starting from 1, tied options share the rank
+	Rating        float64                `json:"rating"`                  // Rating option rating
+	ProsTotal     float64                `json:"prosTotal,omitempty"`     // ProsTotal weighted sum of pros (pros-cons only)
+	ConsTotal     float64                `json:"consTotal,omitempty"`     // ConsTotal weighted sum of cons (pros-cons only)
+	Contributions []*QualityContribution `json:"contributions,omitempty"` // Contributions pros and cons ordered by weight (pros-cons only)
+}
+
+type Explanation struct {
+	Ranking []*OptionExplanation `json:"ranking"`           // Ranking options ordered by rating, the first one is the winner
+	Margin  float64              `json:"margin"`            // Margin rating difference between the winner and the runner-up
+	Summary string               `json:"summary,omitempty"` // Summary short natural-language explanation
+}
+
+type ProblemVersion struct {
+	ProblemId string     `json:"problemId"`           // ProblemId problem id
+	Version   int        `json:"version"`             // Version version number
+	CreatedAt *time.Time `json:"createdAt,omitempty"` // CreatedAt when version was created
+}
+
+type QualityDiff struct {
+	QualityId       string  `json:"qualityId"`          // QualityId quality id
+	Kind            string  `json:"kind"`               // Kind pro or con
+	Name            string  `json:"name"`               // Name quality name
+	Change          string  `json:"change"`             // Change added, removed or changed
+	NameFrom        string  `json:"nameFrom,omitempty"` // NameFrom previous name if changed
+	ImportanceFrom  float64 `json:"importanceFrom"`     // ImportanceFrom importance in the from version
+	ImportanceTo    float64 `json:"importanceTo"`       // ImportanceTo importance in the to version
+	ProbabilityFrom float64 `json:"probabilityFrom"`    // ProbabilityFrom probability in the from version
+	ProbabilityTo   float64 `json:"probabilityTo"`      // ProbabilityTo probability in the to version
+}
+
+type OptionDiff struct {
+	OptionId   string         `json:"optionId"`             // OptionId option id
+	Name       string         `json:"name"`                 // Name option name
+	Change     string         `json:"change,omitempty"`     // Change added, removed, changed or empty if unchanged
+	NameFrom   string         `json:"nameFrom,omitempty"`   // NameFrom previous name if changed
+	RatingFrom *float64       `json:"ratingFrom,omitempty"` // RatingFrom rating in the from version
+	RatingTo   *float64       `json:"ratingTo,omitempty"`   // RatingTo rating in the to version
+	Qualities  []*QualityDiff `json:"qualities,omitempty"`  // Qualities changed qualities
+}
+
+type ProblemDiff struct {
+	ProblemId  string        `json:"problemId"`            // ProblemId problem id
+	From       int           `json:"from"`                 // From compared version
+	To         int           `json:"to"`                   // To compared version
+	NameFrom   string        `json:"nameFrom"`             // NameFrom problem name in the from version
+	NameTo     string        `json:"nameTo"`               // NameTo problem name in the to version
+	MethodFrom string        `json:"methodFrom,omitempty"` // MethodFrom decision method in the from version
+	MethodTo   string        `json:"methodTo,omitempty"`   // MethodTo decision method in the to version
+	WinnerFrom string        `json:"winnerFrom,omitempty"` // WinnerFrom top-ranked option in the from version
+	WinnerTo   string        `json:"winnerTo,omitempty"`   // WinnerTo top-ranked option in the to version
+	Options    []*OptionDiff `json:"options"`              // Options options with ratings and changes
 }
@@ -2,14 +2,97 @@ package decision
 
 import (
 	"github.com/mikhailbolshakov/decision/http"
+	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+)
+
+// names of rate limits configured in http.rate-limits
+const (
+	RateLimitGuestDecisions = "guest-decisions"
+	RateLimitDecisions      = "decisions"
 )
 
 func GetRoutes(c Controller) []*http.Route {
 	return []*http.Route{
 		// non authorize zone
-		http.R("/guests/decisions", c.MakeDecisionGuest).POST(),
+		http.R("/guests/decisions", c.MakeDecisionGuest).POST().NoAuth().RateLimit(RateLimitGuestDecisions).
+			Summary("Makes a decision for a guest").Request(Problem{}).Response(Decision{}),
+		http.R("/methods", c.GetMethods).GET().NoAuth().
+			Summary("Gets available decision methods").Response([]*Method{}),
 
 		// authorized zone
-		http.R("/users/{userId}/decisions", c.MakeDecision).POST(),
+		http.R("/users/{userId}/decisions", c.MakeDecision).POST().RateLimit(RateLimitDecisions).
+			Summary("Makes a decision").Request(Problem{}).Response(Decision{}),
+		http.R("/users/{userId}/decisions/sensitivity", c.AnalyzeSensitivity).POST().RateLimit(RateLimitDecisions).
+			Summary("Analyzes sensitivity of a pros-cons decision").Request(SensitivityRequest{}).Response(SensitivityResult{}),
+
+		// problems
+		http.R("/users/{userId}/problems", c.CreateProblem).POST().
+			Summary("Creates a problem").Request(Problem{}).Response(Problem{}),
+		http.R("/users/{userId}/problems", c.SearchProblems).GET().
+			Summary("Searches user's problems").Query("size", "index", "sortBy").Response(ProblemSearchResponse{}),
+		http.R("/users/{userId}/problems/import", c.ImportProblem).POST().
+			Summary("Imports a problem from an uploaded JSON, YAML or CSV file (multipart, part \"file\")").Query("format", "name").Response(Problem{}),
+		http.R("/users/{userId}/problems/{problemId}/export", c.ExportProblem).GET().
+			Summary("Exports a problem as JSON, YAML or CSV file").Query("format"),
+		http.R("/users/{userId}/problems/{problemId}", c.GetProblem).GET().
+			Summary("Gets a problem").Response(Problem{}),
+		http.R("/users/{userId}/problems/{problemId}", c.UpdateProblem).PUT().
+			Summary("Updates a problem").Request(Problem{}).Response(Problem{}),
+		http.R("/users/{userId}/problems/{problemId}", c.DeleteProblem).DELETE().
+			Summary("Deletes a problem").Response(kitHttp.EmptyOkResponse),
+		http.R("/users/{userId}/problems/{problemId}/decisions", c.MakeDecisionByProblem).POST().RateLimit(RateLimitDecisions).
+			Summary("Makes a decision on a stored problem").Response(Decision{}),
+		http.R("/users/{userId}/problems/{problemId}/decisions", c.GetDecisionsByProblem).GET().
+			Summary("Gets decisions made on a problem, available for the owner and group members").Response([]*Decision{}),
+
+		// versions
+		http.R("/users/{userId}/problems/{problemId}/versions", c.GetProblemVersions).GET().
+			Summary("Gets problem versions").Response([]*ProblemVersion{}),
+		http.R("/users/{userId}/problems/{problemId}/versions/diff", c.DiffProblemVersions).GET().
+			Summary("Compares two problem versions").Query("from", "to").Response(ProblemDiff{}),
+		http.R("/users/{userId}/problems/{problemId}/versions/{version:[0-9]+}", c.GetProblemVersion).GET().
+			Summary("Gets a problem version").Response(Problem{}),
+
+		// options
+		http.R("/users/{userId}/problems/{problemId}/options", c.AddOption).POST().
+			Summary("Adds an option to a problem").Request(Option{}).Response(Option{}),
+		http.R("/users/{userId}/problems/{problemId}/options/{optionId}", c.UpdateOption).PUT().
+			Summary("Updates an option").Request(Option{}).Response(Option{}),
+		http.R("/users/{userId}/problems/{problemId}/options/{optionId}", c.DeleteOption).DELETE().
+			Summary("Deletes an option").Response(kitHttp.EmptyOkResponse),
+
+		// pros & cons
+		http.R("/users/{userId}/problems/{problemId}/options/{optionId}/{kind:pros|cons}", c.AddQuality).POST().
+			Summary("Adds a pro or a con to an option").Request(Quality{}).Response(Quality{}),
+		http.R("/users/{userId}/problems/{problemId}/options/{optionId}/{kind:pros|cons}/{qualityId}", c.UpdateQuality).PUT().
+			Summary("Updates a pro or a con").Request(Quality{}).Response(Quality{}),
+		http.R("/users/{userId}/problems/{problemId}/options/{optionId}/{kind:pros|cons}/{qualityId}", c.DeleteQuality).DELETE().
+			Summary("Deletes a pro or a con").Response(kitHttp.EmptyOkResponse),
+
+		// templates
+		http.R("/users/{userId}/templates", c.CreateTemplate).POST().
+			Summary("Creates a user's template").Request(Template{}).Response(Template{}),
+		http.R("/users/{userId}/templates", c.SearchTemplates).GET().
+			Summary("Searches system and user's templates").Query("size", "index", "category").Response(TemplateSearchResponse{}),
+		http.R("/users/{userId}/templates/{templateId}", c.GetTemplate).GET().
+			Summary("Gets a template").Response(Template{}),
+		http.R("/users/{userId}/templates/{templateId}", c.UpdateTemplate).PUT().
+			Summary("Updates a user's template").Request(Template{}).Response(Template{}),
+		http.R("/users/{userId}/templates/{templateId}", c.DeleteTemplate).DELETE().
+			Summary("Deletes a user's template").Response(kitHttp.EmptyOkResponse),
+		http.R("/users/{userId}/templates/{templateId}/problems", c.CreateProblemFromTemplate).POST().
+			Summary("Creates a problem from a template").Request(TemplateProblemRequest{}).Response(Problem{}),
+
+		// group decisions
+		http.R("/users/{userId}/problems/{problemId}/members", c.SetMembers).PUT().
+			Summary("Sets problem members").Request([]*Member{}).Response([]*Member{}),
+		http.R("/users/{userId}/problems/{problemId}/members", c.GetMembers).GET().
+			Summary("Gets problem members").Response([]*Member{}),
+		http.R("/users/{userId}/problems/{problemId}/assessments", c.SetAssessments).PUT().
+			Summary("Sets user's assessments of problem qualities").Request([]*Assessment{}).Response([]*Assessment{}),
+		http.R("/users/{userId}/problems/{problemId}/assessments", c.GetAssessments).GET().
+			Summary("Gets assessments of all members").Response([]*Assessment{}),
+		http.R("/users/{userId}/problems/{problemId}/group-decisions", c.MakeGroupDecision).POST().RateLimit(RateLimitDecisions).
+			Summary("Makes a group decision aggregating members' assessments").Request(GroupDecisionRequest{}).Response(Decision{}),
 	}
 }
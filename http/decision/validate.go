@@ -0,0 +1,54 @@
+package decision
+
+import (
+	"context"
+	"fmt"
+	"github.com/mikhailbolshakov/decision/kit"
+	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"go.uber.org/multierr"
+)
+
+// Validate checks option ids are unique
+func (p *Problem) Validate() error {
+	var errs error
+	ids := map[string]struct{}{}
+	for i, op := range p.Options {
+		if op == nil || op.Id == "" {
+			continue
+		}
+		if _, ok := ids[op.Id]; ok {
+			errs = multierr.Append(errs, kitHttp.NewLocalizedFieldError(fmt.Sprintf("options[%d].id", i), "duplicate-option-id", "duplicate option id"))
+		}
+		ids[op.Id] = struct{}{}
+	}
+	return errs
+}
+
+// Validate checks quality ids are unique among pros and cons
+func (o *Option) Validate() error {
+	var errs error
+	ids := map[string]struct{}{}
+	for _, kq := range []struct {
+		field     string
+		qualities []*Quality
+	}{{"pros", o.Pros}, {"cons", o.Cons}} {
+		for i, q := range kq.qualities {
+			if q == nil || q.Id == "" {
+				continue
+			}
+			if _, ok := ids[q.Id]; ok {
+				errs = multierr.Append(errs, kitHttp.NewLocalizedFieldError(fmt.Sprintf("%s[%d].id", kq.field, i), "duplicate-quality-id", "duplicate quality id"))
+			}
+			ids[q.Id] = struct{}{}
+		}
+	}
+	return errs
+}
+
+// validateDecisionProblem checks a problem passed to make a decision has options
+func (c *ctrlImpl) validateDecisionProblem(ctx context.Context, field string, problem *Problem) error {
+	if len(problem.Options) == 0 {
+		return kitHttp.ErrHttpRequestInvalid(ctx, kit.KV{field: kitHttp.ValidationMessage(ctx, "options-required", "at least one option is required", nil)})
+	}
+	return nil
+}
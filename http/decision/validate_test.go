@@ -0,0 +1,130 @@
+package decision
+
+import (
+	"bytes"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"github.com/mikhailbolshakov/decision/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+)
+
+type validateTestSuite struct {
+	kit.Suite
+	decisionService *mocks.DecisionService
+	exchangeService *mocks.ExchangeService
+	ctrl            Controller
+}
+
+func (s *validateTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *validateTestSuite) SetupTest() {
+	s.decisionService = &mocks.DecisionService{}
+	s.exchangeService = &mocks.ExchangeService{}
+	s.ctrl = NewController(s.decisionService, &mocks.ProblemService{}, &mocks.GroupService{}, &mocks.TemplateService{}, s.exchangeService, &mocks.ProblemCodec{})
+}
+
+func TestValidateSuite(t *testing.T) {
+	suite.Run(t, new(validateTestSuite))
+}
+
+func (s *validateTestSuite) details(p *Problem) kit.KV {
+	details := kit.KV{}
+	if appErr, ok := kit.IsAppErr(kitHttp.ValidateRequest(s.Ctx, p)); ok {
+		details = appErr.Fields()
+		delete(details, "ctx")
+	}
+	return details
+}
+
+func (s *validateTestSuite) Test_Problem() {
+	id := kit.NewId()
+	details := s.details(&Problem{
+		Name: "problem",
+		Options: []*Option{
+			{Id: id, Name: "first", Pros: []*Quality{{Id: id, Name: "pro", Importance: -5, Probability: 0.5}}, Cons: []*Quality{{Id: id, Name: "con", Probability: 2}}},
+			{Id: id},
+		},
+	})
+	s.Len(details, 5)
+	s.NotEmpty(details["options[0].pros[0].importance"])
+	s.NotEmpty(details["options[0].cons[0].probability"])
+	s.NotEmpty(details["options[1].name"])
+	s.Equal("duplicate option id", details["options[1].id"])
+	s.Equal("duplicate quality id", details["options[0].cons[0].id"])
+}
+
+func (s *validateTestSuite) Test_Problem_Ok() {
+	s.Empty(s.details(&Problem{
+		Options: []*Option{{Name: "first", Pros: []*Quality{{Name: "pro", Importance: 1, Probability: 1}}}, {Name: "second"}},
+	}))
+}
+
+func (s *validateTestSuite) Test_MakeDecisionGuest_Invalid() {
+	kitHttp.NewTestRequest(s.T(), s.Ctx).POST().Url("/guests/decisions").
+		RqBody(&Problem{Options: []*Option{{Name: "first", Pros: []*Quality{{Name: "pro", Importance: -5}}}}}).
+		AssertCode(http.StatusBadRequest).
+		AssertAppError(kitHttp.ErrCodeHttpRequestInvalid).
+		Make(s.ctrl.MakeDecisionGuest)
+	s.decisionService.AssertNotCalled(s.T(), "MakeDecision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *validateTestSuite) Test_MakeDecisionGuest_NoOptions() {
+	kitHttp.NewTestRequest(s.T(), s.Ctx).POST().Url("/guests/decisions").
+		RqBody(&Problem{Name: "problem"}).
+		AssertCode(http.StatusBadRequest).
+		AssertAppError(kitHttp.ErrCodeHttpRequestInvalid).
+		Make(s.ctrl.MakeDecisionGuest)
+}
+
+func (s *validateTestSuite) Test_MakeDecisionGuest_Ok() {
+	s.decisionService.On("MakeDecision", mock.Anything, "", mock.Anything).
+		Return(&domain.Decision{Result: domain.DecisionResult{OptionsRating: map[string]float64{}}}, nil)
+	kitHttp.NewTestRequest(s.T(), s.Ctx).POST().Url("/guests/decisions").
+		RqBody(&Problem{Options: []*Option{{Name: "first"}}}).
+		AssertOk().
+		Make(s.ctrl.MakeDecisionGuest)
+	s.decisionService.AssertExpectations(s.T())
+}
+
+func (s *validateTestSuite) importRequest(content []byte) *kitHttp.TestRequest {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	fw, err := mw.CreateFormFile("file", "problem.json")
+	s.Require().NoError(err)
+	_, err = fw.Write(content)
+	s.Require().NoError(err)
+	s.Require().NoError(mw.Close())
+	return kitHttp.NewTestRequest(s.T(), s.Ctx).POST().Url("/problems/import?format=json").
+		Var("userId", kit.NewId()).
+		Header("Content-Type", mw.FormDataContentType()).
+		RqRawBody(body)
+}
+
+func (s *validateTestSuite) Test_ImportProblem_TooLarge() {
+	s.importRequest(make([]byte, maxImportSize+1)).
+		AssertCode(http.StatusRequestEntityTooLarge).
+		AssertAppError(errors.ErrCodeDecisionImportTooLarge).
+		Make(s.ctrl.ImportProblem)
+	s.exchangeService.AssertNotCalled(s.T(), "ImportProblem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *validateTestSuite) Test_ImportProblem_LimitSize() {
+	s.exchangeService.On("ImportProblem", mock.Anything, mock.Anything, "json", mock.Anything, mock.MatchedBy(func(r io.Reader) bool {
+		data, err := io.ReadAll(r)
+		return err == nil && len(data) == maxImportSize
+	})).Return(&domain.Problem{}, nil)
+	s.importRequest(make([]byte, maxImportSize)).
+		AssertOk().
+		Make(s.ctrl.ImportProblem)
+	s.exchangeService.AssertExpectations(s.T())
+}
@@ -2,24 +2,39 @@ package http
 
 import (
 	"fmt"
+	"github.com/gorilla/mux"
 	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/auth"
 	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"github.com/mikhailbolshakov/decision/kit/i18n"
+	"github.com/mikhailbolshakov/decision/kit/tracing"
 	"net/http"
 	"time"
 )
 
 const (
-	HeaderXRealIp       = "x-real-ip"
-	HeaderXForwarderFor = "x-forwarder-for"
-	HeaderRequestId     = "x-request-id"
+	HeaderXRealIp        = "x-real-ip"
+	HeaderXForwarderFor  = "x-forwarder-for"
+	HeaderRequestId      = "x-request-id"
+	HeaderAcceptLanguage = "accept-language"
 )
 
 type Middleware struct {
 	kitHttp.BaseController
+	verifier    auth.Verifier
+	adminRole   string
+	permissions map[string][]string
 }
 
-func NewMiddleware() *Middleware {
-	return &Middleware{}
+// NewMiddleware creates middlewares
+// adminRole allows access to resources of any user and is granted all the permissions, if empty no one has such access
+// permissions grants permissions to roles
+func NewMiddleware(verifier auth.Verifier, adminRole string, permissions map[string][]string) *Middleware {
+	return &Middleware{
+		verifier:    verifier,
+		adminRole:   adminRole,
+		permissions: permissions,
+	}
 }
 
 func (m *Middleware) AuthAccessTokenMiddleware(next http.HandlerFunc, tokenTypes ...string) http.HandlerFunc {
@@ -41,18 +56,96 @@ func (m *Middleware) AuthAccessTokenMiddleware(next http.HandlerFunc, tokenTypes
 			return
 		}
 
-		// TODO: authorization
-		fmt.Println(token)
+		// verify token
+		claims, err := m.verifier.Verify(ctx, token)
+		if err != nil {
+			m.RespondError(w, err)
+			return
+		}
 
 		// populate context
-		r = r.WithContext(ctxRq.ToContext(r.Context()))
+		ctxRq = ctxRq.WithUser(claims.Subject, claims.Username).WithSessionId(claims.SessionId).WithRoles(claims.Roles...)
+		ctx = ctxRq.ToContext(ctx)
+
+		// user's resources are accessible by the user or admin only
+		if userId, ok := mux.Vars(r)["userId"]; ok && userId != kitHttp.Me && userId != claims.Subject && !m.isAdmin(claims.Roles) {
+			m.RespondError(w, kitHttp.ErrAuthForbidden(ctx))
+			return
+		}
+
+		r = r.WithContext(ctx)
+
+		next.ServeHTTP(w, r)
+	}
+
+	return f
+}
 
+// RolesMiddleware allows a request only if the request context has all the roles
+func (m *Middleware) RolesMiddleware(next http.HandlerFunc, roles ...string) http.HandlerFunc {
+	hasRoles := m.HasRoles(roles...)
+	f := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if ok, err := hasRoles(ctx, r); err != nil {
+			m.RespondError(w, err)
+			return
+		} else if !ok {
+			m.RespondError(w, kitHttp.ErrAuthForbidden(ctx))
+			return
+		}
 		next.ServeHTTP(w, r)
 	}
+	return f
+}
 
+// PermissionsMiddleware allows a request only if roles of the request context grant all the permissions
+func (m *Middleware) PermissionsMiddleware(next http.HandlerFunc, permissions ...string) http.HandlerFunc {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var roles []string
+		if rCtx, ok := kit.Request(ctx); ok && rCtx != nil {
+			roles = rCtx.Roles
+		}
+		if !m.granted(roles, permissions) {
+			m.RespondError(w, kitHttp.ErrAuthForbidden(ctx))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
 	return f
 }
 
+// granted checks if the roles grant all the permissions
+func (m *Middleware) granted(roles, permissions []string) bool {
+	if m.isAdmin(roles) {
+		return true
+	}
+	granted := map[string]struct{}{}
+	for _, role := range roles {
+		for _, permission := range m.permissions[role] {
+			granted[permission] = struct{}{}
+		}
+	}
+	for _, permission := range permissions {
+		if _, ok := granted[permission]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Middleware) isAdmin(roles []string) bool {
+	if m.adminRole == "" {
+		return false
+	}
+	for _, role := range roles {
+		if role == m.adminRole {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Middleware) SetContextMiddleware(next http.Handler) http.Handler {
 
 	f := func(w http.ResponseWriter, r *http.Request) {
@@ -78,11 +171,37 @@ func (m *Middleware) SetContextMiddleware(next http.Handler) http.Handler {
 			ctxRq = ctxRq.WithClientIp(clientIP)
 		}
 
+		// negotiate client language among supported ones
+		ctxRq = ctxRq.WithLang(i18n.Default.Match(r.Header.Get(HeaderAcceptLanguage)))
+
+		// continue trace of the caller if specified
+		if traceId, spanId, ok := tracing.ParseTraceparent(r.Header.Get(tracing.HeaderTraceparent)); ok {
+			ctxRq = ctxRq.WithTrace(traceId, spanId)
+		}
+
 		ctx := ctxRq.ToContext(r.Context())
 
+		// span covers the handler
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		ctx, span := tracing.StartSpan(ctx, r.Method+" "+route)
+		defer span.End()
+		span.SetAttr("http.method", r.Method).SetAttr("http.route", route)
+		w.Header().Set(tracing.HeaderTraceparent, tracing.Traceparent(ctx))
+
 		r = r.WithContext(ctx)
 
-		next.ServeHTTP(w, r)
+		rw := &statusResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		span.SetAttr("http.status_code", rw.statusCode)
+		if rw.statusCode >= http.StatusInternalServerError {
+			span.SetError(fmt.Errorf("%s", http.StatusText(rw.statusCode)))
+		}
 	}
 
 	return http.HandlerFunc(f)
@@ -96,3 +215,17 @@ func (m *Middleware) WithTimeoutMiddleware(next http.HandlerFunc, timeoutSec int
 
 	return f
 }
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (rw *statusResponseWriter) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.statusCode = code
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
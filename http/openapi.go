@@ -0,0 +1,320 @@
+package http
+
+import (
+	"fmt"
+	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	OpenApiVersion = "3.0.3"
+	OpenApiUrl     = "/openapi.json"
+
+	securitySchemeBearer = "bearerAuth"
+	errorSchema          = "Error"
+	mediaTypeJson        = "application/json"
+)
+
+var (
+	// pathVarRegexp matches mux path variables {name} and {name:pattern}
+	pathVarRegexp = regexp.MustCompile(`\{([^}:]+)(?::([^}]+))?\}`)
+	timeType      = reflect.TypeOf(time.Time{})
+	// componentNameReplacer makes package path valid for a component name
+	componentNameReplacer = strings.NewReplacer("/", "_", ".", "_")
+)
+
+// OpenApi OpenAPI 3 document
+type OpenApi struct {
+	OpenApi    string                                  `json:"openapi"`
+	Info       *OpenApiInfo                            `json:"info"`
+	Paths      map[string]map[string]*OpenApiOperation `json:"paths"`
+	Components *OpenApiComponents                      `json:"components"`
+}
+
+// OpenApiInfo API metadata
+type OpenApiInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenApiOperation single API operation on a path
+type OpenApiOperation struct {
+	OperationId string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Parameters  []*OpenApiParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenApiRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenApiResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+// OpenApiParameter path or query parameter
+type OpenApiParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *OpenApiSchema `json:"schema"`
+}
+
+// OpenApiRequestBody operation request body
+type OpenApiRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenApiMediaType `json:"content"`
+}
+
+// OpenApiResponse operation response
+type OpenApiResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenApiMediaType `json:"content,omitempty"`
+}
+
+// OpenApiMediaType content of a particular media type
+type OpenApiMediaType struct {
+	Schema *OpenApiSchema `json:"schema"`
+}
+
+// OpenApiSchema JSON schema of a type
+type OpenApiSchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	Items                *OpenApiSchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenApiSchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *OpenApiSchema            `json:"additionalProperties,omitempty"`
+}
+
+// OpenApiSecurityScheme authentication scheme
+type OpenApiSecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// OpenApiComponents reusable schemas and security schemes
+type OpenApiComponents struct {
+	Schemas         map[string]*OpenApiSchema         `json:"schemas"`
+	SecuritySchemes map[string]*OpenApiSecurityScheme `json:"securitySchemes"`
+}
+
+// OpenApi generates OpenAPI document from the routes metadata
+// routes without handle function (handlers on URL prefix) aren't described
+func (r *RouteBuilder) OpenApi(info *OpenApiInfo) *OpenApi {
+	doc := &OpenApi{
+		OpenApi: OpenApiVersion,
+		Info:    info,
+		Paths:   map[string]map[string]*OpenApiOperation{},
+		Components: &OpenApiComponents{
+			Schemas: map[string]*OpenApiSchema{},
+			SecuritySchemes: map[string]*OpenApiSecurityScheme{
+				securitySchemeBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	g := &schemaGenerator{schemas: doc.Components.Schemas, names: map[reflect.Type]string{}}
+	g.schema(reflect.TypeOf(kitHttp.Error{}))
+
+	for _, route := range r.routes {
+		if route.handleFn == nil {
+			continue
+		}
+		path, params := openApiPath(route.urlPrefix + route.url)
+		for _, q := range route.query {
+			params = append(params, &OpenApiParameter{Name: q, In: "query", Schema: &OpenApiSchema{Type: "string"}})
+		}
+		op := &OpenApiOperation{
+			OperationId: operationId(route.handleFn),
+			Summary:     route.summary,
+			Parameters:  params,
+			Responses: map[string]*OpenApiResponse{
+				"200":     {Description: "OK"},
+				"default": {Description: "error", Content: jsonContent(&OpenApiSchema{Ref: schemaRef(errorSchema)})},
+			},
+		}
+		if route.request != nil {
+			op.RequestBody = &OpenApiRequestBody{Required: true, Content: jsonContent(g.schema(reflect.TypeOf(route.request)))}
+		}
+		if route.response != nil {
+			op.Responses["200"].Content = jsonContent(g.schema(reflect.TypeOf(route.response)))
+		}
+		if route.rateLimit != "" {
+			op.Responses["429"] = &OpenApiResponse{
+				Description: "too many requests, retry in seconds specified by Retry-After header",
+				Content:     jsonContent(&OpenApiSchema{Ref: schemaRef(errorSchema)}),
+			}
+		}
+		if len(route.authTokens) > 0 {
+			op.Security = []map[string][]string{{securitySchemeBearer: {}}}
+		}
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*OpenApiOperation{}
+		}
+		for _, verb := range route.verbs {
+			doc.Paths[path][strings.ToLower(verb)] = op
+		}
+	}
+	return doc
+}
+
+// OpenApiRoute returns a route serving OpenAPI document of all the routes set to the builder
+// the document is generated on the first request when all the routes are already set
+func (r *RouteBuilder) OpenApiRoute(info *OpenApiInfo) *Route {
+	var once sync.Once
+	var doc *OpenApi
+	ctrl := kitHttp.BaseController{}
+	return R(OpenApiUrl, func(w http.ResponseWriter, rq *http.Request) {
+		once.Do(func() { doc = r.OpenApi(info) })
+		ctrl.RespondOK(w, doc)
+	}).GET().NoAuth().Summary("OpenAPI document")
+}
+
+// openApiPath converts mux path to OpenAPI path and extracts path parameters
+func openApiPath(url string) (string, []*OpenApiParameter) {
+	var params []*OpenApiParameter
+	path := pathVarRegexp.ReplaceAllStringFunc(url, func(v string) string {
+		m := pathVarRegexp.FindStringSubmatch(v)
+		p := &OpenApiParameter{Name: m[1], In: "path", Required: true, Schema: &OpenApiSchema{Type: "string"}}
+		if m[2] != "" {
+			p.Schema.Pattern = "^(" + m[2] + ")$"
+		}
+		params = append(params, p)
+		return "{" + m[1] + "}"
+	})
+	return path, params
+}
+
+// operationId takes the handle function name
+func operationId(f http.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func jsonContent(schema *OpenApiSchema) map[string]*OpenApiMediaType {
+	return map[string]*OpenApiMediaType{mediaTypeJson: {Schema: schema}}
+}
+
+func schemaRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// schemaGenerator derives JSON schemas from Go types by reflection
+// named structs are put to components and referenced
+type schemaGenerator struct {
+	schemas map[string]*OpenApiSchema
+	names   map[reflect.Type]string // names component names of the registered types
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *OpenApiSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &OpenApiSchema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenApiSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenApiSchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenApiSchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &OpenApiSchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenApiSchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenApiSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &OpenApiSchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &OpenApiSchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name, ok := g.names[t]
+		if !ok {
+			// register before filling properties to support recursive types
+			name = g.componentName(t)
+			g.names[t] = name
+			g.schemas[name] = &OpenApiSchema{}
+			*g.schemas[name] = *g.object(t)
+		}
+		return &OpenApiSchema{Ref: schemaRef(name)}
+	default:
+		// interface{} or unsupported types accept any value
+		return &OpenApiSchema{}
+	}
+}
+
+// componentName takes the type name, if it's already taken by a type of another package, the name is qualified by the package path
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, ok := g.schemas[name]; !ok {
+		return name
+	}
+	name = componentNameReplacer.Replace(t.PkgPath()) + "." + t.Name()
+	// types declared in functions of the same package share the package path
+	for i := 2; ; i++ {
+		if _, ok := g.schemas[name]; !ok {
+			return name
+		}
+		name = fmt.Sprintf("%s.%s_%d", componentNameReplacer.Replace(t.PkgPath()), t.Name(), i)
+	}
+}
+
+func (g *schemaGenerator) object(t reflect.Type) *OpenApiSchema {
+	s := &OpenApiSchema{Type: "object", Properties: map[string]*OpenApiSchema{}}
+	g.properties(t, s)
+	return s
+}
+
+func (g *schemaGenerator) properties(t reflect.Type, s *OpenApiSchema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if tag == "-" {
+			continue
+		}
+		// embedded structs without json name are flattened as encoding/json does
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.properties(ft, s)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schema(f.Type)
+		if isRequired(f) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// isRequired checks if the field is validated as required
+func isRequired(f reflect.StructField) bool {
+	for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"net/http"
+	"time"
+)
+
+type testBase struct {
+	Id string `json:"id" validate:"required,uuid"`
+}
+
+type testItem struct {
+	testBase
+	Name     string             `json:"name" validate:"required,max=256"`
+	Count    int                `json:"count,omitempty" validate:"required_if=Name test"`
+	Rating   float64            `json:"rating"`
+	Ratings  map[string]float64 `json:"ratings"`
+	Children []*testItem        `json:"children"`
+	Created  *time.Time         `json:"created"`
+	Params   interface{}        `json:"params"`
+	Skipped  string             `json:"-"`
+	internal string
+}
+
+// testItemAlias refers to the package level testItem from the function scope where its name is shadowed
+type testItemAlias = testItem
+
+func (s *routingTestSuite) openApi() *OpenApi {
+	b := s.builder()
+	b.SetRoutes([]*Route{
+		R("/items/{itemId}", s.ok).GET().Summary("get").Query("size").Response(testItem{}),
+		R("/items/{itemId}/{kind:pros|cons}", s.ok).PUT().POST().Request([]*testItem{}).Response(kitHttp.EmptyOkResponse),
+		R("/health", s.ok).GET().NoAuth(),
+	})
+	return b.OpenApi(&OpenApiInfo{Title: "test", Version: "1"})
+}
+
+func (s *routingTestSuite) Test_OpenApi_Paths() {
+	doc := s.openApi()
+	s.Equal(OpenApiVersion, doc.OpenApi)
+	s.Len(doc.Paths, 3)
+
+	op := doc.Paths["/items/{itemId}"]["get"]
+	s.NotEmpty(op)
+	s.Equal("get", op.Summary)
+	s.Equal("ok", op.OperationId)
+	s.Len(op.Parameters, 2)
+	s.Equal(&OpenApiParameter{Name: "itemId", In: "path", Required: true, Schema: &OpenApiSchema{Type: "string"}}, op.Parameters[0])
+	s.Equal("query", op.Parameters[1].In)
+	s.Nil(op.RequestBody)
+	s.Equal(schemaRef("testItem"), op.Responses["200"].Content[mediaTypeJson].Schema.Ref)
+	s.Equal(schemaRef(errorSchema), op.Responses["default"].Content[mediaTypeJson].Schema.Ref)
+	s.Equal([]map[string][]string{{securitySchemeBearer: {}}}, op.Security)
+
+	path := doc.Paths["/items/{itemId}/{kind}"]
+	s.Len(path, 2)
+	s.Equal(path["put"], path["post"])
+	s.Equal("^(pros|cons)$", path["put"].Parameters[1].Schema.Pattern)
+	rq := path["put"].RequestBody.Content[mediaTypeJson].Schema
+	s.Equal("array", rq.Type)
+	s.Equal(schemaRef("testItem"), rq.Items.Ref)
+	rs := path["put"].Responses["200"].Content[mediaTypeJson].Schema
+	s.Equal("object", rs.Type)
+	s.Equal("string", rs.Properties["status"].Type)
+
+	s.Empty(doc.Paths["/health"]["get"].Security)
+}
+
+func (s *routingTestSuite) Test_OpenApi_Schemas() {
+	doc := s.openApi()
+	s.NotEmpty(doc.Components.Schemas[errorSchema])
+	s.Equal("object", doc.Components.Schemas[errorSchema].Properties["details"].Type)
+
+	item := doc.Components.Schemas["testItem"]
+	s.NotEmpty(item)
+	s.Len(item.Properties, 8)
+	s.Equal("string", item.Properties["id"].Type)
+	s.Equal(&OpenApiSchema{Type: "integer", Format: "int32"}, item.Properties["count"])
+	s.Equal(&OpenApiSchema{Type: "number", Format: "double"}, item.Properties["rating"])
+	s.Equal("number", item.Properties["ratings"].AdditionalProperties.Type)
+	s.Equal(schemaRef("testItem"), item.Properties["children"].Items.Ref)
+	s.Equal(&OpenApiSchema{Type: "string", Format: "date-time"}, item.Properties["created"])
+	s.Equal(&OpenApiSchema{}, item.Properties["params"])
+	s.Equal([]string{"id", "name"}, item.Required)
+}
+
+func (s *routingTestSuite) Test_OpenApi_SchemaNameCollision() {
+	// Error collides with kitHttp.Error, testItem collides with the package level type
+	type Error struct {
+		Reason string `json:"reason"`
+	}
+	type testItem struct {
+		Value string `json:"value"`
+	}
+	b := s.builder()
+	b.SetRoutes([]*Route{
+		R("/items", s.ok).GET().Response(testItem{}),
+		R("/items/errors", s.ok).GET().Response(Error{}),
+		R("/items/{itemId}", s.ok).GET().Response(testItemAlias{}),
+	})
+	doc := b.OpenApi(&OpenApiInfo{Title: "test", Version: "1"})
+
+	s.NotEmpty(doc.Components.Schemas[errorSchema].Properties["details"])
+	errRef := doc.Paths["/items/errors"]["get"].Responses["200"].Content[mediaTypeJson].Schema.Ref
+	s.Equal(schemaRef("github_com_mikhailbolshakov_decision_http.Error"), errRef)
+
+	item := doc.Components.Schemas["testItem"]
+	s.NotEmpty(item.Properties["value"])
+	aliasRef := doc.Paths["/items/{itemId}"]["get"].Responses["200"].Content[mediaTypeJson].Schema.Ref
+	s.Equal(schemaRef("github_com_mikhailbolshakov_decision_http.testItem"), aliasRef)
+	s.NotEmpty(doc.Components.Schemas["github_com_mikhailbolshakov_decision_http.testItem"].Properties["name"])
+}
+
+func (s *routingTestSuite) Test_OpenApi_Route() {
+	b := s.builder()
+	b.SetRoutes([]*Route{R("/items", s.ok).GET(), b.OpenApiRoute(&OpenApiInfo{Title: "test", Version: "1"})})
+	s.NoError(b.validate())
+	doc := &OpenApi{}
+	kitHttp.NewTestRequest(s.T(), s.Ctx).GET().Url(OpenApiUrl).RsBody(doc).AssertCode(http.StatusOK).Make(b.handleFn(b.routes[1]))
+	s.Equal("test", doc.Info.Title)
+	s.Len(doc.Paths, 2)
+	s.NotEmpty(doc.Paths["/items"]["get"])
+}
@@ -2,9 +2,17 @@ package http
 
 import (
 	"github.com/gorilla/mux"
+	"github.com/mikhailbolshakov/decision/errors"
 	"github.com/mikhailbolshakov/decision/kit"
 	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"go.uber.org/multierr"
 	"net/http"
+	"strings"
+)
+
+const (
+	// TokenTypeAccess access token
+	TokenTypeAccess = "access"
 )
 
 type RouteBuilder struct {
@@ -36,16 +44,23 @@ type Route struct {
 	handler     http.Handler
 	middlewares []mux.MiddlewareFunc
 	authTokens  []string
+	roles       []string
+	permissions []string
+	rateLimit   string
+	summary     string
+	request     interface{}
+	response    interface{}
+	query       []string
 	subRouter   bool
 }
 
+// Build validates all the routes and sets up http routing
+// if there are invalid routes, nothing is set up and an error listing all the problems is returned
 func (r *RouteBuilder) Build() error {
+	if err := r.validate(); err != nil {
+		return err
+	}
 	for _, route := range r.routes {
-		// validate route
-		err := route.validate()
-		if err != nil {
-			return err
-		}
 		// setup http routing
 		httpRouter := r.http.RootRouter
 		if route.subRouter {
@@ -56,14 +71,7 @@ func (r *RouteBuilder) Build() error {
 			}
 		}
 		if route.handleFn != nil {
-			handleFn := route.handleFn
-			// if authentication, apply special middleware
-			if r.mdw != nil {
-				if len(route.authTokens) > 0 {
-					handleFn = r.mdw.AuthAccessTokenMiddleware(handleFn, route.authTokens...)
-				}
-			}
-			httpRouter.HandleFunc(route.url, handleFn).Methods(route.verbs...)
+			httpRouter.HandleFunc(route.url, r.handleFn(route)).Methods(route.verbs...)
 		} else if route.handler != nil {
 			// if handler specified, it means all processing done by it
 			httpRouter.PathPrefix(route.urlPrefix).Handler(route.handler)
@@ -72,12 +80,35 @@ func (r *RouteBuilder) Build() error {
 	return nil
 }
 
+// handleFn wraps route's handle function with authentication, rate limiting and authorization middlewares
+func (r *RouteBuilder) handleFn(route *Route) http.HandlerFunc {
+	handleFn := route.handleFn
+	// roles and permissions are checked after the context is populated by authentication
+	if r.mdw != nil && len(route.permissions) > 0 {
+		handleFn = r.mdw.PermissionsMiddleware(handleFn, route.permissions...)
+	}
+	if r.mdw != nil && len(route.roles) > 0 {
+		handleFn = r.mdw.RolesMiddleware(handleFn, route.roles...)
+	}
+	// rate limit is applied after authentication, so that authenticated requests are limited per user
+	if route.rateLimit != "" && r.http != nil && r.http.Limiter != nil {
+		handleFn = r.http.Limiter.Middleware(route.rateLimit, handleFn)
+	}
+	// if authentication, apply special middleware
+	if r.mdw != nil && len(route.authTokens) > 0 {
+		handleFn = r.mdw.AuthAccessTokenMiddleware(handleFn, route.authTokens...)
+	}
+	return handleFn
+}
+
 // R starts building a new route with url and handle function
+// route requires an access token unless NoAuth is specified
 func R(url string, f func(http.ResponseWriter, *http.Request)) *Route {
 	return &Route{
-		id:       kit.NewRandString(),
-		url:      url,
-		handleFn: f,
+		id:         kit.NewRandString(),
+		url:        url,
+		handleFn:   f,
+		authTokens: []string{TokenTypeAccess},
 	}
 }
 
@@ -136,6 +167,48 @@ func (r *Route) NoAuth() *Route {
 	return r
 }
 
+// Roles specifies roles required to access the route, a user must have all of them
+func (r *Route) Roles(roles ...string) *Route {
+	r.roles = append(r.roles, roles...)
+	return r
+}
+
+// Permissions specifies permissions required to access the route, roles of a user must grant all of them
+func (r *Route) Permissions(permissions ...string) *Route {
+	r.permissions = append(r.permissions, permissions...)
+	return r
+}
+
+// RateLimit applies the rate limit configured with the name, requests aren't limited if it isn't configured
+func (r *Route) RateLimit(name string) *Route {
+	r.rateLimit = name
+	return r
+}
+
+// Summary specifies route's description for API docs
+func (r *Route) Summary(summary string) *Route {
+	r.summary = summary
+	return r
+}
+
+// Request specifies a value of the request body type for API docs
+func (r *Route) Request(v interface{}) *Route {
+	r.request = v
+	return r
+}
+
+// Response specifies a value of the response body type for API docs
+func (r *Route) Response(v interface{}) *Route {
+	r.response = v
+	return r
+}
+
+// Query specifies query parameters for API docs
+func (r *Route) Query(params ...string) *Route {
+	r.query = append(r.query, params...)
+	return r
+}
+
 // HandleFn specifies a handle function for route
 func (r *Route) HandleFn(f func(http.ResponseWriter, *http.Request)) *Route {
 	r.handleFn = f
@@ -155,18 +228,65 @@ func (r *Route) Middlewares(mdws ...mux.MiddlewareFunc) *Route {
 	return r
 }
 
+// validate validates all the routes and checks the same method and path isn't registered twice
+func (r *RouteBuilder) validate() error {
+	var errs error
+	registered := map[string]struct{}{}
+	for _, route := range r.routes {
+		errs = multierr.Append(errs, route.validate())
+		if route.handleFn == nil {
+			continue
+		}
+		path := route.urlPrefix + route.url
+		for _, verb := range route.verbs {
+			// routes differing in variable names only match the same requests
+			key := verb + " " + pathTemplate(path)
+			if _, ok := registered[key]; ok {
+				errs = multierr.Append(errs, errors.ErrRouteBuilderDuplicate(verb, path))
+				continue
+			}
+			registered[key] = struct{}{}
+		}
+	}
+	return errs
+}
+
+// pathTemplate replaces every {var} or {var:pattern} of the path with a placeholder
+func pathTemplate(path string) string {
+	var sb strings.Builder
+	depth := 0
+	for _, c := range path {
+		switch {
+		case c == '{':
+			if depth == 0 {
+				sb.WriteString("{}")
+			}
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case depth == 0:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
 func (r *Route) validate() error {
-	//if r.url == "" && r.urlPrefix == "" {
-	//	return errors.ErrRouteBuilderUrlEmpty()
-	//}
-	//if len(r.verbs) == 0 && r.handleFn != nil {
-	//	return errors.ErrRouteBuilderVerbEmpty(r.url)
-	//}
-	//if r.handler == nil && r.handleFn == nil {
-	//	return errors.ErrRouteBuilderBothHandleFuncAndHandlerEmpty(r.url)
-	//}
-	//if len(r.middlewares) > 0 && !r.subRouter {
-	//	return errors.ErrRouteBuilderSpecialMiddlewaresRequireSubrouting(r.url)
-	//}
-	return nil
+	var errs error
+	if r.url == "" && r.urlPrefix == "" {
+		errs = multierr.Append(errs, errors.ErrRouteBuilderUrlEmpty())
+	}
+	if len(r.verbs) == 0 && r.handleFn != nil {
+		errs = multierr.Append(errs, errors.ErrRouteBuilderVerbEmpty(r.url))
+	}
+	if r.handler == nil && r.handleFn == nil {
+		errs = multierr.Append(errs, errors.ErrRouteBuilderBothHandleFuncAndHandlerEmpty(r.url))
+	}
+	if len(r.middlewares) > 0 && !r.subRouter {
+		errs = multierr.Append(errs, errors.ErrRouteBuilderSpecialMiddlewaresRequireSubrouting(r.url))
+	}
+	if (len(r.roles) > 0 || len(r.permissions) > 0) && len(r.authTokens) == 0 {
+		errs = multierr.Append(errs, errors.ErrRouteBuilderRolesRequireAuth(r.url))
+	}
+	return errs
 }
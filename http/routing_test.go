@@ -0,0 +1,192 @@
+package http
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/auth"
+	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/multierr"
+	"net/http"
+	"testing"
+)
+
+// verifierMock accepts any token and returns the given claims
+type verifierMock struct {
+	claims *auth.Claims
+}
+
+func (v *verifierMock) Verify(ctx context.Context, token string) (*auth.Claims, error) {
+	return v.claims, nil
+}
+
+type routingTestSuite struct {
+	kit.Suite
+	userId string
+}
+
+func (s *routingTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+	s.userId = kit.NewId()
+}
+
+func TestRoutingSuite(t *testing.T) {
+	suite.Run(t, new(routingTestSuite))
+}
+
+func (s *routingTestSuite) builder(roles ...string) *RouteBuilder {
+	verifier := &verifierMock{claims: &auth.Claims{Subject: s.userId, Roles: roles}}
+	return NewRouteBuilder(nil, NewMiddleware(verifier, "decision.admin", map[string][]string{
+		"decision.monitoring": {"sys.metrics"},
+		"decision.support":    {"sys.metrics", "sys.logs"},
+	}))
+}
+
+func (s *routingTestSuite) ok(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *routingTestSuite) request() *kitHttp.TestRequest {
+	return kitHttp.NewTestRequest(s.T(), s.Ctx).GET().Url("/users/me").Header("Authorization", "Bearer token")
+}
+
+func (s *routingTestSuite) Test_Roles_Ok() {
+	route := R("/users/{userId}", s.ok).GET().Roles("decision.editor", "decision.viewer")
+	s.request().AssertOk().Make(s.builder("decision.viewer", "decision.editor", "any").handleFn(route))
+}
+
+func (s *routingTestSuite) Test_Roles_Forbidden() {
+	route := R("/users/{userId}", s.ok).GET().Roles("decision.editor", "decision.viewer")
+	s.request().
+		AssertCode(http.StatusForbidden).
+		AssertAppError(kitHttp.ErrCodeAuthForbidden).
+		Make(s.builder("decision.viewer").handleFn(route))
+}
+
+func (s *routingTestSuite) Test_Permissions_Ok() {
+	route := R("/users/{userId}", s.ok).GET().Permissions("sys.metrics", "sys.logs")
+	s.request().AssertOk().Make(s.builder("decision.monitoring", "decision.support").handleFn(route))
+}
+
+func (s *routingTestSuite) Test_Permissions_Forbidden() {
+	route := R("/users/{userId}", s.ok).GET().Permissions("sys.metrics", "sys.logs")
+	s.request().
+		AssertCode(http.StatusForbidden).
+		AssertAppError(kitHttp.ErrCodeAuthForbidden).
+		Make(s.builder("decision.monitoring", "sys.logs").handleFn(route))
+}
+
+func (s *routingTestSuite) Test_Permissions_Admin() {
+	route := R("/users/{userId}", s.ok).GET().Permissions("sys.metrics", "sys.logs")
+	s.request().AssertOk().Make(s.builder("decision.admin").handleFn(route))
+}
+
+func (s *routingTestSuite) Test_NoRoles_Ok() {
+	route := R("/users/{userId}", s.ok).GET()
+	s.request().AssertOk().Make(s.builder().handleFn(route))
+}
+
+func (s *routingTestSuite) Test_AnotherUser_Forbidden() {
+	route := R("/users/{userId}", s.ok).GET()
+	s.request().
+		Var("userId", kit.NewId()).
+		AssertCode(http.StatusForbidden).
+		AssertAppError(kitHttp.ErrCodeAuthForbidden).
+		Make(s.builder().handleFn(route))
+}
+
+func (s *routingTestSuite) Test_AnotherUser_Admin() {
+	route := R("/users/{userId}", s.ok).GET()
+	s.request().Var("userId", kit.NewId()).AssertOk().Make(s.builder("decision.admin").handleFn(route))
+}
+
+func (s *routingTestSuite) Test_NoToken_Unauthorized() {
+	route := R("/users/{userId}", s.ok).GET()
+	kitHttp.NewTestRequest(s.T(), s.Ctx).GET().Url("/users/me").
+		AssertCode(http.StatusUnauthorized).
+		AssertAppError(kitHttp.ErrCodeAuthFailed).
+		Make(s.builder().handleFn(route))
+}
+
+func (s *routingTestSuite) Test_Build_Ok() {
+	b := s.builder()
+	b.SetRoutes([]*Route{R("/users/{userId}", s.ok).GET(), R("/users/{userId}", s.ok).PUT()})
+	b.SetRoutes([]*Route{R("/health", s.ok).GET().NoAuth()})
+	s.NoError(b.validate())
+}
+
+func (s *routingTestSuite) Test_Build_Invalid() {
+	b := s.builder()
+	b.SetRoutes([]*Route{
+		R("", s.ok).GET(),
+		R("/no-verbs", s.ok),
+		R("/no-handler", nil).GET(),
+		R("/middlewares", s.ok).GET().Middlewares(func(h http.Handler) http.Handler { return h }),
+		R("/roles", s.ok).GET().NoAuth().Roles("decision.admin"),
+		R("/permissions", s.ok).GET().NoAuth().Permissions("sys.metrics"),
+		R("/users/{userId}", s.ok).GET(),
+	})
+	b.SetRoutes([]*Route{R("/users/{userId}", s.ok).POST().GET()})
+	b.SetRoutes([]*Route{R("/users/{id:[0-9a-f-]{36}}", s.ok).PUT(), R("/users/{uid}", s.ok).PUT()})
+	err := b.Build()
+	s.Error(err)
+	var codes []string
+	for _, e := range multierr.Errors(err) {
+		appErr, ok := kit.IsAppErr(e)
+		s.True(ok)
+		codes = append(codes, appErr.Code())
+	}
+	s.Equal([]string{
+		errors.ErrCodeRouteBuilderUrlEmpty,
+		errors.ErrCodeRouteBuilderVerbEmpty,
+		errors.ErrCodeRouteBuilderBothHandleFuncAndHandlerEmpty,
+		errors.ErrCodeRouteBuilderSpecialMiddlewaresRequireSubrouting,
+		errors.ErrCodeRouteBuilderRolesRequireAuth,
+		errors.ErrCodeRouteBuilderRolesRequireAuth,
+		errors.ErrCodeRouteBuilderDuplicate,
+		errors.ErrCodeRouteBuilderDuplicate,
+	}, codes)
+}
+
+func (s *routingTestSuite) Test_RateLimit_PerUser() {
+	srv := kitHttp.NewHttpServer(&kitHttp.Config{RateLimits: map[string]*kitHttp.RateLimit{"decisions": {Rps: 0.001, Burst: 1}}}, decision.LF())
+	verifier := &verifierMock{claims: &auth.Claims{Subject: s.userId}}
+	handleFn := NewRouteBuilder(srv, NewMiddleware(verifier, "", nil)).handleFn(R("/users/{userId}", s.ok).GET().RateLimit("decisions"))
+	s.request().AssertOk().Make(handleFn)
+	s.request().
+		AssertCode(http.StatusTooManyRequests).
+		AssertAppError(kitHttp.ErrCodeHttpTooManyRequests).
+		Make(handleFn)
+}
+
+func (s *routingTestSuite) Test_Error_Localized() {
+	srv := kitHttp.NewHttpServer(&kitHttp.Config{RateLimits: map[string]*kitHttp.RateLimit{"decisions": {Rps: 0.001, Burst: 1}}}, decision.LF())
+	mdw := NewMiddleware(&verifierMock{claims: &auth.Claims{Subject: s.userId}}, "", nil)
+	handler := mdw.SetContextMiddleware(NewRouteBuilder(srv, mdw).handleFn(R("/users/{userId}", s.ok).GET().RateLimit("decisions")))
+	s.request().AssertOk().Make(handler.ServeHTTP)
+
+	rs := &kitHttp.Error{}
+	s.request().
+		Header(HeaderAcceptLanguage, "ru-RU,ru;q=0.9,en;q=0.8").
+		AssertCode(http.StatusTooManyRequests).
+		RsBody(rs).
+		Make(handler.ServeHTTP)
+	s.Equal(kitHttp.ErrCodeHttpTooManyRequests, rs.Code)
+	s.Equal("Слишком много запросов, повторите позже", rs.Message)
+
+	// unsupported language falls back to english
+	s.request().
+		Header(HeaderAcceptLanguage, "de").
+		AssertCode(http.StatusTooManyRequests).
+		RsBody(rs).
+		Make(handler.ServeHTTP)
+	s.Equal("Too many requests, try again later", rs.Message)
+}
+
+func (s *routingTestSuite) Test_PathTemplate() {
+	s.Equal("/users/{}/problems/{}", pathTemplate("/users/{userId}/problems/{problemId}"))
+	s.Equal("/users/{}", pathTemplate("/users/{id:[0-9]{3}}"))
+	s.Equal("/health", pathTemplate("/health"))
+}
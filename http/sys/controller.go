@@ -2,25 +2,56 @@ package sys
 
 import (
 	"github.com/mikhailbolshakov/decision"
+	"github.com/mikhailbolshakov/decision/kit/health"
 	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"github.com/mikhailbolshakov/decision/kit/metrics"
 	"net/http"
 )
 
 type Controller interface {
 	kitHttp.Controller
 	Health(http.ResponseWriter, *http.Request)
+	Live(http.ResponseWriter, *http.Request)
+	Ready(http.ResponseWriter, *http.Request)
+	Metrics(http.ResponseWriter, *http.Request)
 }
 
 type ctrlImpl struct {
 	kitHttp.BaseController
+	health health.Registry
 }
 
-func NewController() Controller {
+func NewController(health health.Registry) Controller {
 	return &ctrlImpl{
 		BaseController: kitHttp.BaseController{Logger: decision.LF()},
+		health:         health,
 	}
 }
 
+// Health is kept for compatibility, it's the same as readiness
 func (c *ctrlImpl) Health(w http.ResponseWriter, r *http.Request) {
-	c.RespondOK(w, kitHttp.EmptyOkResponse)
+	c.Ready(w, r)
+}
+
+// Live responds if the service is alive, orchestrator restarts the instance otherwise
+func (c *ctrlImpl) Live(w http.ResponseWriter, r *http.Request) {
+	c.respondReport(w, c.health.Live(r.Context()))
+}
+
+// Ready responds if the service is able to serve requests, orchestrator stops routing to the instance otherwise
+func (c *ctrlImpl) Ready(w http.ResponseWriter, r *http.Request) {
+	c.respondReport(w, c.health.Ready(r.Context()))
+}
+
+func (c *ctrlImpl) respondReport(w http.ResponseWriter, report *health.Report) {
+	if !report.Up() {
+		c.RespondWithStatus(w, http.StatusServiceUnavailable, report)
+		return
+	}
+	c.RespondOK(w, report)
+}
+
+// Metrics exposes metrics in Prometheus text format
+func (c *ctrlImpl) Metrics(w http.ResponseWriter, r *http.Request) {
+	metrics.Default.Handler().ServeHTTP(w, r)
 }
@@ -0,0 +1,55 @@
+package sys
+
+import (
+	"context"
+	"errors"
+	"github.com/mikhailbolshakov/decision"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/health"
+	kitHttp "github.com/mikhailbolshakov/decision/kit/http"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type sysTestSuite struct {
+	kit.Suite
+	health health.Registry
+	ctrl   Controller
+}
+
+func (s *sysTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+}
+
+func (s *sysTestSuite) SetupTest() {
+	s.health = health.NewRegistry(nil, decision.LF())
+	s.health.Register("http", func(context.Context) error { return nil }, health.Liveness())
+	s.ctrl = NewController(s.health)
+}
+
+func TestSysSuite(t *testing.T) {
+	suite.Run(t, new(sysTestSuite))
+}
+
+func (s *sysTestSuite) Test_Ready_Ok() {
+	rs := &health.Report{}
+	kitHttp.NewTestRequest(s.T(), s.Ctx).GET().Url("/ready").RsBody(rs).AssertOk().Make(s.ctrl.Ready)
+	s.Equal(health.StatusUp, rs.Status)
+	s.Equal(health.StatusUp, rs.Components["http"].Status)
+}
+
+func (s *sysTestSuite) Test_Ready_DependencyDown() {
+	s.health.Register("postgres", func(context.Context) error { return errors.New("connection refused") })
+
+	rs := &health.Report{}
+	kitHttp.NewTestRequest(s.T(), s.Ctx).GET().Url("/ready").RsBody(rs).AssertCode(http.StatusServiceUnavailable).Make(s.ctrl.Ready)
+	s.Equal(health.StatusDown, rs.Status)
+	s.Equal(health.StatusDown, rs.Components["postgres"].Status)
+
+	// dependency doesn't affect liveness
+	rs = &health.Report{}
+	kitHttp.NewTestRequest(s.T(), s.Ctx).GET().Url("/live").RsBody(rs).AssertOk().Make(s.ctrl.Live)
+	s.Equal(health.StatusUp, rs.Status)
+	s.Len(rs.Components, 1)
+}
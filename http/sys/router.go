@@ -2,10 +2,19 @@ package sys
 
 import (
 	"github.com/mikhailbolshakov/decision/http"
+	"github.com/mikhailbolshakov/decision/kit/health"
+)
+
+const (
+	// PermissionMetrics allows reading metrics
+	PermissionMetrics = "sys.metrics"
 )
 
 func GetRoutes(c Controller) []*http.Route {
 	return []*http.Route{
-		http.R("/health", c.Health).GET().NoAuth(),
+		http.R("/health", c.Health).GET().NoAuth().Summary("Health check, the same as readiness").Response(&health.Report{}),
+		http.R("/live", c.Live).GET().NoAuth().Summary("Liveness probe").Response(&health.Report{}),
+		http.R("/ready", c.Ready).GET().NoAuth().Summary("Readiness probe with dependency checks").Response(&health.Report{}),
+		http.R("/metrics", c.Metrics).GET().Permissions(PermissionMetrics).Summary("Metrics in Prometheus text format"),
 	}
 }
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+	"net/http"
+)
+
+const (
+	ErrCodeAuthNoKeys           = "AUTH-001"
+	ErrCodeAuthKeyLoad          = "AUTH-002"
+	ErrCodeAuthTokenMalformed   = "AUTH-003"
+	ErrCodeAuthAlgUnsupported   = "AUTH-004"
+	ErrCodeAuthKeyNotFound      = "AUTH-005"
+	ErrCodeAuthSignatureInvalid = "AUTH-006"
+	ErrCodeAuthTokenExpired     = "AUTH-007"
+	ErrCodeAuthTokenNotActive   = "AUTH-008"
+	ErrCodeAuthIssuerInvalid    = "AUTH-009"
+	ErrCodeAuthAudienceInvalid  = "AUTH-010"
+	ErrCodeAuthSubjectEmpty     = "AUTH-011"
+)
+
+var (
+	ErrAuthNoKeys = func() error {
+		return kit.NewAppErrBuilder(ErrCodeAuthNoKeys, "neither secret nor public keys are configured").Err()
+	}
+	ErrAuthKeyLoad = func(cause error, path string) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthKeyLoad, "loading key from %s", path).Wrap(cause).Err()
+	}
+	ErrAuthTokenMalformed = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthTokenMalformed, "token malformed").Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrAuthAlgUnsupported = func(ctx context.Context, alg string) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthAlgUnsupported, "signing algorithm isn't supported").F(kit.KV{"alg": alg}).Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrAuthKeyNotFound = func(ctx context.Context, kid string) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthKeyNotFound, "signing key not found").F(kit.KV{"kid": kid}).Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrAuthSignatureInvalid = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthSignatureInvalid, "token signature is invalid").Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrAuthTokenExpired = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthTokenExpired, "token expired").Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrAuthTokenNotActive = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthTokenNotActive, "token isn't active yet").Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrAuthIssuerInvalid = func(ctx context.Context, iss string) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthIssuerInvalid, "token issuer is invalid").F(kit.KV{"iss": iss}).Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrAuthAudienceInvalid = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthAudienceInvalid, "token audience is invalid").Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrAuthSubjectEmpty = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthSubjectEmpty, "token subject is empty").Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+)
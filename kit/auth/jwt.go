@@ -0,0 +1,316 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// Config JWT validation configuration
+// at least one of Secret, PublicKeyPath, JwksPath must be specified
+type Config struct {
+	Issuer        string // Issuer expected token issuer, not checked if empty
+	Audience      string // Audience expected token audience, not checked if empty
+	Secret        string // Secret shared secret for HS256 tokens
+	PublicKeyPath string `config:"public-key-path"` // PublicKeyPath path to PEM encoded RSA public key for RS256 tokens
+	JwksPath      string `config:"jwks-path"`       // JwksPath path to local JWKS file with RSA keys for RS256 tokens
+	LeewaySec     int    `config:"leeway-sec"`      // LeewaySec allowed clock skew when checking exp and nbf
+}
+
+// Claims validated token claims
+type Claims struct {
+	Subject   string    // Subject user id
+	SessionId string    // SessionId session id
+	Username  string    // Username user name
+	Roles     []string  // Roles user roles
+	Issuer    string    // Issuer token issuer
+	Audience  []string  // Audience token audience
+	ExpiresAt time.Time // ExpiresAt token expiration, zero if not specified
+}
+
+// Verifier validates JWT tokens
+type Verifier interface {
+	// Verify validates the token signature and claims
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// audience can be either a string or an array of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	var arr []string
+	if err := json.Unmarshal(b, &arr); err != nil {
+		return err
+	}
+	*a = arr
+	return nil
+}
+
+type claims struct {
+	Sub               string   `json:"sub"`
+	Sid               string   `json:"sid,omitempty"`
+	PreferredUsername string   `json:"preferred_username,omitempty"`
+	Roles             []string `json:"roles,omitempty"`
+	Iss               string   `json:"iss,omitempty"`
+	Aud               audience `json:"aud,omitempty"`
+	Exp               *int64   `json:"exp,omitempty"`
+	Nbf               *int64   `json:"nbf,omitempty"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwks struct {
+	Keys []*jwk `json:"keys"`
+}
+
+type verifierImpl struct {
+	cfg    *Config
+	secret []byte
+	// keys RSA keys by kid, a key loaded from PEM has empty kid
+	keys map[string]*rsa.PublicKey
+	now  func() time.Time
+}
+
+// NewVerifier creates a verifier loading keys specified in config
+func NewVerifier(cfg *Config) (Verifier, error) {
+	v := &verifierImpl{
+		cfg:  cfg,
+		keys: make(map[string]*rsa.PublicKey),
+		now:  time.Now,
+	}
+	if cfg == nil || (cfg.Secret == "" && cfg.PublicKeyPath == "" && cfg.JwksPath == "") {
+		return nil, ErrAuthNoKeys()
+	}
+	if cfg.Secret != "" {
+		v.secret = []byte(cfg.Secret)
+	}
+	if cfg.PublicKeyPath != "" {
+		key, err := loadPublicKey(cfg.PublicKeyPath)
+		if err != nil {
+			return nil, ErrAuthKeyLoad(err, cfg.PublicKeyPath)
+		}
+		v.keys[""] = key
+	}
+	if cfg.JwksPath != "" {
+		keys, err := loadJwks(cfg.JwksPath)
+		if err != nil {
+			return nil, ErrAuthKeyLoad(err, cfg.JwksPath)
+		}
+		for kid, key := range keys {
+			v.keys[kid] = key
+		}
+	}
+	return v, nil
+}
+
+func (v *verifierImpl) Verify(ctx context.Context, token string) (*Claims, error) {
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrAuthTokenMalformed(ctx)
+	}
+
+	h := &header{}
+	if err := decodeSegment(parts[0], h); err != nil {
+		return nil, ErrAuthTokenMalformed(ctx)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrAuthTokenMalformed(ctx)
+	}
+
+	// the algorithm is accepted only if the corresponding key is configured,
+	// so that an RSA public key can never be used as an HMAC secret
+	signed := []byte(parts[0] + "." + parts[1])
+	switch {
+	case h.Alg == AlgHS256 && v.secret != nil:
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write(signed)
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return nil, ErrAuthSignatureInvalid(ctx)
+		}
+	case h.Alg == AlgRS256 && len(v.keys) > 0:
+		key, err := v.rsaKey(ctx, h.Kid)
+		if err != nil {
+			return nil, err
+		}
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+			return nil, ErrAuthSignatureInvalid(ctx)
+		}
+	default:
+		return nil, ErrAuthAlgUnsupported(ctx, h.Alg)
+	}
+
+	c := &claims{}
+	if err := decodeSegment(parts[1], c); err != nil {
+		return nil, ErrAuthTokenMalformed(ctx)
+	}
+	if err := v.validate(ctx, c); err != nil {
+		return nil, err
+	}
+
+	r := &Claims{
+		Subject:   c.Sub,
+		SessionId: c.Sid,
+		Username:  c.PreferredUsername,
+		Roles:     c.Roles,
+		Issuer:    c.Iss,
+		Audience:  c.Aud,
+	}
+	if c.Exp != nil {
+		r.ExpiresAt = time.Unix(*c.Exp, 0)
+	}
+	return r, nil
+}
+
+func (v *verifierImpl) validate(ctx context.Context, c *claims) error {
+	now := v.now()
+	leeway := time.Duration(v.cfg.LeewaySec) * time.Second
+	if c.Exp != nil && now.After(time.Unix(*c.Exp, 0).Add(leeway)) {
+		return ErrAuthTokenExpired(ctx)
+	}
+	if c.Nbf != nil && now.Add(leeway).Before(time.Unix(*c.Nbf, 0)) {
+		return ErrAuthTokenNotActive(ctx)
+	}
+	if v.cfg.Issuer != "" && c.Iss != v.cfg.Issuer {
+		return ErrAuthIssuerInvalid(ctx, c.Iss)
+	}
+	if v.cfg.Audience != "" {
+		found := false
+		for _, a := range c.Aud {
+			if a == v.cfg.Audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrAuthAudienceInvalid(ctx)
+		}
+	}
+	if c.Sub == "" {
+		return ErrAuthSubjectEmpty(ctx)
+	}
+	return nil
+}
+
+// rsaKey finds a key by kid, if token has no kid the only configured key is used
+func (v *verifierImpl) rsaKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	return nil, ErrAuthKeyNotFound(ctx, kid)
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// loadPublicKey loads RSA public key from PEM file (PKIX, PKCS1 or certificate)
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("not an RSA key")
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+		return nil, fmt.Errorf("not an RSA key")
+	}
+}
+
+// loadJwks loads RSA signing keys from JWKS file, other keys are skipped
+func loadJwks(path string) (map[string]*rsa.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set := &jwks{}
+	if err := json.Unmarshal(b, set); err != nil {
+		return nil, err
+	}
+	r := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		r[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(r) == 0 {
+		return nil, fmt.Errorf("no RSA signing keys found")
+	}
+	return r, nil
+}
@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+)
+
+var logger = kit.InitLogger(&kit.LogConfig{Level: kit.InfoLevel})
+var logf = func() kit.CLogger {
+	return kit.L(logger)
+}
+
+const testSecret = "secret"
+
+type jwtTestSuite struct {
+	kit.Suite
+	key *rsa.PrivateKey
+}
+
+func (s *jwtTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+	var err error
+	s.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	s.NoError(err)
+}
+
+func TestJwtSuite(t *testing.T) {
+	suite.Run(t, new(jwtTestSuite))
+}
+
+func (s *jwtTestSuite) segment(v interface{}) string {
+	b, err := json.Marshal(v)
+	s.NoError(err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (s *jwtTestSuite) hs256(claims map[string]interface{}) string {
+	signed := s.segment(map[string]string{"alg": AlgHS256, "typ": "JWT"}) + "." + s.segment(claims)
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *jwtTestSuite) rs256(kid string, claims map[string]interface{}) string {
+	signed := s.segment(map[string]string{"alg": AlgRS256, "typ": "JWT", "kid": kid}) + "." + s.segment(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	s.NoError(err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (s *jwtTestSuite) claims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":                "user",
+		"sid":                "session",
+		"preferred_username": "name",
+		"roles":              []string{"decision.admin"},
+		"iss":                "issuer",
+		"aud":                "decision",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func (s *jwtTestSuite) writeFile(name string, data []byte) string {
+	path := filepath.Join(s.T().TempDir(), name)
+	s.NoError(os.WriteFile(path, data, 0600))
+	return path
+}
+
+func (s *jwtTestSuite) jwksPath(kid string) string {
+	b, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "EC", "kid": "ec"},
+			{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+			},
+		},
+	})
+	s.NoError(err)
+	return s.writeFile("jwks.json", b)
+}
+
+func (s *jwtTestSuite) Test_NoKeys() {
+	_, err := NewVerifier(&Config{})
+	s.AssertAppErr(err, ErrCodeAuthNoKeys)
+}
+
+func (s *jwtTestSuite) Test_HS256_Ok() {
+	v, err := NewVerifier(&Config{Secret: testSecret, Issuer: "issuer", Audience: "decision"})
+	s.NoError(err)
+	c, err := v.Verify(s.Ctx, s.hs256(s.claims()))
+	s.NoError(err)
+	s.Equal("user", c.Subject)
+	s.Equal("session", c.SessionId)
+	s.Equal("name", c.Username)
+	s.Equal([]string{"decision.admin"}, c.Roles)
+	s.Equal([]string{"decision"}, c.Audience)
+	s.False(c.ExpiresAt.IsZero())
+}
+
+func (s *jwtTestSuite) Test_HS256_SignatureInvalid() {
+	v, err := NewVerifier(&Config{Secret: "another"})
+	s.NoError(err)
+	_, err = v.Verify(s.Ctx, s.hs256(s.claims()))
+	s.AssertAppErr(err, ErrCodeAuthSignatureInvalid)
+}
+
+func (s *jwtTestSuite) Test_Malformed() {
+	v, err := NewVerifier(&Config{Secret: testSecret})
+	s.NoError(err)
+	_, err = v.Verify(s.Ctx, "token")
+	s.AssertAppErr(err, ErrCodeAuthTokenMalformed)
+}
+
+func (s *jwtTestSuite) Test_AlgNone() {
+	v, err := NewVerifier(&Config{Secret: testSecret})
+	s.NoError(err)
+	token := s.segment(map[string]string{"alg": "none"}) + "." + s.segment(s.claims()) + "."
+	_, err = v.Verify(s.Ctx, token)
+	s.AssertAppErr(err, ErrCodeAuthAlgUnsupported)
+}
+
+func (s *jwtTestSuite) Test_Expired() {
+	v, err := NewVerifier(&Config{Secret: testSecret, LeewaySec: 30})
+	s.NoError(err)
+	claims := s.claims()
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, err = v.Verify(s.Ctx, s.hs256(claims))
+	s.AssertAppErr(err, ErrCodeAuthTokenExpired)
+	// within leeway
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	_, err = v.Verify(s.Ctx, s.hs256(claims))
+	s.NoError(err)
+}
+
+func (s *jwtTestSuite) Test_NotActive() {
+	v, err := NewVerifier(&Config{Secret: testSecret})
+	s.NoError(err)
+	claims := s.claims()
+	claims["nbf"] = time.Now().Add(time.Minute).Unix()
+	_, err = v.Verify(s.Ctx, s.hs256(claims))
+	s.AssertAppErr(err, ErrCodeAuthTokenNotActive)
+}
+
+func (s *jwtTestSuite) Test_IssuerAudience() {
+	v, err := NewVerifier(&Config{Secret: testSecret, Issuer: "another"})
+	s.NoError(err)
+	_, err = v.Verify(s.Ctx, s.hs256(s.claims()))
+	s.AssertAppErr(err, ErrCodeAuthIssuerInvalid)
+
+	v, err = NewVerifier(&Config{Secret: testSecret, Audience: "another"})
+	s.NoError(err)
+	_, err = v.Verify(s.Ctx, s.hs256(s.claims()))
+	s.AssertAppErr(err, ErrCodeAuthAudienceInvalid)
+
+	// audience as array
+	claims := s.claims()
+	claims["aud"] = []string{"other", "another"}
+	_, err = v.Verify(s.Ctx, s.hs256(claims))
+	s.NoError(err)
+}
+
+func (s *jwtTestSuite) Test_SubjectEmpty() {
+	v, err := NewVerifier(&Config{Secret: testSecret})
+	s.NoError(err)
+	claims := s.claims()
+	delete(claims, "sub")
+	_, err = v.Verify(s.Ctx, s.hs256(claims))
+	s.AssertAppErr(err, ErrCodeAuthSubjectEmpty)
+}
+
+func (s *jwtTestSuite) Test_RS256_Jwks() {
+	v, err := NewVerifier(&Config{JwksPath: s.jwksPath("key-1")})
+	s.NoError(err)
+	c, err := v.Verify(s.Ctx, s.rs256("key-1", s.claims()))
+	s.NoError(err)
+	s.Equal("user", c.Subject)
+
+	_, err = v.Verify(s.Ctx, s.rs256("key-2", s.claims()))
+	s.AssertAppErr(err, ErrCodeAuthKeyNotFound)
+}
+
+func (s *jwtTestSuite) Test_RS256_Pem() {
+	b, err := x509.MarshalPKIXPublicKey(&s.key.PublicKey)
+	s.NoError(err)
+	path := s.writeFile("key.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+	v, err := NewVerifier(&Config{PublicKeyPath: path})
+	s.NoError(err)
+	_, err = v.Verify(s.Ctx, s.rs256("", s.claims()))
+	s.NoError(err)
+
+	// HS256 isn't accepted when only RSA keys are configured
+	_, err = v.Verify(s.Ctx, s.hs256(s.claims()))
+	s.AssertAppErr(err, ErrCodeAuthAlgUnsupported)
+}
@@ -16,6 +16,7 @@ const (
 	CallerTypeQueue  = "queue"
 	CallerTypeWs     = "ws"
 	CallerTypeWebRtc = "webrtc"
+	CallerTypeGrpc   = "grpc"
 
 	AppTest = "test"
 )
@@ -49,6 +50,10 @@ type RequestContext struct {
 	Lang language.Tag `json:"_ctx.lang,omitempty" mapstructure:"_ctx.lang"`
 	// Kv arbitrary key-value
 	Kv KV `json:"_ctx.kv,omitempty" mapstructure:"_ctx.kv"`
+	// Tid trace ID
+	Tid string `json:"_ctx.tid,omitempty" mapstructure:"_ctx.tid"`
+	// SpId current span ID
+	SpId string `json:"_ctx.spid,omitempty" mapstructure:"_ctx.spid"`
 }
 
 func NewRequestCtx() *RequestContext {
@@ -107,6 +112,14 @@ func (r *RequestContext) GetKv() KV {
 	return r.Kv
 }
 
+func (r *RequestContext) GetTraceId() string {
+	return r.Tid
+}
+
+func (r *RequestContext) GetSpanId() string {
+	return r.SpId
+}
+
 func (r *RequestContext) Empty() *RequestContext {
 	return &RequestContext{}
 }
@@ -158,6 +171,10 @@ func (r *RequestContext) Rest() *RequestContext {
 	return r.WithCaller(CallerTypeRest)
 }
 
+func (r *RequestContext) Grpc() *RequestContext {
+	return r.WithCaller(CallerTypeGrpc)
+}
+
 func (r *RequestContext) Webrtc() *RequestContext {
 	return r.WithCaller(CallerTypeWebRtc)
 }
@@ -207,6 +224,12 @@ func (r *RequestContext) WithClient(clientId string) *RequestContext {
 	return r
 }
 
+func (r *RequestContext) WithTrace(traceId, spanId string) *RequestContext {
+	r.Tid = traceId
+	r.SpId = spanId
+	return r
+}
+
 func (r *RequestContext) WithRoles(roles ...string) *RequestContext {
 	r.Roles = roles
 	return r
@@ -234,6 +257,8 @@ func (r *RequestContext) ToMap() map[string]interface{} {
 		"_ctx.rl":   r.Roles,
 		"_ctx.lang": r.Lang,
 		"_ctx.kv":   r.Kv,
+		"_ctx.tid":  r.Tid,
+		"_ctx.spid": r.SpId,
 	}
 }
 
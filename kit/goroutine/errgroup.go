@@ -3,6 +3,7 @@ package goroutine
 import (
 	"context"
 	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/tracing"
 	"sync"
 )
 
@@ -110,10 +111,16 @@ func (g *errGroup) Go(f func() error) {
 
 	// prepare panic wrapper
 	wrapper := func() (err error) {
+		_, span := tracing.StartSpan(g.ctx, spanName(g.cmp, g.mth))
+		defer func() {
+			span.SetError(err)
+			span.End()
+		}()
 		defer func() {
 			if r := recover(); r != nil {
 				err = kit.ErrPanic(g.ctx, r)
 				logger.E(err).St().Err()
+				panicsTotal.Inc(g.cmp)
 			}
 		}()
 		err = f()
@@ -3,6 +3,8 @@ package goroutine
 import (
 	"context"
 	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/metrics"
+	"github.com/mikhailbolshakov/decision/kit/tracing"
 	"time"
 )
 
@@ -11,6 +13,11 @@ const (
 	Unrestricted = -1
 )
 
+var (
+	panicsTotal  = metrics.Default.Counter("goroutine_panics_total", "Number of panics recovered in goroutines", "component")
+	retriesTotal = metrics.Default.Counter("goroutine_retries_total", "Number of goroutine retries after panic", "component")
+)
+
 // Goroutine provides a wrapper around native GO goroutine with panic recovery and retry support
 type Goroutine interface {
 	// Go executes a f func as a goroutine
@@ -94,10 +101,16 @@ func (g *goroutine) Go(ctx context.Context, f func()) {
 
 	// prepare panic wrapper
 	wrapper := func() (err error) {
+		_, span := tracing.StartSpan(ctx, spanName(g.cmp, g.mth))
+		defer func() {
+			span.SetError(err)
+			span.End()
+		}()
 		defer func() {
 			if r := recover(); r != nil {
 				err = kit.ErrPanic(ctx, r)
 				logger.E(err).St().Err()
+				panicsTotal.Inc(g.cmp)
 			}
 		}()
 		f()
@@ -108,6 +121,7 @@ func (g *goroutine) Go(ctx context.Context, f func()) {
 		for {
 			if err := wrapper(); err != nil && (retryCounter < g.retry || g.retry < 0) {
 				logger.Dbg("panic retry")
+				retriesTotal.Inc(g.cmp)
 				// wait for some time before retry to avoid overloading in case of unrecoverable error
 				time.Sleep(g.delay)
 				// inc retry counter
@@ -118,3 +132,15 @@ func (g *goroutine) Go(ctx context.Context, f func()) {
 		}
 	}()
 }
+
+// spanName builds name of the span a goroutine is traced with
+func spanName(cmp, mth string) string {
+	name := "goroutine"
+	if cmp != "" {
+		name += " " + cmp
+	}
+	if mth != "" {
+		name += "." + mth
+	}
+	return name
+}
@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+	"net/http"
+)
+
+const (
+	ErrCodeGrpcSrvListen       = "GRPC-001"
+	ErrCodeGrpcSrvShutdown     = "GRPC-002"
+	ErrCodeGrpcSrvNotListening = "GRPC-003"
+	ErrCodeGrpcAuthFailed      = "GRPC-004"
+)
+
+var (
+	ErrGrpcSrvListen = func(cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeGrpcSrvListen, "").Wrap(cause).Err()
+	}
+	ErrGrpcSrvShutdown = func(cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeGrpcSrvShutdown, "graceful shutdown").Wrap(cause).Err()
+	}
+	ErrGrpcSrvNotListening = func(ctx context.Context, addr string) error {
+		return kit.NewAppErrBuilder(ErrCodeGrpcSrvNotListening, "server isn't listening").F(kit.KV{"addr": addr}).C(ctx).Err()
+	}
+	ErrGrpcAuthFailed = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeGrpcAuthFailed, "authorization failed").Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+)
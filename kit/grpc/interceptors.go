@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"strings"
+)
+
+// MDAuthorization metadata key a bearer token is passed with
+const MDAuthorization = "authorization"
+
+// requestContext rebuilds request context from incoming metadata
+// if the caller hasn't passed a request context, a new one is created
+// trace of the caller is continued if traceparent is passed
+func requestContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = kit.FromGrpcMD(ctx, md)
+	rCtx, ok := kit.Request(ctx)
+	if !ok || rCtx == nil {
+		rCtx = kit.NewRequestCtx()
+	}
+	rCtx = rCtx.Grpc()
+	if rCtx.GetRequestId() == "" {
+		rCtx = rCtx.WithNewRequestId()
+	}
+	if tp := md.Get(tracing.HeaderTraceparent); len(tp) > 0 {
+		if traceId, spanId, ok := tracing.ParseTraceparent(tp[0]); ok {
+			rCtx = rCtx.WithTrace(traceId, spanId)
+		}
+	}
+	return rCtx.ToContext(ctx)
+}
+
+// authenticate verifies the bearer token and populates the request context with the token claims
+func (s *Server) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if vals := md.Get(MDAuthorization); len(vals) > 0 {
+		if parts := strings.SplitN(vals[0], " ", 2); len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+			token = strings.TrimSpace(parts[1])
+		}
+	}
+	if token == "" {
+		return ctx, ErrGrpcAuthFailed(ctx)
+	}
+	claims, err := s.verifier.Verify(ctx, token)
+	if err != nil {
+		return ctx, err
+	}
+	rCtx, _ := kit.Request(ctx)
+	rCtx = rCtx.WithUser(claims.Subject, claims.Username).WithSessionId(claims.SessionId).WithRoles(claims.Roles...)
+	return rCtx.ToContext(ctx), nil
+}
+
+// call executes a handler within a span with panic recovery, an error is converted to gRPC status
+// if the server requires auth, the handler is executed only for an authenticated caller
+// setTrailer passes an application error code to the client
+func (s *Server) call(ctx context.Context, method string, setTrailer func(metadata.MD), handler func(ctx context.Context) error) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "grpc "+method)
+	span.SetAttr("rpc.method", method)
+	defer func() {
+		if r := recover(); r != nil {
+			err = kit.ErrPanic(ctx, r)
+			s.logger().Pr("grpc").Cmp("server").Mth(method).C(ctx).E(err).St().Err()
+		}
+		if err != nil {
+			span.SetError(err)
+			if appErr, ok := kit.IsAppErr(err); ok {
+				setTrailer(metadata.Pairs(MDErrorCode, appErr.Code()))
+			}
+			st := Status(err)
+			span.SetAttr("rpc.code", st.Code().String())
+			err = st.Err()
+		}
+		span.End()
+	}()
+	if s.verifier != nil {
+		if ctx, err = s.authenticate(ctx); err != nil {
+			return err
+		}
+	}
+	return handler(ctx)
+}
+
+func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var rs interface{}
+	err := s.call(requestContext(ctx), info.FullMethod,
+		func(md metadata.MD) { _ = grpc.SetTrailer(ctx, md) },
+		func(ctx context.Context) (err error) {
+			rs, err = handler(ctx, req)
+			return err
+		})
+	return rs, err
+}
+
+func (s *Server) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return s.call(requestContext(ss.Context()), info.FullMethod, ss.SetTrailer,
+		func(ctx context.Context) error {
+			return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		})
+}
+
+// serverStream substitutes stream context with the request context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/auth"
+	"github.com/mikhailbolshakov/decision/kit/goroutine"
+	"github.com/mikhailbolshakov/decision/kit/health"
+	"google.golang.org/grpc"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+type Config struct {
+	Port               string
+	ShutdownTimeoutSec int `config:"shutdown-timeout-sec"`
+}
+
+// Server represents gRPC server
+// request context is rebuilt from metadata, errors are converted to gRPC statuses, panics are recovered
+type Server struct {
+	Srv       *grpc.Server    // Srv - internal server
+	addr      string          // addr - listening address
+	logger    kit.CLoggerFunc // logger
+	listening int32           // listening is set to 1 while server accepts connections
+	shutdown  time.Duration   // shutdown timeout in-flight calls are waited for on shutdown
+	verifier  auth.Verifier   // verifier - if set, calls must pass a valid token
+}
+
+func NewServer(cfg *Config, logger kit.CLoggerFunc, opts ...grpc.ServerOption) *Server {
+	s := &Server{
+		addr:     fmt.Sprintf(":%s", cfg.Port),
+		logger:   logger,
+		shutdown: time.Duration(cfg.ShutdownTimeoutSec) * time.Second,
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(s.unaryInterceptor), grpc.ChainStreamInterceptor(s.streamInterceptor))
+	s.Srv = grpc.NewServer(opts...)
+	return s
+}
+
+// WithAuth requires each call to pass a bearer token with authorization metadata
+// user, session and roles of the request context are taken from the token claims, never from the caller's metadata
+func (s *Server) WithAuth(verifier auth.Verifier) *Server {
+	s.verifier = verifier
+	return s
+}
+
+func (s *Server) Listen() {
+	goroutine.New().
+		WithLoggerFn(s.logger).
+		WithRetry(goroutine.Unrestricted).
+		Cmp("grpc-server").
+		Mth("listen").
+		Go(context.Background(),
+			func() {
+				l := s.logger().Pr("grpc").Cmp("server").Mth("listen").F(kit.KV{"url": s.addr})
+				l.Inf("start listening")
+			start:
+				ln, err := net.Listen("tcp", s.addr)
+				if err == nil {
+					atomic.StoreInt32(&s.listening, 1)
+					err = s.Srv.Serve(ln)
+					atomic.StoreInt32(&s.listening, 0)
+				}
+				if err != nil {
+					if err != grpc.ErrServerStopped {
+						l.E(ErrGrpcSrvListen(err)).St().Err()
+						time.Sleep(time.Second * 5)
+						goto start
+					}
+					l.Dbg("server closed")
+				}
+			})
+}
+
+// RegisterHealthChecks registers check the server accepts connections
+func (s *Server) RegisterHealthChecks(r health.Registry) {
+	r.Register("grpc", func(ctx context.Context) error {
+		if atomic.LoadInt32(&s.listening) == 0 {
+			return ErrGrpcSrvNotListening(ctx, s.addr)
+		}
+		return nil
+	}, health.Liveness())
+}
+
+// Shutdown stops accepting connections and waits for in-flight calls within the shutdown timeout
+// when the timeout passes or ctx is done, connections are closed and in-flight calls are cancelled
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.shutdown > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.shutdown)
+		defer cancel()
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.Srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.Srv.Stop()
+		return ErrGrpcSrvShutdown(ctx.Err())
+	}
+}
+
+// Close closes connections immediately
+func (s *Server) Close() {
+	s.Srv.Stop()
+}
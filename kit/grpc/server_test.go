@@ -0,0 +1,242 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/auth"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var logger = kit.InitLogger(&kit.LogConfig{Level: kit.InfoLevel})
+var logf = func() kit.CLogger {
+	return kit.L(logger)
+}
+
+const testMethod = "/test.Test/Call"
+
+// testHandler handles calls of the test service
+type testHandler func(ctx context.Context) error
+
+// testServiceDesc describes a test service with a single unary method
+var testServiceDesc = grpc.ServiceDesc{
+	ServiceName: "test.Test",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Call",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				rq := &emptypb.Empty{}
+				if err := dec(rq); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return &emptypb.Empty{}, srv.(testHandler)(ctx)
+				}
+				return interceptor(ctx, rq, &grpc.UnaryServerInfo{Server: srv, FullMethod: testMethod}, handler)
+			},
+		},
+	},
+}
+
+type serverTestSuite struct {
+	kit.Suite
+}
+
+func (s *serverTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(serverTestSuite))
+}
+
+// serve starts server on a random port with a handler, returns a client connection
+func (s *serverTestSuite) serve(srv *Server, handler testHandler) *grpc.ClientConn {
+	srv.Srv.RegisterService(&testServiceDesc, handler)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	s.NoError(err)
+	go func() { _ = srv.Srv.Serve(ln) }()
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	s.NoError(err)
+	s.T().Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func (s *serverTestSuite) call(ctx context.Context, conn *grpc.ClientConn, opts ...grpc.CallOption) error {
+	return conn.Invoke(ctx, testMethod, &emptypb.Empty{}, &emptypb.Empty{}, opts...)
+}
+
+func (s *serverTestSuite) Test_RequestContext() {
+	srv := NewServer(&Config{}, logf)
+	defer srv.Close()
+	var rCtx *kit.RequestContext
+	conn := s.serve(srv, func(ctx context.Context) error {
+		rCtx, _ = kit.Request(ctx)
+		return nil
+	})
+
+	// request context is passed by the caller
+	rq := kit.NewRequestCtx().WithNewRequestId().WithUser("user-id", "user")
+	ctx := rq.ToContext(context.Background())
+	md, _ := kit.ContextToGrpcMD(ctx)
+	s.NoError(s.call(metadata.NewOutgoingContext(ctx, md), conn))
+	s.NotNil(rCtx)
+	s.Equal("user-id", rCtx.GetUserId())
+	s.Equal(rq.GetRequestId(), rCtx.GetRequestId())
+	s.Equal(kit.CallerTypeGrpc, rCtx.GetCaller())
+
+	// request context is created if not passed
+	rCtx = nil
+	s.NoError(s.call(context.Background(), conn))
+	s.NotNil(rCtx)
+	s.NotEmpty(rCtx.GetRequestId())
+	s.Equal(kit.CallerTypeGrpc, rCtx.GetCaller())
+}
+
+// verifierFunc verifies tokens with a function
+type verifierFunc func(ctx context.Context, token string) (*auth.Claims, error)
+
+func (f verifierFunc) Verify(ctx context.Context, token string) (*auth.Claims, error) {
+	return f(ctx, token)
+}
+
+func (s *serverTestSuite) Test_Auth() {
+	srv := NewServer(&Config{}, logf).WithAuth(verifierFunc(func(ctx context.Context, token string) (*auth.Claims, error) {
+		if token != "valid" {
+			return nil, auth.ErrAuthSignatureInvalid(ctx)
+		}
+		return &auth.Claims{Subject: "user-id", Username: "user", SessionId: "session-id", Roles: []string{"role"}}, nil
+	}))
+	defer srv.Close()
+	var rCtx *kit.RequestContext
+	conn := s.serve(srv, func(ctx context.Context) error {
+		rCtx, _ = kit.Request(ctx)
+		return nil
+	})
+
+	// identity passed with request context is replaced with the token claims
+	ctx := kit.NewRequestCtx().WithNewRequestId().WithUser("another", "another").WithRoles("admin").ToContext(context.Background())
+	md, _ := kit.ContextToGrpcMD(ctx)
+	md.Set(MDAuthorization, "Bearer valid")
+	s.NoError(s.call(metadata.NewOutgoingContext(ctx, md), conn))
+	s.Equal("user-id", rCtx.GetUserId())
+	s.Equal("user", rCtx.GetUsername())
+	s.Equal("session-id", rCtx.GetSessionId())
+	s.Equal([]string{"role"}, rCtx.GetRoles())
+
+	// no token
+	rCtx = nil
+	var trailer metadata.MD
+	err := s.call(metadata.NewOutgoingContext(ctx, metadata.Pairs(MDAuthorization, "valid")), conn, grpc.Trailer(&trailer))
+	s.Equal(codes.Unauthenticated, status.Code(err))
+	s.Equal([]string{ErrCodeGrpcAuthFailed}, trailer.Get(MDErrorCode))
+	s.Nil(rCtx)
+
+	// invalid token
+	err = s.call(metadata.NewOutgoingContext(ctx, metadata.Pairs(MDAuthorization, "Bearer invalid")), conn, grpc.Trailer(&trailer))
+	s.Equal(codes.Unauthenticated, status.Code(err))
+	s.Equal([]string{auth.ErrCodeAuthSignatureInvalid}, trailer.Get(MDErrorCode))
+	s.Nil(rCtx)
+}
+
+func (s *serverTestSuite) Test_AppError_Status() {
+	srv := NewServer(&Config{}, logf)
+	defer srv.Close()
+	conn := s.serve(srv, func(ctx context.Context) error {
+		return kit.NewAppErrBuilder("TST-001", "not found").Business().C(ctx).HttpSt(http.StatusNotFound).Err()
+	})
+
+	var trailer metadata.MD
+	err := s.call(context.Background(), conn, grpc.Trailer(&trailer))
+	s.Error(err)
+	s.Equal(codes.NotFound, status.Code(err))
+	s.Equal([]string{"TST-001"}, trailer.Get(MDErrorCode))
+}
+
+func (s *serverTestSuite) Test_Panic_Recovered() {
+	srv := NewServer(&Config{}, logf)
+	defer srv.Close()
+	conn := s.serve(srv, func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	var trailer metadata.MD
+	err := s.call(context.Background(), conn, grpc.Trailer(&trailer))
+	s.Error(err)
+	s.Equal(codes.Internal, status.Code(err))
+	s.Equal([]string{kit.ErrCodePanic}, trailer.Get(MDErrorCode))
+	// server keeps serving
+	s.Equal(codes.Internal, status.Code(s.call(context.Background(), conn)))
+}
+
+func (s *serverTestSuite) Test_Shutdown_Drained() {
+	srv := NewServer(&Config{ShutdownTimeoutSec: 5}, logf)
+	started := make(chan struct{})
+	conn := s.serve(srv, func(ctx context.Context) error {
+		close(started)
+		time.Sleep(time.Millisecond * 100)
+		return nil
+	})
+
+	errs := make(chan error, 1)
+	go func() { errs <- s.call(context.Background(), conn) }()
+	<-started
+
+	s.NoError(srv.Shutdown(s.Ctx))
+	// in-flight call is completed
+	s.NoError(<-errs)
+}
+
+func (s *serverTestSuite) Test_Shutdown_Timeout() {
+	srv := NewServer(&Config{ShutdownTimeoutSec: 5}, logf)
+	started := make(chan struct{})
+	conn := s.serve(srv, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	errs := make(chan error, 1)
+	go func() { errs <- s.call(context.Background(), conn) }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(s.Ctx, time.Millisecond*100)
+	defer cancel()
+	s.AssertAppErr(srv.Shutdown(ctx), ErrCodeGrpcSrvShutdown)
+	// in-flight call is cancelled
+	s.Error(<-errs)
+}
+
+func (s *serverTestSuite) Test_Status() {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"grpc status", kit.NewAppErrBuilder("TST-001", "").GrpcSt(uint32(codes.Aborted)).HttpSt(http.StatusBadRequest).Err(), codes.Aborted},
+		{"http status", kit.NewAppErrBuilder("TST-001", "").HttpSt(http.StatusForbidden).Err(), codes.PermissionDenied},
+		{"business", kit.NewAppErrBuilder("TST-001", "").Business().Err(), codes.FailedPrecondition},
+		{"system", kit.NewAppErrBuilder("TST-001", "").Err(), codes.Internal},
+		{"status", status.Error(codes.NotFound, ""), codes.NotFound},
+		{"canceled", context.Canceled, codes.Canceled},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"unknown", errors.New("error"), codes.Unknown},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			s.Equal(tt.code, Status(tt.err).Code())
+		})
+	}
+	s.Nil(Status(nil))
+}
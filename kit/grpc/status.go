@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+// MDErrorCode trailer metadata key an application error code is passed with
+const MDErrorCode = "app-err-code"
+
+// httpToGrpc maps HTTP statuses of application errors to gRPC codes
+var httpToGrpc = map[uint32]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// Status converts an error to gRPC status
+// gRPC status of application error is taken if specified, otherwise it's derived from HTTP status and error type
+func Status(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	if appErr, ok := kit.IsAppErr(err); ok {
+		return status.New(appErrCode(appErr), appErr.Error())
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	}
+	return status.New(codes.Unknown, err.Error())
+}
+
+func appErrCode(appErr *kit.AppError) codes.Code {
+	if st := appErr.GrpcStatus(); st != nil {
+		return codes.Code(*st)
+	}
+	if st := appErr.HttpStatus(); st != nil {
+		if code, ok := httpToGrpc[*st]; ok {
+			return code
+		}
+	}
+	if appErr.Type() == kit.ErrTypeBusiness {
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
@@ -0,0 +1,20 @@
+package health
+
+import (
+	"github.com/mikhailbolshakov/decision/kit"
+	"time"
+)
+
+const (
+	ErrCodeHealthCheckTimeout = "HLT-001"
+	ErrCodeHealthCheckPanic   = "HLT-002"
+)
+
+var (
+	ErrHealthCheckTimeout = func(name string, timeout time.Duration) error {
+		return kit.NewAppErrBuilder(ErrCodeHealthCheckTimeout, "check timed out").F(kit.KV{"check": name, "timeout": timeout.String()}).Err()
+	}
+	ErrHealthCheckPanic = func(name string) error {
+		return kit.NewAppErrBuilder(ErrCodeHealthCheckPanic, "check panicked").F(kit.KV{"check": name}).Err()
+	}
+)
@@ -0,0 +1,204 @@
+package health
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/goroutine"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	defaultTimeout = time.Second * 2
+)
+
+// Config health checks configuration
+type Config struct {
+	TimeoutMs  int `config:"timeout-ms"`   // TimeoutMs default timeout of a check
+	CacheTtlMs int `config:"cache-ttl-ms"` // CacheTtlMs how long a check result is reused, no caching if 0
+}
+
+// CheckFunc checks a component, nil means the component is healthy
+type CheckFunc func(ctx context.Context) error
+
+// ComponentStatus result of a component check
+type ComponentStatus struct {
+	Status     string    `json:"status"`          // Status up or down
+	Error      string    `json:"error,omitempty"` // Error why the component is down
+	DurationMs float64   `json:"durationMs"`      // DurationMs how long the check took
+	CheckedAt  time.Time `json:"checkedAt"`       // CheckedAt when the check was executed
+}
+
+// Report aggregated status of components, it's down if any of components is down
+type Report struct {
+	Status     string                      `json:"status"`
+	Components map[string]*ComponentStatus `json:"components,omitempty"`
+}
+
+// Up checks if all components are up
+func (r *Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Registry keeps component checkers
+type Registry interface {
+	// Register registers a readiness check of the component
+	Register(name string, check CheckFunc, opts ...Option)
+	// Live executes liveness checks
+	Live(ctx context.Context) *Report
+	// Ready executes readiness checks
+	Ready(ctx context.Context) *Report
+}
+
+// Component is implemented by components having something to check
+type Component interface {
+	// RegisterHealthChecks registers component's checks
+	RegisterHealthChecks(r Registry)
+}
+
+// Option check option
+type Option func(c *checker)
+
+// WithTimeout overrides the default timeout of the check
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *checker) {
+		c.timeout = timeout
+	}
+}
+
+// Liveness makes the check also a liveness one
+// liveness checks must not depend on external services, otherwise an outage of a dependency leads to restarts
+func Liveness() Option {
+	return func(c *checker) {
+		c.liveness = true
+	}
+}
+
+type checker struct {
+	sync.Mutex
+	name     string
+	check    CheckFunc
+	timeout  time.Duration
+	liveness bool
+	last     *ComponentStatus
+}
+
+type registryImpl struct {
+	sync.RWMutex
+	checkers map[string]*checker
+	cacheTtl time.Duration
+	timeout  time.Duration
+	logger   kit.CLoggerFunc
+}
+
+func NewRegistry(cfg *Config, logger kit.CLoggerFunc) Registry {
+	r := &registryImpl{
+		checkers: map[string]*checker{},
+		timeout:  defaultTimeout,
+		logger:   logger,
+	}
+	if cfg != nil {
+		if cfg.TimeoutMs > 0 {
+			r.timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+		}
+		r.cacheTtl = time.Duration(cfg.CacheTtlMs) * time.Millisecond
+	}
+	return r
+}
+
+func (r *registryImpl) Register(name string, check CheckFunc, opts ...Option) {
+	c := &checker{name: name, check: check, timeout: r.timeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.checkers[name] = c
+}
+
+func (r *registryImpl) Live(ctx context.Context) *Report {
+	return r.run(ctx, func(c *checker) bool { return c.liveness })
+}
+
+func (r *registryImpl) Ready(ctx context.Context) *Report {
+	return r.run(ctx, func(c *checker) bool { return true })
+}
+
+// run executes checks in parallel
+func (r *registryImpl) run(ctx context.Context, filter func(c *checker) bool) *Report {
+	r.RLock()
+	var checkers []*checker
+	for _, c := range r.checkers {
+		if filter(c) {
+			checkers = append(checkers, c)
+		}
+	}
+	r.RUnlock()
+	sort.Slice(checkers, func(i, j int) bool { return checkers[i].name < checkers[j].name })
+
+	statuses := make([]*ComponentStatus, len(checkers))
+	wg := sync.WaitGroup{}
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c *checker) {
+			defer wg.Done()
+			statuses[i] = r.status(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusUp, Components: map[string]*ComponentStatus{}}
+	for i, c := range checkers {
+		report.Components[c.name] = statuses[i]
+		if statuses[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// status returns a cached status or executes the check
+// concurrent callers wait for the running check rather than execute it once again
+func (r *registryImpl) status(ctx context.Context, c *checker) *ComponentStatus {
+	c.Lock()
+	defer c.Unlock()
+	if c.last != nil && r.cacheTtl > 0 && kit.Now().Sub(c.last.CheckedAt) < r.cacheTtl {
+		return c.last
+	}
+	start := kit.Now()
+	st := &ComponentStatus{Status: StatusUp, CheckedAt: start}
+	if err := r.execute(ctx, c); err != nil {
+		st.Status, st.Error = StatusDown, err.Error()
+		r.logger().Cmp("health").Mth("check").C(ctx).F(kit.KV{"check": c.name}).E(err).Warn()
+	}
+	st.DurationMs = float64(kit.Now().Sub(start).Microseconds()) / 1000
+	c.last = st
+	return st
+}
+
+// execute executes the check with timeout, the check isn't waited for after timeout
+func (r *registryImpl) execute(ctx context.Context, c *checker) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	res := make(chan error, 1)
+	goroutine.New().
+		WithLoggerFn(r.logger).
+		Cmp("health").
+		Mth(c.name).
+		Go(ctx, func() {
+			err := ErrHealthCheckPanic(c.name)
+			// if check panics, the deferred send reports it
+			defer func() { res <- err }()
+			err = c.check(ctx)
+		})
+	select {
+	case err := <-res:
+		return err
+	case <-ctx.Done():
+		return ErrHealthCheckTimeout(c.name, c.timeout)
+	}
+}
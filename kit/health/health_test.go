@@ -0,0 +1,96 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var logger = kit.InitLogger(&kit.LogConfig{Level: kit.InfoLevel})
+var logf = func() kit.CLogger {
+	return kit.L(logger)
+}
+
+type healthTestSuite struct {
+	kit.Suite
+}
+
+func (s *healthTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func TestHealthSuite(t *testing.T) {
+	suite.Run(t, new(healthTestSuite))
+}
+
+func ok(context.Context) error { return nil }
+
+func (s *healthTestSuite) Test_Empty() {
+	r := NewRegistry(nil, logf)
+	s.True(r.Live(s.Ctx).Up())
+	s.True(r.Ready(s.Ctx).Up())
+}
+
+func (s *healthTestSuite) Test_LiveAndReady() {
+	r := NewRegistry(nil, logf)
+	r.Register("http", ok, Liveness())
+	r.Register("db", func(context.Context) error { return errors.New("connection refused") })
+
+	live := r.Live(s.Ctx)
+	s.True(live.Up())
+	s.Len(live.Components, 1)
+	s.Equal(StatusUp, live.Components["http"].Status)
+
+	ready := r.Ready(s.Ctx)
+	s.False(ready.Up())
+	s.Len(ready.Components, 2)
+	s.Equal(StatusUp, ready.Components["http"].Status)
+	s.Equal(StatusDown, ready.Components["db"].Status)
+	s.Equal("connection refused", ready.Components["db"].Error)
+}
+
+func (s *healthTestSuite) Test_Timeout() {
+	r := NewRegistry(&Config{TimeoutMs: 10000}, logf)
+	r.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, WithTimeout(time.Millisecond*20))
+	report := r.Ready(s.Ctx)
+	s.False(report.Up())
+	s.Contains(report.Components["slow"].Error, ErrCodeHealthCheckTimeout)
+	s.Less(report.Components["slow"].DurationMs, float64(1000))
+}
+
+func (s *healthTestSuite) Test_Panic() {
+	r := NewRegistry(nil, logf)
+	r.Register("panic", func(context.Context) error { panic("boom") })
+	report := r.Ready(s.Ctx)
+	s.False(report.Up())
+	s.Contains(report.Components["panic"].Error, ErrCodeHealthCheckPanic)
+}
+
+func (s *healthTestSuite) Test_Cache() {
+	var calls int32
+	check := func(context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}
+
+	r := NewRegistry(&Config{CacheTtlMs: 60000}, logf)
+	r.Register("db", check)
+	first := r.Ready(s.Ctx)
+	second := r.Ready(s.Ctx)
+	s.Equal(int32(1), atomic.LoadInt32(&calls))
+	s.Equal(first.Components["db"].CheckedAt, second.Components["db"].CheckedAt)
+
+	// no caching by default
+	r = NewRegistry(nil, logf)
+	r.Register("db", check)
+	r.Ready(s.Ctx)
+	r.Ready(s.Ctx)
+	s.Equal(int32(3), atomic.LoadInt32(&calls))
+}
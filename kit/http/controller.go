@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/i18n"
+	"golang.org/x/text/language"
 	"io"
 	"mime"
 	"mime/multipart"
@@ -202,6 +204,10 @@ func (c *BaseController) RespondError(w http.ResponseWriter, err error) {
 	if appErr, ok := kit.IsAppErr(err); ok {
 		httpErr.Code = appErr.Code()
 		httpErr.Message = appErr.Message()
+		// message is localized to the language of the request the error occurred in
+		if msg, ok := i18n.Default.Message(errLang(appErr), appErr.Code(), appErr.Fields()); ok {
+			httpErr.Message = msg
+		}
 		httpErr.TranslationKey = "errors.app.code." + strings.ReplaceAll(strings.ToLower(appErr.Code()), "-", ".")
 		httpErr.Details = appErr.Fields()
 		httpErr.Type = appErr.Type()
@@ -217,6 +223,16 @@ func (c *BaseController) RespondError(w http.ResponseWriter, err error) {
 	c.RespondJson(w, httpStatus, httpErr)
 }
 
+// errLang returns language of the request context attached to the error
+func errLang(appErr *kit.AppError) language.Tag {
+	if rCtx, ok := appErr.Fields()["ctx"].(map[string]interface{}); ok {
+		if lang, ok := rCtx["_ctx.lang"].(language.Tag); ok {
+			return lang
+		}
+	}
+	return language.Und
+}
+
 func (c *BaseController) RespondWithStatus(w http.ResponseWriter, status int, payload interface{}) {
 	c.RespondJson(w, status, payload)
 }
@@ -230,7 +246,7 @@ func (c *BaseController) DecodeRequest(ctx context.Context, r *http.Request, bod
 	if err := decoder.Decode(body); err != nil {
 		return ErrHttpDecodeRequest(err, ctx)
 	}
-	return nil
+	return ValidateRequest(ctx, body)
 }
 
 func (c *BaseController) Var(ctx context.Context, r *http.Request, varName string, allowEmpty bool) (string, error) {
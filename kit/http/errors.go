@@ -45,6 +45,13 @@ const (
 	ErrCodeHttpProxyFileReadResponse         = "HTTP-035"
 	ErrCodeHttpProxyFileJsonUnmarshal        = "HTTP-036"
 	ErrCodeAuthFailed                        = "HTTP-037"
+	ErrCodeAuthForbidden                     = "HTTP-038"
+	ErrCodeHttpRequestInvalid                = "HTTP-039"
+	ErrCodeHttpSrvNotListening               = "HTTP-040"
+	ErrCodeHttpSrvShutdown                   = "HTTP-041"
+	ErrCodeHttpTooManyRequests               = "HTTP-042"
+	ErrCodeHttpRateLimitInvalid              = "HTTP-043"
+	ErrCodeHttpTrustedProxyInvalid           = "HTTP-044"
 )
 
 var (
@@ -160,6 +167,27 @@ var (
 		return kit.NewAppErrBuilder(ErrCodeHttpProxyFileJsonUnmarshal, "unmarshall failed").Wrap(cause).C(ctx).Err()
 	}
 	ErrAuthFailed = func(ctx context.Context) error {
-		return kit.NewAppErrBuilder(ErrCodeAuthFailed, "authorization failed").C(ctx).Err()
+		return kit.NewAppErrBuilder(ErrCodeAuthFailed, "authorization failed").Business().C(ctx).HttpSt(http.StatusUnauthorized).Err()
+	}
+	ErrHttpRequestInvalid = func(ctx context.Context, details kit.KV) error {
+		return kit.NewAppErrBuilder(ErrCodeHttpRequestInvalid, "request invalid").F(details).Business().C(ctx).HttpSt(http.StatusBadRequest).Err()
+	}
+	ErrAuthForbidden = func(ctx context.Context) error {
+		return kit.NewAppErrBuilder(ErrCodeAuthForbidden, "access forbidden").Business().C(ctx).HttpSt(http.StatusForbidden).Err()
+	}
+	ErrHttpSrvNotListening = func(ctx context.Context, addr string) error {
+		return kit.NewAppErrBuilder(ErrCodeHttpSrvNotListening, "server isn't listening").F(kit.KV{"addr": addr}).C(ctx).Err()
+	}
+	ErrHttpSrvShutdown = func(cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeHttpSrvShutdown, "graceful shutdown").Wrap(cause).Err()
+	}
+	ErrHttpTooManyRequests = func(ctx context.Context, limit string) error {
+		return kit.NewAppErrBuilder(ErrCodeHttpTooManyRequests, "too many requests").F(kit.KV{"limit": limit}).Business().C(ctx).HttpSt(http.StatusTooManyRequests).Err()
+	}
+	ErrHttpRateLimitInvalid = func(ctx context.Context, limit string) error {
+		return kit.NewAppErrBuilder(ErrCodeHttpRateLimitInvalid, "rate limit must have positive rps and non-negative burst").F(kit.KV{"limit": limit}).C(ctx).Err()
+	}
+	ErrHttpTrustedProxyInvalid = func(ctx context.Context, proxy string) error {
+		return kit.NewAppErrBuilder(ErrCodeHttpTrustedProxyInvalid, "trusted proxy must be IP or CIDR").F(kit.KV{"proxy": proxy}).C(ctx).Err()
 	}
 )
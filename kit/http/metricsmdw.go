@@ -0,0 +1,47 @@
+package http
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/mikhailbolshakov/decision/kit/metrics"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	httpRequestsTotal   = metrics.Default.Counter("http_requests_total", "Number of HTTP requests", "method", "route", "code")
+	httpRequestDuration = metrics.Default.Histogram("http_request_duration_seconds", "HTTP request latency", nil, "method", "route")
+)
+
+// metricsMiddleware counts requests and measures latency per route template
+func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		rw := &statusResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(rw, r)
+
+		httpRequestsTotal.Inc(r.Method, route, strconv.Itoa(rw.statusCode))
+		httpRequestDuration.Observe(time.Since(start).Seconds(), r.Method, route)
+	})
+}
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (rw *statusResponseWriter) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.statusCode = code
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
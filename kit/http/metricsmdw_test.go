@@ -0,0 +1,39 @@
+package http
+
+import (
+	"bytes"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/metrics"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type metricsMdwTestSuite struct {
+	kit.Suite
+}
+
+func (s *metricsMdwTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func TestMetricsMdwSuite(t *testing.T) {
+	suite.Run(t, new(metricsMdwTestSuite))
+}
+
+func (s *metricsMdwTestSuite) Test_RouteTemplate() {
+	srv := NewHttpServer(&Config{}, logf)
+	srv.RootRouter.HandleFunc("/metrics-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).Methods(http.MethodPost)
+	for _, id := range []string{"1", "2"} {
+		w := httptest.NewRecorder()
+		srv.Srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/metrics-test/"+id, nil))
+		s.Equal(http.StatusCreated, w.Code)
+	}
+	b := &bytes.Buffer{}
+	s.NoError(metrics.Default.Write(b))
+	s.Contains(b.String(), `http_requests_total{method="POST",route="/metrics-test/{id}",code="201"} 2`)
+	s.Contains(b.String(), `http_request_duration_seconds_count{method="POST",route="/metrics-test/{id}"} 2`)
+}
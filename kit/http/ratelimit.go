@@ -0,0 +1,200 @@
+package http
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/metrics"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HeaderRetryAfter = "Retry-After"
+
+	// staleBucketsSweepPeriod how often buckets refilled to capacity are removed from memory store
+	staleBucketsSweepPeriod = time.Minute
+)
+
+var rateLimitedTotal = metrics.Default.Counter("http_rate_limited_total", "Number of requests rejected by rate limits", "limit")
+
+// RateLimit token bucket params
+type RateLimit struct {
+	Rps   float64 // Rps tokens added to a bucket per second
+	Burst int     // Burst bucket capacity, requests allowed at once
+}
+
+// RateLimitStore keeps token buckets
+type RateLimitStore interface {
+	// Take takes a token from the bucket of the key
+	// if the bucket is empty, it returns false and time the next token is available in
+	Take(key string, limit *RateLimit) (bool, time.Duration)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// refill time the bucket gets full in, 0 if it's never refilled
+	refill time.Duration
+}
+
+type memoryRateLimitStore struct {
+	sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimitStore creates store keeping buckets in memory, limits are applied per instance
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{buckets: map[string]*bucket{}, lastSweep: kit.Now(), now: kit.Now}
+}
+
+func (s *memoryRateLimitStore) Take(key string, limit *RateLimit) (bool, time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	now := s.now()
+	capacity := math.Max(float64(limit.Burst), 1)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+	// refill tokens for the time passed
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*limit.Rps)
+	b.last = now
+	b.refill = 0
+	if limit.Rps > 0 {
+		b.refill = time.Duration(capacity / limit.Rps * float64(time.Second))
+	}
+
+	s.sweep(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if limit.Rps <= 0 {
+		return false, 0
+	}
+	return false, time.Duration((1 - b.tokens) / limit.Rps * float64(time.Second))
+}
+
+// sweep removes buckets refilled to capacity, they are the same as absent ones
+// refill time is kept per bucket, since buckets of different limits share the store
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < staleBucketsSweepPeriod {
+		return
+	}
+	s.lastSweep = now
+	for k, b := range s.buckets {
+		if b.refill > 0 && now.Sub(b.last) > b.refill {
+			delete(s.buckets, k)
+		}
+	}
+}
+
+// RateLimiter applies named rate limits to requests
+type RateLimiter struct {
+	BaseController
+	limits         map[string]*RateLimit
+	store          RateLimitStore
+	trustedProxies []string
+	trusted        []*net.IPNet
+}
+
+// NewRateLimiter creates a rate limiter, limits are keyed by name
+// client IP passed in headers is trusted only if the request comes from one of trusted proxies (IPs or CIDRs)
+func NewRateLimiter(limits map[string]*RateLimit, trustedProxies []string, store RateLimitStore, logger kit.CLoggerFunc) *RateLimiter {
+	return &RateLimiter{
+		BaseController: BaseController{Logger: logger},
+		limits:         limits,
+		store:          store,
+		trustedProxies: trustedProxies,
+	}
+}
+
+// Init validates limits and trusted proxies, until it's called no proxy is trusted
+func (l *RateLimiter) Init(ctx context.Context) error {
+	for name, limit := range l.limits {
+		if limit != nil && (limit.Rps <= 0 || math.IsInf(limit.Rps, 0) || math.IsNaN(limit.Rps) || limit.Burst < 0) {
+			return ErrHttpRateLimitInvalid(ctx, name)
+		}
+	}
+	trusted := make([]*net.IPNet, 0, len(l.trustedProxies))
+	for _, proxy := range l.trustedProxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return ErrHttpTrustedProxyInvalid(ctx, proxy)
+		}
+		trusted = append(trusted, ipNet)
+	}
+	l.trusted = trusted
+	return nil
+}
+
+// Middleware limits requests by the named limit per user if authenticated and per client IP otherwise
+// if the limit isn't configured, requests aren't limited
+func (l *RateLimiter) Middleware(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, ok := l.limits[name]
+		if !ok || limit == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := r.Context()
+		allowed, retryAfter := l.store.Take(name+":"+l.rateLimitKey(r), limit)
+		if !allowed {
+			rateLimitedTotal.Inc(name)
+			if retryAfter > 0 {
+				w.Header().Set(HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			}
+			l.RespondError(w, ErrHttpTooManyRequests(ctx, name))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// rateLimitKey identifies a requester by user id or client IP
+// client IP taken from headers is used only if the request comes from a trusted proxy, otherwise headers can be spoofed
+func (l *RateLimiter) rateLimitKey(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if rCtx, ok := kit.Request(r.Context()); ok && rCtx != nil {
+		if uid := rCtx.GetUserId(); uid != "" {
+			return "user:" + uid
+		}
+		if clientIp := rCtx.GetClientIp(); clientIp != "" && l.isTrusted(ip) {
+			return "ip:" + clientIp
+		}
+	}
+	return "ip:" + ip
+}
+
+func (l *RateLimiter) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range l.trusted {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type rateLimitTestSuite struct {
+	kit.Suite
+	now   time.Time
+	store *memoryRateLimitStore
+}
+
+func (s *rateLimitTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func (s *rateLimitTestSuite) SetupTest() {
+	s.now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.store = NewMemoryRateLimitStore().(*memoryRateLimitStore)
+	s.store.lastSweep = s.now
+	s.store.now = func() time.Time { return s.now }
+}
+
+func TestRateLimitSuite(t *testing.T) {
+	suite.Run(t, new(rateLimitTestSuite))
+}
+
+func (s *rateLimitTestSuite) Test_Store_TokenBucket() {
+	limit := &RateLimit{Rps: 2, Burst: 3}
+	for i := 0; i < 3; i++ {
+		ok, _ := s.store.Take("k", limit)
+		s.True(ok)
+	}
+	ok, retryAfter := s.store.Take("k", limit)
+	s.False(ok)
+	s.Equal(time.Millisecond*500, retryAfter)
+
+	// other keys have their own buckets
+	ok, _ = s.store.Take("other", limit)
+	s.True(ok)
+
+	// a token is refilled
+	s.now = s.now.Add(time.Millisecond * 500)
+	ok, _ = s.store.Take("k", limit)
+	s.True(ok)
+	ok, _ = s.store.Take("k", limit)
+	s.False(ok)
+
+	// bucket isn't refilled over capacity
+	s.now = s.now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		ok, _ = s.store.Take("k", limit)
+		s.True(ok)
+	}
+	ok, _ = s.store.Take("k", limit)
+	s.False(ok)
+}
+
+func (s *rateLimitTestSuite) Test_Store_Sweep() {
+	limit := &RateLimit{Rps: 1, Burst: 1}
+	s.store.Take("stale", limit)
+	s.now = s.now.Add(staleBucketsSweepPeriod + time.Second)
+	s.store.Take("fresh", limit)
+	s.Len(s.store.buckets, 1)
+	s.NotNil(s.store.buckets["fresh"])
+}
+
+func (s *rateLimitTestSuite) Test_Store_Sweep_PerLimit() {
+	// the slow bucket isn't full yet, so it must survive sweeping triggered by the fast limit
+	slow, fast := &RateLimit{Rps: 0.001, Burst: 1}, &RateLimit{Rps: 10, Burst: 1}
+	ok, _ := s.store.Take("slow", slow)
+	s.True(ok)
+	s.now = s.now.Add(staleBucketsSweepPeriod + time.Second)
+	s.store.Take("fast", fast)
+	s.NotNil(s.store.buckets["slow"])
+	ok, _ = s.store.Take("slow", slow)
+	s.False(ok)
+}
+
+func (s *rateLimitTestSuite) request(limiter *RateLimiter, ctx *kit.RequestContext) *httptest.ResponseRecorder {
+	h := limiter.Middleware("guest", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	rq := httptest.NewRequest(http.MethodPost, "/guests/decisions", nil)
+	if ctx != nil {
+		rq = rq.WithContext(ctx.ToContext(s.Ctx))
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, rq)
+	return w
+}
+
+func (s *rateLimitTestSuite) Test_Middleware() {
+	// httptest requests come from 192.0.2.1
+	limiter := NewRateLimiter(map[string]*RateLimit{"guest": {Rps: 0.5, Burst: 1}}, []string{"192.0.2.0/24"}, s.store, logf)
+	s.NoError(limiter.Init(s.Ctx))
+
+	ip1 := kit.NewRequestCtx().WithClientIp("10.0.0.1")
+	s.Equal(http.StatusOK, s.request(limiter, ip1).Code)
+	w := s.request(limiter, ip1)
+	s.Equal(http.StatusTooManyRequests, w.Code)
+	s.Equal("2", w.Header().Get(HeaderRetryAfter))
+	s.Contains(w.Body.String(), ErrCodeHttpTooManyRequests)
+
+	// another IP isn't limited
+	s.Equal(http.StatusOK, s.request(limiter, kit.NewRequestCtx().WithClientIp("10.0.0.2")).Code)
+
+	// authenticated users are limited per user regardless of IP
+	user := kit.NewRequestCtx().WithClientIp("10.0.0.1").WithUser("u1", "john")
+	s.Equal(http.StatusOK, s.request(limiter, user).Code)
+	s.Equal(http.StatusTooManyRequests, s.request(limiter, user).Code)
+	s.Equal(http.StatusOK, s.request(limiter, kit.NewRequestCtx().WithClientIp("10.0.0.1").WithUser("u2", "jane")).Code)
+
+	// falls back to remote address without request context
+	s.Equal(http.StatusOK, s.request(limiter, nil).Code)
+	s.Equal(http.StatusTooManyRequests, s.request(limiter, nil).Code)
+}
+
+func (s *rateLimitTestSuite) Test_Middleware_UntrustedClientIp() {
+	limiter := NewRateLimiter(map[string]*RateLimit{"guest": {Rps: 0.5, Burst: 1}}, []string{"10.10.10.10"}, s.store, logf)
+	s.NoError(limiter.Init(s.Ctx))
+
+	// the request doesn't come from a trusted proxy, so spoofed IPs share the bucket of the remote address
+	s.Equal(http.StatusOK, s.request(limiter, kit.NewRequestCtx().WithClientIp("10.0.0.1")).Code)
+	s.Equal(http.StatusTooManyRequests, s.request(limiter, kit.NewRequestCtx().WithClientIp("10.0.0.2")).Code)
+	s.Equal(http.StatusTooManyRequests, s.request(limiter, nil).Code)
+}
+
+func (s *rateLimitTestSuite) Test_Init_Invalid() {
+	limiter := NewRateLimiter(map[string]*RateLimit{"guest": {Rps: 0, Burst: 1}}, nil, s.store, logf)
+	s.AssertAppErr(limiter.Init(s.Ctx), ErrCodeHttpRateLimitInvalid)
+
+	limiter = NewRateLimiter(map[string]*RateLimit{"guest": {Rps: 1, Burst: -1}}, nil, s.store, logf)
+	s.AssertAppErr(limiter.Init(s.Ctx), ErrCodeHttpRateLimitInvalid)
+
+	limiter = NewRateLimiter(nil, []string{"proxy"}, s.store, logf)
+	s.AssertAppErr(limiter.Init(s.Ctx), ErrCodeHttpTrustedProxyInvalid)
+}
+
+func (s *rateLimitTestSuite) Test_Middleware_NotConfigured() {
+	limiter := NewRateLimiter(nil, nil, s.store, logf)
+	for i := 0; i < 10; i++ {
+		s.Equal(http.StatusOK, s.request(limiter, nil).Code)
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/mikhailbolshakov/decision/kit"
 	"github.com/mikhailbolshakov/decision/kit/goroutine"
+	"github.com/mikhailbolshakov/decision/kit/health"
 	"github.com/rs/cors"
+	"net"
 	"net/http"
+	"sync/atomic"
 	"time"
 )
 
@@ -23,10 +26,13 @@ type Config struct {
 	Port                 string
 	Cors                 *Cors
 	Trace                bool
-	WriteTimeoutSec      int `config:"write-timeout-sec"`
-	ReadTimeoutSec       int `config:"read-timeout-sec"`
-	ReadBufferSizeBytes  int `config:"read-buffer-size-bytes"`
-	WriteBufferSizeBytes int `config:"write-buffer-size-bytes"`
+	WriteTimeoutSec      int                   `config:"write-timeout-sec"`
+	ReadTimeoutSec       int                   `config:"read-timeout-sec"`
+	ReadBufferSizeBytes  int                   `config:"read-buffer-size-bytes"`
+	WriteBufferSizeBytes int                   `config:"write-buffer-size-bytes"`
+	ShutdownTimeoutSec   int                   `config:"shutdown-timeout-sec"`
+	RateLimits           map[string]*RateLimit `config:"rate-limits"`     // RateLimits limits by name routes refer to
+	TrustedProxies       []string              `config:"trusted-proxies"` // TrustedProxies IPs or CIDRs of proxies client IP headers are trusted from
 }
 
 // Server represents HTTP server
@@ -34,7 +40,11 @@ type Server struct {
 	Srv        *http.Server        // Srv - internal server
 	RootRouter *mux.Router         // RootRouter - root router
 	WsUpgrader *websocket.Upgrader // WsUpgrader - websocket upgrader
+	Limiter    *RateLimiter        // Limiter - rate limiter applied to routes
 	logger     kit.CLoggerFunc     // logger
+	listening  int32               // listening is set to 1 while server accepts connections
+	shutdown   time.Duration       // shutdown timeout in-flight requests are waited for on shutdown
+	cancel     context.CancelFunc  // cancel cancels contexts of in-flight requests
 }
 
 type RouteSetter interface {
@@ -65,12 +75,14 @@ func getOptions(cfg *Config) cors.Options {
 func NewHttpServer(cfg *Config, logger kit.CLoggerFunc) *Server {
 	r := mux.NewRouter()
 	corsHandler := cors.New(getOptions(cfg)).Handler(r)
+	baseCtx, cancel := context.WithCancel(context.Background())
 	s := &Server{
 		Srv: &http.Server{
 			Addr:         fmt.Sprintf(":%s", cfg.Port),
 			Handler:      corsHandler,
 			WriteTimeout: time.Duration(cfg.WriteTimeoutSec) * time.Second,
 			ReadTimeout:  time.Duration(cfg.ReadTimeoutSec) * time.Second,
+			BaseContext:  func(net.Listener) context.Context { return baseCtx },
 		},
 		WsUpgrader: &websocket.Upgrader{
 			ReadBufferSize:  cfg.ReadBufferSizeBytes,
@@ -79,8 +91,12 @@ func NewHttpServer(cfg *Config, logger kit.CLoggerFunc) *Server {
 				return true
 			},
 		},
-		logger: logger,
+		logger:   logger,
+		shutdown: time.Duration(cfg.ShutdownTimeoutSec) * time.Second,
+		cancel:   cancel,
 	}
+	s.Limiter = NewRateLimiter(cfg.RateLimits, cfg.TrustedProxies, NewMemoryRateLimitStore(), logger)
+	r.Use(s.metricsMiddleware)
 	if cfg.Trace {
 		r.Use(s.loggingMiddleware)
 	}
@@ -103,7 +119,13 @@ func (s *Server) Listen() {
 				l := s.logger().Pr("http").Cmp("server").Mth("listen").F(kit.KV{"url": s.Srv.Addr})
 				l.Inf("start listening")
 			start:
-				if err := s.Srv.ListenAndServe(); err != nil {
+				ln, err := net.Listen("tcp", s.Srv.Addr)
+				if err == nil {
+					atomic.StoreInt32(&s.listening, 1)
+					err = s.Srv.Serve(ln)
+					atomic.StoreInt32(&s.listening, 0)
+				}
+				if err != nil {
 					if err != http.ErrServerClosed {
 						l.E(ErrHttpSrvListen(err)).St().Err()
 						time.Sleep(time.Second * 5)
@@ -116,6 +138,38 @@ func (s *Server) Listen() {
 			})
 }
 
+// RegisterHealthChecks registers check the server accepts connections
+func (s *Server) RegisterHealthChecks(r health.Registry) {
+	r.Register("http", func(ctx context.Context) error {
+		if atomic.LoadInt32(&s.listening) == 0 {
+			return ErrHttpSrvNotListening(ctx, s.Srv.Addr)
+		}
+		return nil
+	}, health.Liveness())
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests within the shutdown timeout
+// when the timeout passes or ctx is done, contexts of in-flight requests are cancelled and connections are closed
+func (s *Server) Shutdown(ctx context.Context) error {
+	l := s.logger().Pr("http").Cmp("server").Mth("shutdown").C(ctx)
+	if s.shutdown > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.shutdown)
+		defer cancel()
+	}
+	if err := s.Srv.Shutdown(ctx); err != nil {
+		l.Warn("drain timeout, in-flight requests are cancelled")
+		s.cancel()
+		_ = s.Srv.Close()
+		return ErrHttpSrvShutdown(err)
+	}
+	s.cancel()
+	l.Dbg("drained")
+	return nil
+}
+
+// Close closes connections immediately, in-flight requests are cancelled
 func (s *Server) Close() {
+	s.cancel()
 	_ = s.Srv.Close()
 }
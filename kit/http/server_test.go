@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type serverTestSuite struct {
+	kit.Suite
+}
+
+func (s *serverTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(serverTestSuite))
+}
+
+// serve starts server on a random port with a handler, returns url of the handler
+func (s *serverTestSuite) serve(srv *Server, handler http.HandlerFunc) string {
+	srv.RootRouter.HandleFunc("/test", handler)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	s.NoError(err)
+	go func() { _ = srv.Srv.Serve(ln) }()
+	return "http://" + ln.Addr().String() + "/test"
+}
+
+func (s *serverTestSuite) Test_Shutdown_Drained() {
+	srv := NewHttpServer(&Config{ShutdownTimeoutSec: 5}, logf)
+	started := make(chan struct{})
+	url := s.serve(srv, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(time.Millisecond * 100)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	codes := make(chan int, 1)
+	go func() {
+		rs, err := http.Get(url)
+		if err != nil {
+			codes <- 0
+			return
+		}
+		_ = rs.Body.Close()
+		codes <- rs.StatusCode
+	}()
+	<-started
+
+	s.NoError(srv.Shutdown(s.Ctx))
+	// in-flight request is completed
+	s.Equal(http.StatusOK, <-codes)
+	// no new connections accepted
+	_, err := http.Get(url)
+	s.Error(err)
+}
+
+func (s *serverTestSuite) Test_Shutdown_Deadline() {
+	srv := NewHttpServer(&Config{}, logf)
+	started := make(chan struct{})
+	cancelled := make(chan bool, 1)
+	url := s.serve(srv, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+			cancelled <- true
+		case <-time.After(time.Second * 5):
+			cancelled <- false
+		}
+	})
+
+	go func() {
+		if rs, err := http.Get(url); err == nil {
+			_ = rs.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(s.Ctx, time.Millisecond*100)
+	defer cancel()
+	s.AssertAppErr(srv.Shutdown(ctx), ErrCodeHttpSrvShutdown)
+	// in-flight request is cancelled through context
+	s.True(<-cancelled)
+}
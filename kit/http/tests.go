@@ -85,6 +85,12 @@ func (t *TestRequest) RqBody(rq interface{}) *TestRequest {
 	return t
 }
 
+// RqRawBody allows define request body as is, e.g. multipart content
+func (t *TestRequest) RqRawBody(body io.Reader) *TestRequest {
+	t.rqBodyReader = body
+	return t
+}
+
 // RqBody allows pass a variable of expected response type, so that response body is unmarshalled to this variable
 func (t *TestRequest) RsBody(rs interface{}) *TestRequest {
 	if rs != nil {
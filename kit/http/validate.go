@@ -0,0 +1,227 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	ens "github.com/go-playground/validator/translations/en"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/i18n"
+	"go.uber.org/multierr"
+	"golang.org/x/text/language"
+	"gopkg.in/go-playground/validator.v9"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Validatable is implemented by request models requiring checks which can't be expressed by tags
+// Validate can return a FieldError or several of them combined by multierr, field path is relative to the model
+type Validatable interface {
+	Validate() error
+}
+
+// FieldError is a validation failure of a particular field
+type FieldError struct {
+	Field   string // Field json path of the field
+	Key     string // Key translation key of the message, it's looked up among validation messages of i18n catalogs
+	Message string // Message failure description used if there is no translation
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// NewFieldError creates a field validation failure
+func NewFieldError(field, message string) error {
+	return &FieldError{Field: field, Message: message}
+}
+
+// NewLocalizedFieldError creates a field validation failure with a message translated by key
+func NewLocalizedFieldError(field, key, message string) error {
+	return &FieldError{Field: field, Key: key, Message: message}
+}
+
+// ValidationMessage returns a validation message localized to the request language
+// the key is looked up among validation messages of i18n catalogs, the message is returned if there is no translation
+func ValidationMessage(ctx context.Context, key, message string, args kit.KV) string {
+	rCtx, _ := kit.Request(ctx)
+	return validationMessage(rCtx.GetLang(), key, message, args)
+}
+
+func validationMessage(lang language.Tag, key, message string, args kit.KV) string {
+	if key == "" {
+		return message
+	}
+	if msg, ok := i18n.Default.Message(lang, "validation."+key, args); ok {
+		return msg
+	}
+	return message
+}
+
+// requestValidator validates request models by `validate` tags and Validatable implementations
+type requestValidator struct {
+	validate *validator.Validate
+	trans    ut.Translator
+}
+
+var (
+	reqValidator     *requestValidator
+	reqValidatorOnce sync.Once
+)
+
+func getRequestValidator() *requestValidator {
+	reqValidatorOnce.Do(func() {
+		v := &requestValidator{validate: validator.New()}
+		// report json names of fields
+		v.validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+		uni := ut.New(en.New(), en.New())
+		v.trans, _ = uni.GetTranslator("en")
+		_ = ens.RegisterDefaultTranslations(v.validate, v.trans)
+		reqValidator = v
+	})
+	return reqValidator
+}
+
+// ValidateRequest validates a request model
+// all failures are returned as a single error with failure messages by field path in details
+func ValidateRequest(ctx context.Context, body interface{}) error {
+	v := getRequestValidator()
+	rCtx, _ := kit.Request(ctx)
+	lang := rCtx.GetLang()
+	details := kit.KV{}
+	v.tags(reflect.ValueOf(body), "", lang, details)
+	v.funcs(reflect.ValueOf(body), "", lang, details)
+	if len(details) > 0 {
+		return ErrHttpRequestInvalid(ctx, details)
+	}
+	return nil
+}
+
+// tags validates tags of structs, nested structs are validated by validator, slices of structs must have `dive` tag
+func (v *requestValidator) tags(val reflect.Value, path string, lang language.Tag, details kit.KV) {
+	val = indirect(val)
+	if !val.IsValid() {
+		return
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		err := v.validate.Struct(val.Interface())
+		if err == nil {
+			return
+		}
+		fieldErrs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			details[pathOf(path, "")] = err.Error()
+			return
+		}
+		for _, fe := range fieldErrs {
+			// namespace starts with the struct name
+			ns := fe.Namespace()
+			if i := strings.Index(ns, "."); i >= 0 {
+				ns = ns[i+1:]
+			}
+			details[pathOf(path, ns)] = v.message(lang, fe)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			v.tags(val.Index(i), fmt.Sprintf("%s[%d]", path, i), lang, details)
+		}
+	}
+}
+
+// funcs calls Validatable implementations of the value and all nested values
+func (v *requestValidator) funcs(val reflect.Value, path string, lang language.Tag, details kit.KV) {
+	val = indirect(val)
+	if !val.IsValid() {
+		return
+	}
+	// the address is taken to support both value and pointer receivers
+	target := val
+	if val.CanAddr() {
+		target = val.Addr()
+	}
+	if target.CanInterface() {
+		if validatable, ok := target.Interface().(Validatable); ok {
+			for _, err := range multierr.Errors(validatable.Validate()) {
+				if fe, ok := err.(*FieldError); ok {
+					details[pathOf(path, fe.Field)] = validationMessage(lang, fe.Key, fe.Message, nil)
+				} else {
+					details[pathOf(path, "")] = err.Error()
+				}
+			}
+		}
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		t := val.Type()
+		for i := 0; i < val.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = t.Field(i).Name
+			}
+			v.funcs(val.Field(i), pathOf(path, name), lang, details)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			v.funcs(val.Index(i), fmt.Sprintf("%s[%d]", path, i), lang, details)
+		}
+	case reflect.Map:
+		for _, k := range val.MapKeys() {
+			v.funcs(val.MapIndex(k), fmt.Sprintf("%s[%v]", path, k.Interface()), lang, details)
+		}
+	}
+}
+
+// message returns a message of the tag failure localized to the language
+// tag messages specific to the kind of the field (e.g. max.string) take precedence
+func (v *requestValidator) message(lang language.Tag, fe validator.FieldError) string {
+	args := kit.KV{"param": fe.Param()}
+	kind := fe.Kind().String()
+	if msg := validationMessage(lang, fe.Tag()+"."+kind, "", args); msg != "" {
+		return msg
+	}
+	return validationMessage(lang, fe.Tag(), fe.Translate(v.trans), args)
+}
+
+func indirect(val reflect.Value) reflect.Value {
+	for val.IsValid() && (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) {
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+		val = val.Elem()
+	}
+	return val
+}
+
+// pathOf joins field paths, empty path means the whole request
+func pathOf(path, field string) string {
+	switch {
+	case path == "" && field == "":
+		return "request"
+	case path == "":
+		return field
+	case field == "":
+		return path
+	case strings.HasPrefix(field, "["):
+		return path + field
+	default:
+		return path + "." + field
+	}
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/i18n"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/multierr"
+	"golang.org/x/text/language"
+	"net/http"
+	"testing"
+)
+
+type validateItem struct {
+	Id    string  `json:"id"`
+	Name  string  `json:"name" validate:"required"`
+	Value float64 `json:"value" validate:"gte=0,lte=1"`
+}
+
+type validateRequest struct {
+	Name  string          `json:"name" validate:"required,max=5"`
+	Items []*validateItem `json:"items" validate:"dive,required"`
+}
+
+// Validate checks item ids are unique
+func (r *validateRequest) Validate() error {
+	var errs error
+	ids := map[string]bool{}
+	for _, it := range r.Items {
+		if it != nil && ids[it.Id] {
+			errs = multierr.Append(errs, NewLocalizedFieldError("items", "duplicate-id", "duplicate id "+it.Id))
+		}
+		if it != nil {
+			ids[it.Id] = true
+		}
+	}
+	return errs
+}
+
+type validateTestSuite struct {
+	kit.Suite
+}
+
+func (s *validateTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func TestValidateSuite(t *testing.T) {
+	suite.Run(t, new(validateTestSuite))
+}
+
+func (s *validateTestSuite) details(err error) kit.KV {
+	s.AssertAppErr(err, ErrCodeHttpRequestInvalid)
+	appErr, _ := kit.IsAppErr(err)
+	s.Equal(http.StatusBadRequest, int(*appErr.HttpStatus()))
+	details := appErr.Fields()
+	// context is added to fields of all errors
+	delete(details, "ctx")
+	return details
+}
+
+func (s *validateTestSuite) Test_Ok() {
+	s.NoError(ValidateRequest(s.Ctx, &validateRequest{Name: "name", Items: []*validateItem{{Id: "1", Name: "item", Value: 1}}}))
+}
+
+func (s *validateTestSuite) Test_Tags() {
+	details := s.details(ValidateRequest(s.Ctx, &validateRequest{
+		Name:  "long name",
+		Items: []*validateItem{{Id: "1", Name: "item"}, {Id: "2", Value: -5}},
+	}))
+	s.Len(details, 3)
+	s.NotEmpty(details["name"])
+	s.NotEmpty(details["items[1].name"])
+	s.NotEmpty(details["items[1].value"])
+}
+
+func (s *validateTestSuite) Test_Validatable() {
+	details := s.details(ValidateRequest(s.Ctx, &validateRequest{
+		Name:  "name",
+		Items: []*validateItem{{Id: "1", Name: "item"}, {Id: "1", Name: "item"}},
+	}))
+	s.Equal(kit.KV{"items": "duplicate id 1"}, details)
+}
+
+func (s *validateTestSuite) Test_Slice() {
+	details := s.details(ValidateRequest(s.Ctx, &[]*validateRequest{{Name: "name"}, {Items: []*validateItem{{Name: "item"}, {Name: "item"}}}}))
+	s.Len(details, 2)
+	s.NotEmpty(details["[1].name"])
+	s.Equal("duplicate id ", details["[1].items"])
+}
+
+func (s *validateTestSuite) Test_Localized() {
+	i18n.Default.Add(language.Russian, map[string]string{"validation.duplicate-id": "повторяющийся идентификатор"})
+	ctx := kit.NewRequestCtx().WithLang(language.Russian).ToContext(s.Ctx)
+	details := s.details(ValidateRequest(ctx, &validateRequest{
+		Name:  "long name",
+		Items: []*validateItem{{Id: "1"}, {Id: "1", Name: "item", Value: 2}},
+	}))
+	s.Equal(kit.KV{
+		"name":           "длина должна быть не больше 5 символов",
+		"items[0].name":  "обязательное поле",
+		"items[1].value": "должно быть не больше 1",
+		"items":          "повторяющийся идентификатор",
+	}, details)
+}
+
+func (s *validateTestSuite) Test_NotStruct() {
+	s.NoError(ValidateRequest(s.Ctx, &map[string]string{"a": "b"}))
+	s.NoError(ValidateRequest(s.Ctx, nil))
+}
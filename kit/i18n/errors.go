@@ -0,0 +1,15 @@
+package i18n
+
+import (
+	"github.com/mikhailbolshakov/decision/kit"
+)
+
+const (
+	ErrCodeI18nCatalogLoad = "I18N-001"
+)
+
+var (
+	ErrI18nCatalogLoad = func(cause error, file string) error {
+		return kit.NewAppErrBuilder(ErrCodeI18nCatalogLoad, "load catalog").Wrap(cause).F(kit.KV{"file": file}).Err()
+	}
+)
@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"fmt"
+	"github.com/mikhailbolshakov/decision/kit"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Default is a catalog messages of all the packages are registered in
+var Default = NewCatalog(language.English)
+
+// Catalog keeps localized messages by language
+// a message is found by key (AppError code for errors), placeholders {name} are substituted with args
+type Catalog struct {
+	mu       sync.RWMutex
+	fallback language.Tag                 // fallback language used when a message isn't translated to the requested one
+	langs    []language.Tag               // langs supported languages, fallback goes first
+	messages map[string]map[string]string // messages by base language and key
+	matcher  language.Matcher             // matcher negotiates supported languages
+}
+
+func NewCatalog(fallback language.Tag) *Catalog {
+	c := &Catalog{
+		fallback: fallback,
+		messages: map[string]map[string]string{},
+	}
+	c.addLang(fallback)
+	return c
+}
+
+// Add adds messages of the language, existing messages with the same keys are overridden
+func (c *Catalog) Add(lang language.Tag, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addLang(lang)
+	msgs := c.messages[baseOf(lang)]
+	for k, v := range messages {
+		msgs[k] = v
+	}
+}
+
+func (c *Catalog) addLang(lang language.Tag) {
+	base := baseOf(lang)
+	if _, ok := c.messages[base]; ok {
+		return
+	}
+	c.messages[base] = map[string]string{}
+	c.langs = append(c.langs, language.Make(base))
+	c.matcher = language.NewMatcher(c.langs)
+}
+
+// Load loads YAML catalogs from the directory, a file name is a language tag (e.g. ru.yaml)
+// nested keys are joined with dots
+func (c *Catalog) Load(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.yaml"))
+	if err != nil {
+		return ErrI18nCatalogLoad(err, dir)
+	}
+	for _, file := range files {
+		lang, err := language.Parse(strings.TrimSuffix(path.Base(file), ".yaml"))
+		if err != nil {
+			return ErrI18nCatalogLoad(err, file)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return ErrI18nCatalogLoad(err, file)
+		}
+		var tree map[string]interface{}
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return ErrI18nCatalogLoad(err, file)
+		}
+		messages := map[string]string{}
+		flatten(tree, "", messages)
+		c.Add(lang, messages)
+	}
+	return nil
+}
+
+// MustLoad loads catalogs and panics on error, it's intended to load embedded catalogs on init
+func (c *Catalog) MustLoad(fsys fs.FS, dir string) {
+	if err := c.Load(fsys, dir); err != nil {
+		panic(err)
+	}
+}
+
+// Languages returns supported languages
+func (c *Catalog) Languages() []language.Tag {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]language.Tag{}, c.langs...)
+}
+
+// Match negotiates a supported language by Accept-Language header value
+// fallback language is returned if nothing matches
+func (c *Catalog) Match(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return c.fallback
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, index, confidence := c.matcher.Match(tags...)
+	if confidence == language.No {
+		return c.fallback
+	}
+	return c.langs[index]
+}
+
+// Message returns a localized message by key
+// if the message isn't translated to the language, fallback language is tried
+func (c *Catalog) Message(lang language.Tag, key string, args kit.KV) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	msg, ok := c.messages[baseOf(lang)][key]
+	if !ok {
+		msg, ok = c.messages[baseOf(c.fallback)][key]
+	}
+	if !ok {
+		return "", false
+	}
+	return substitute(msg, args), true
+}
+
+// baseOf returns base language of the tag, undefined tag is considered as no language
+func baseOf(lang language.Tag) string {
+	if lang == language.Und {
+		return ""
+	}
+	base, _ := lang.Base()
+	return base.String()
+}
+
+func substitute(msg string, args kit.KV) string {
+	if len(args) == 0 || !strings.Contains(msg, "{") {
+		return msg
+	}
+	pairs := make([]string, 0, len(args)*2)
+	for k, v := range args {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+func flatten(tree map[string]interface{}, prefix string, messages map[string]string) {
+	for k, v := range tree {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]interface{}:
+			flatten(val, key, messages)
+		case nil:
+		default:
+			messages[key] = fmt.Sprint(val)
+		}
+	}
+}
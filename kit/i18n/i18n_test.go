@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/text/language"
+	"testing"
+	"testing/fstest"
+)
+
+var logger = kit.InitLogger(&kit.LogConfig{Level: kit.InfoLevel})
+var logf = func() kit.CLogger {
+	return kit.L(logger)
+}
+
+type i18nTestSuite struct {
+	kit.Suite
+	catalog *Catalog
+}
+
+func (s *i18nTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func (s *i18nTestSuite) SetupTest() {
+	s.catalog = NewCatalog(language.English)
+	s.NoError(s.catalog.Load(fstest.MapFS{
+		"locales/en.yaml": {Data: []byte("TST-001: not found\nTST-002: limit {limit} exceeded\nvalidation:\n  required: required\n")},
+		"locales/ru.yaml": {Data: []byte("TST-001: не найдено\nvalidation:\n  required: обязательное поле\n")},
+	}, "locales"))
+}
+
+func TestI18nSuite(t *testing.T) {
+	suite.Run(t, new(i18nTestSuite))
+}
+
+func (s *i18nTestSuite) Test_Match() {
+	s.Equal(language.Russian, s.catalog.Match("ru-RU,ru;q=0.9,en;q=0.8"))
+	s.Equal(language.Russian, s.catalog.Match("fr, ru;q=0.5"))
+	s.Equal(language.English, s.catalog.Match("en-US"))
+	s.Equal(language.English, s.catalog.Match("de"))
+	s.Equal(language.English, s.catalog.Match(""))
+	s.Equal(language.English, s.catalog.Match("invalid;;"))
+	s.Equal([]language.Tag{language.English, language.Russian}, s.catalog.Languages())
+}
+
+func (s *i18nTestSuite) Test_Message() {
+	msg, ok := s.catalog.Message(language.Russian, "TST-001", nil)
+	s.True(ok)
+	s.Equal("не найдено", msg)
+
+	// regional variant uses base language
+	msg, _ = s.catalog.Message(language.MustParse("ru-RU"), "validation.required", nil)
+	s.Equal("обязательное поле", msg)
+
+	// fallback language is used if not translated
+	msg, ok = s.catalog.Message(language.Russian, "TST-002", kit.KV{"limit": 10})
+	s.True(ok)
+	s.Equal("limit 10 exceeded", msg)
+
+	// undefined language
+	msg, _ = s.catalog.Message(language.Und, "TST-001", nil)
+	s.Equal("not found", msg)
+
+	_, ok = s.catalog.Message(language.Russian, "TST-003", nil)
+	s.False(ok)
+}
+
+func (s *i18nTestSuite) Test_Add_Overrides() {
+	s.catalog.Add(language.Russian, map[string]string{"TST-001": "отсутствует"})
+	msg, _ := s.catalog.Message(language.Russian, "TST-001", nil)
+	s.Equal("отсутствует", msg)
+}
+
+func (s *i18nTestSuite) Test_Load_Invalid() {
+	s.AssertAppErr(NewCatalog(language.English).Load(fstest.MapFS{"locales/invalid-lang-tag.yaml": {Data: []byte("a: b")}}, "locales"), ErrCodeI18nCatalogLoad)
+	s.AssertAppErr(NewCatalog(language.English).Load(fstest.MapFS{"locales/ru.yaml": {Data: []byte("a: [")}}, "locales"), ErrCodeI18nCatalogLoad)
+}
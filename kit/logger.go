@@ -212,6 +212,12 @@ func (cl *clogger) C(ctx context.Context) CLogger {
 		if sid := r.GetSessionId(); sid != "" {
 			ff["ctx.sid"] = sid
 		}
+		if tid := r.GetTraceId(); tid != "" {
+			ff["ctx.tid"] = tid
+		}
+		if spid := r.GetSpanId(); spid != "" {
+			ff["ctx.spid"] = spid
+		}
 		cl.F(ff)
 	}
 	return cl
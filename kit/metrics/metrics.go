@@ -0,0 +1,273 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+
+	// ContentType Prometheus text exposition format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	// DefBuckets default histogram buckets for durations in seconds
+	DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	// Default registry all the service metrics are registered in
+	Default = NewRegistry()
+)
+
+// Registry keeps metric families and writes them in Prometheus text format
+type Registry struct {
+	sync.RWMutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: map[string]*family{}}
+}
+
+// family metrics of the same name and type with different label values
+type family struct {
+	sync.Mutex
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	fn          func() float64
+	counts      []uint64 // counts histogram counts per bucket (not cumulative)
+	count       uint64
+	sum         float64
+}
+
+// CounterVec monotonically increasing values partitioned by labels
+type CounterVec struct {
+	f *family
+}
+
+// HistogramVec observations counted in buckets partitioned by labels
+type HistogramVec struct {
+	f *family
+}
+
+// family returns a registered family or registers a new one
+// registering the same name with another type or labels is a programming error, so it panics
+func (r *Registry) family(name, help, typ string, buckets []float64, labels ...string) *family {
+	r.Lock()
+	defer r.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.typ != typ || strings.Join(f.labels, ",") != strings.Join(labels, ",") {
+			panic(fmt.Sprintf("metric %s already registered with another type or labels", name))
+		}
+		return f
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  map[string]*series{},
+	}
+	r.families[name] = f
+	return f
+}
+
+// Counter registers a counter with labels, if already registered the same counter is returned
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{f: r.family(name, help, TypeCounter, nil, labels...)}
+}
+
+// Histogram registers a histogram with labels, if already registered the same histogram is returned
+// buckets are upper bounds in ascending order, DefBuckets are used if empty
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	return &HistogramVec{f: r.family(name, help, TypeHistogram, buckets, labels...)}
+}
+
+// GaugeFunc registers a gauge which value is taken from fn when metrics are written
+// labels are pairs of label names and values; registering the same labels again replaces fn
+func (r *Registry) GaugeFunc(name, help string, fn func() float64, labels ...string) {
+	r.valueFunc(name, help, TypeGauge, fn, labels...)
+}
+
+// CounterFunc registers a counter which value is taken from fn when metrics are written
+// labels are pairs of label names and values; registering the same labels again replaces fn
+func (r *Registry) CounterFunc(name, help string, fn func() float64, labels ...string) {
+	r.valueFunc(name, help, TypeCounter, fn, labels...)
+}
+
+func (r *Registry) valueFunc(name, help, typ string, fn func() float64, labels ...string) {
+	var names, values []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		names, values = append(names, labels[i]), append(values, labels[i+1])
+	}
+	f := r.family(name, help, typ, nil, names...)
+	f.Lock()
+	defer f.Unlock()
+	f.get(values).fn = fn
+}
+
+// get returns series by label values, must be called under lock
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, %d passed", f.name, len(f.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string{}, labelValues...)}
+		if f.typ == TypeHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Inc increments the counter by 1
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter, negative values are ignored as counters can't decrease
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.f.Lock()
+	defer c.f.Unlock()
+	c.f.get(labelValues).value += v
+}
+
+// Observe adds an observation to the histogram
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.f.Lock()
+	defer h.f.Unlock()
+	s := h.f.get(labelValues)
+	for i, b := range h.f.buckets {
+		if v <= b {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+// Write writes all the metrics in Prometheus text exposition format
+func (r *Registry) Write(w io.Writer) error {
+	r.RLock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.RUnlock()
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler returns http handler exposing metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, rq *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.Write(w)
+	})
+}
+
+func (f *family) write(w *bufio.Writer) {
+	f.Lock()
+	defer f.Unlock()
+	if len(f.series) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+	for _, k := range keys {
+		s := f.series[k]
+		if f.typ != TypeHistogram {
+			v := s.value
+			if s.fn != nil {
+				v = s.fn()
+			}
+			fmt.Fprintf(w, "%s%s %s\n", f.name, f.labelString(s.labelValues, "", ""), formatFloat(v))
+			continue
+		}
+		var cumulative uint64
+		for i, b := range f.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelString(s.labelValues, "le", formatFloat(b)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelString(s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, f.labelString(s.labelValues, "", ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, f.labelString(s.labelValues, "", ""), s.count)
+	}
+}
+
+// labelString formats labels with an optional extra label
+func (f *family) labelString(values []string, extraName, extraValue string) string {
+	var pairs []string
+	for i, name := range f.labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabel(values[i])))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, escapeLabel(extraValue)))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
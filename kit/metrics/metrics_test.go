@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"bytes"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var logger = kit.InitLogger(&kit.LogConfig{Level: kit.InfoLevel})
+var logf = func() kit.CLogger {
+	return kit.L(logger)
+}
+
+type metricsTestSuite struct {
+	kit.Suite
+}
+
+func (s *metricsTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func TestMetricsSuite(t *testing.T) {
+	suite.Run(t, new(metricsTestSuite))
+}
+
+func (s *metricsTestSuite) write(r *Registry) string {
+	b := &bytes.Buffer{}
+	s.NoError(r.Write(b))
+	return b.String()
+}
+
+func (s *metricsTestSuite) Test_Counter() {
+	r := NewRegistry()
+	c := r.Counter("requests_total", "Requests count", "method", "code")
+	c.Inc("GET", "200")
+	c.Add(2, "GET", "200")
+	c.Add(-1, "GET", "200")
+	c.Inc("POST", `5"0\0`)
+	// the same counter is returned on registering again
+	r.Counter("requests_total", "Requests count", "method", "code").Inc("GET", "200")
+	s.Equal(`# HELP requests_total Requests count
+# TYPE requests_total counter
+requests_total{method="GET",code="200"} 4
+requests_total{method="POST",code="5\"0\\0"} 1
+`, s.write(r))
+}
+
+func (s *metricsTestSuite) Test_Histogram() {
+	r := NewRegistry()
+	h := r.Histogram("duration_seconds", "Duration", []float64{0.1, 1}, "route")
+	h.Observe(0.05, "/a")
+	h.Observe(0.5, "/a")
+	h.Observe(5, "/a")
+	s.Equal(`# HELP duration_seconds Duration
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/a",le="0.1"} 1
+duration_seconds_bucket{route="/a",le="1"} 2
+duration_seconds_bucket{route="/a",le="+Inf"} 3
+duration_seconds_sum{route="/a"} 5.55
+duration_seconds_count{route="/a"} 3
+`, s.write(r))
+}
+
+func (s *metricsTestSuite) Test_Funcs() {
+	r := NewRegistry()
+	v := 1.0
+	r.GaugeFunc("open", "Open connections", func() float64 { return v }, "db", "main")
+	r.CounterFunc("waits_total", "Waits", func() float64 { return 3 })
+	v = 2
+	s.Equal(`# HELP open Open connections
+# TYPE open gauge
+open{db="main"} 2
+# HELP waits_total Waits
+# TYPE waits_total counter
+waits_total 3
+`, s.write(r))
+}
+
+func (s *metricsTestSuite) Test_EmptyFamily() {
+	r := NewRegistry()
+	r.Counter("empty_total", "Empty")
+	s.Empty(s.write(r))
+}
+
+func (s *metricsTestSuite) Test_RegisteredWithAnotherType() {
+	r := NewRegistry()
+	r.Counter("metric", "Metric", "a")
+	s.Panics(func() { r.Histogram("metric", "Metric", nil, "a") })
+	s.Panics(func() { r.Counter("metric", "Metric", "b") })
+}
+
+func (s *metricsTestSuite) Test_LabelValuesMismatch() {
+	r := NewRegistry()
+	s.Panics(func() { r.Counter("metric", "Metric", "a").Inc() })
+}
+
+func (s *metricsTestSuite) Test_Handler() {
+	r := NewRegistry()
+	r.Counter("requests_total", "Requests").Inc()
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal(ContentType, w.Header().Get("Content-Type"))
+	s.Contains(w.Body.String(), "requests_total 1\n")
+}
@@ -1,6 +1,9 @@
 package kit
 
-import "context"
+import (
+	"context"
+	"go.uber.org/multierr"
+)
 
 // Service declares an interface each service must implement
 type Service interface {
@@ -11,6 +14,7 @@ type Service interface {
 	// Start executes all background processes
 	Start(ctx context.Context) error
 	// Close closes the service
+	// dependencies must be closed in order: incoming requests first, then domain workers, then storages
 	Close(ctx context.Context)
 }
 
@@ -28,3 +32,39 @@ type AdapterListener interface {
 	// ListenAsync runs async listening
 	ListenAsync(ctx context.Context) error
 }
+
+// CloseFunc releases a dependency, it should give up waiting when ctx is done
+type CloseFunc func(ctx context.Context) error
+
+// Closer closes dependencies one by one in the order they are added
+type Closer struct {
+	names  []string
+	fns    []CloseFunc
+	logger CLoggerFunc
+}
+
+func NewCloser(logger CLoggerFunc) *Closer {
+	return &Closer{logger: logger}
+}
+
+// Add adds a dependency to close
+func (c *Closer) Add(name string, fn CloseFunc) *Closer {
+	c.names = append(c.names, name)
+	c.fns = append(c.fns, fn)
+	return c
+}
+
+// Close closes all the dependencies, failure of one doesn't prevent closing the next ones
+func (c *Closer) Close(ctx context.Context) error {
+	var errs error
+	for i, fn := range c.fns {
+		l := c.logger().Cmp("closer").Mth("close").C(ctx).F(KV{"dependency": c.names[i]})
+		if err := fn(ctx); err != nil {
+			l.E(err).Err()
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		l.Dbg("closed")
+	}
+	return errs
+}
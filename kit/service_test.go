@@ -0,0 +1,26 @@
+package kit
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Closer_Order(t *testing.T) {
+	logger := InitLogger(&LogConfig{Level: TraceLevel})
+	var closed []string
+	closeFn := func(name string, err error) CloseFunc {
+		return func(context.Context) error {
+			closed = append(closed, name)
+			return err
+		}
+	}
+	err := NewCloser(func() CLogger { return L(logger) }).
+		Add("http", closeFn("http", nil)).
+		Add("workers", closeFn("workers", errors.New("timeout"))).
+		Add("db", closeFn("db", nil)).
+		Close(context.Background())
+	assert.EqualError(t, err, "timeout")
+	assert.Equal(t, []string{"http", "workers", "db"}, closed)
+}
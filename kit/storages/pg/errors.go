@@ -3,13 +3,17 @@ package pg
 import "github.com/mikhailbolshakov/decision/kit"
 
 const (
-	ErrCodeGooseMigrationUp     = "DB-001"
-	ErrCodeGooseMigrationGetVer = "DB-002"
-	ErrCodePostgresOpen         = "DB-003"
-	ErrCodeGooseFolderNotFound  = "DB-004"
-	ErrCodeGooseFolderOpen      = "DB-005"
-	ErrCodeGooseMigrationLock   = "DB-006"
-	ErrCodeGooseMigrationUnLock = "DB-007"
+	ErrCodeGooseMigrationUp      = "DB-001"
+	ErrCodeGooseMigrationGetVer  = "DB-002"
+	ErrCodePostgresOpen          = "DB-003"
+	ErrCodeGooseFolderNotFound   = "DB-004"
+	ErrCodeGooseFolderOpen       = "DB-005"
+	ErrCodeGooseMigrationLock    = "DB-006"
+	ErrCodeGooseMigrationUnLock  = "DB-007"
+	ErrCodePostgresTracing       = "DB-008"
+	ErrCodePostgresPing          = "DB-009"
+	ErrCodeGooseMigrationCollect = "DB-010"
+	ErrCodeGooseMigrationPending = "DB-011"
 )
 
 var (
@@ -34,4 +38,16 @@ var (
 	ErrGooseMigrationUnLock = func(cause error) error {
 		return kit.NewAppErrBuilder(ErrCodeGooseMigrationUnLock, "unlocking after migration").Wrap(cause).Err()
 	}
+	ErrPostgresTracing = func(cause error) error {
+		return kit.NewAppErrBuilder(ErrCodePostgresTracing, "registering tracing callbacks").Wrap(cause).Err()
+	}
+	ErrPostgresPing = func(cause error) error {
+		return kit.NewAppErrBuilder(ErrCodePostgresPing, "database unreachable").Wrap(cause).Err()
+	}
+	ErrGooseMigrationCollect = func(cause error) error {
+		return kit.NewAppErrBuilder(ErrCodeGooseMigrationCollect, "collecting migrations").Wrap(cause).Err()
+	}
+	ErrGooseMigrationPending = func(current, last int64) error {
+		return kit.NewAppErrBuilder(ErrCodeGooseMigrationPending, "migrations not applied").F(kit.KV{"current": current, "last": last}).Err()
+	}
 )
@@ -6,6 +6,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/mikhailbolshakov/decision/kit"
 	"github.com/pressly/goose"
+	"math"
 	"os"
 	"path/filepath"
 )
@@ -13,7 +14,10 @@ import (
 const pgMigrationAdvisoryLockId = 789654123
 
 type Migration interface {
+	// Up applies all not applied migrations
 	Up() error
+	// Check checks all the migrations are applied
+	Check() error
 }
 
 type migImpl struct {
@@ -65,3 +69,19 @@ func (m *migImpl) Up() error {
 	l.InfF("ok, version: %d", version)
 	return nil
 }
+
+func (m *migImpl) Check() error {
+	version, err := goose.GetDBVersion(m.db)
+	if err != nil {
+		return ErrGooseMigrationGetVer(err)
+	}
+	migrations, err := goose.CollectMigrations(m.source, version, math.MaxInt64)
+	if err != nil {
+		return ErrGooseMigrationCollect(err)
+	}
+	if len(migrations) > 0 {
+		last, _ := migrations.Last()
+		return ErrGooseMigrationPending(version, last.Version)
+	}
+	return nil
+}
@@ -1,8 +1,11 @@
 package pg
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/metrics"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
@@ -67,11 +70,47 @@ func Open(config *DbConfig, logger kit.CLoggerFunc) (*Storage, error) {
 
 	logger().Pr("db").Cmp(config.User).Inf("ok")
 
+	if err := registerTracing(db); err != nil {
+		return nil, ErrPostgresTracing(err)
+	}
+
 	s.Instance = db
 
+	if sqlDb, err := db.DB(); err == nil {
+		registerPoolMetrics(sqlDb, config.DBName)
+	}
+
 	return s, nil
 }
 
+// registerPoolMetrics exposes connection pool stats, they are read when metrics are written
+func registerPoolMetrics(db *sql.DB, dbName string) {
+	metrics.Default.GaugeFunc("db_pool_max_open_connections", "Maximum number of open connections to the database",
+		func() float64 { return float64(db.Stats().MaxOpenConnections) }, "db", dbName)
+	metrics.Default.GaugeFunc("db_pool_open_connections", "Number of established connections both in use and idle",
+		func() float64 { return float64(db.Stats().OpenConnections) }, "db", dbName)
+	metrics.Default.GaugeFunc("db_pool_in_use_connections", "Number of connections currently in use",
+		func() float64 { return float64(db.Stats().InUse) }, "db", dbName)
+	metrics.Default.GaugeFunc("db_pool_idle_connections", "Number of idle connections",
+		func() float64 { return float64(db.Stats().Idle) }, "db", dbName)
+	metrics.Default.CounterFunc("db_pool_wait_total", "Total number of connections waited for",
+		func() float64 { return float64(db.Stats().WaitCount) }, "db", dbName)
+	metrics.Default.CounterFunc("db_pool_wait_duration_seconds_total", "Total time blocked waiting for a new connection",
+		func() float64 { return db.Stats().WaitDuration.Seconds() }, "db", dbName)
+}
+
+// Ping checks the database is reachable
+func (s *Storage) Ping(ctx context.Context) error {
+	db, err := s.Instance.DB()
+	if err != nil {
+		return ErrPostgresPing(err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return ErrPostgresPing(err)
+	}
+	return nil
+}
+
 func (s *Storage) Close() {
 	if s.Instance != nil {
 		db, _ := s.Instance.DB()
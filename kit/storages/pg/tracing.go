@@ -0,0 +1,59 @@
+package pg
+
+import (
+	"errors"
+	"github.com/mikhailbolshakov/decision/kit/tracing"
+	"go.uber.org/multierr"
+	"gorm.io/gorm"
+)
+
+const spanInstanceKey = "tracing:span"
+
+// registerTracing wraps each gorm operation with a span being a child of the span in the statement context
+func registerTracing(db *gorm.DB) error {
+	cb := db.Callback()
+	return multierr.Combine(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startSpan("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startSpan("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startSpan("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startSpan("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startSpan("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startSpan("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil {
+			return
+		}
+		ctx, span := tracing.StartSpan(db.Statement.Context, "gorm."+operation)
+		span.SetAttr("db.table", db.Statement.Table)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanInstanceKey, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(*tracing.Span)
+	if !ok {
+		return
+	}
+	span.SetAttr("db.statement", db.Statement.SQL.String())
+	span.SetAttr("db.rows", db.RowsAffected)
+	// not found isn't a failure of the query
+	if !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.SetError(db.Error)
+	}
+	span.End()
+}
@@ -0,0 +1,23 @@
+package tracing
+
+import (
+	"github.com/mikhailbolshakov/decision/kit"
+)
+
+const (
+	ErrCodeTracingExporterInvalid = "TRC-001"
+	ErrCodeTracingFilePathEmpty   = "TRC-002"
+	ErrCodeTracingFileOpen        = "TRC-003"
+)
+
+var (
+	ErrTracingExporterInvalid = func(exporter string) error {
+		return kit.NewAppErrBuilder(ErrCodeTracingExporterInvalid, "invalid exporter").F(kit.KV{"exporter": exporter}).Err()
+	}
+	ErrTracingFilePathEmpty = func() error {
+		return kit.NewAppErrBuilder(ErrCodeTracingFilePathEmpty, "file path must be specified for file exporter").Err()
+	}
+	ErrTracingFileOpen = func(cause error, path string) error {
+		return kit.NewAppErrBuilder(ErrCodeTracingFileOpen, "open file failed").Wrap(cause).F(kit.KV{"path": path}).Err()
+	}
+)
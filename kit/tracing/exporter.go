@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Exporter sends finished spans to a backend
+type Exporter interface {
+	// Export exports a finished span
+	Export(span *SpanData)
+	// Close flushes and releases resources
+	Close() error
+}
+
+// NewExporter creates exporter by config
+func NewExporter(cfg *Config) (Exporter, error) {
+	if cfg == nil {
+		return noopExporter{}, nil
+	}
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return noopExporter{}, nil
+	case ExporterStdout:
+		return NewWriterExporter(os.Stdout, nil), nil
+	case ExporterFile:
+		if cfg.Path == "" {
+			return nil, ErrTracingFilePathEmpty()
+		}
+		f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, ErrTracingFileOpen(err, cfg.Path)
+		}
+		return NewWriterExporter(f, f), nil
+	default:
+		return nil, ErrTracingExporterInvalid(cfg.Exporter)
+	}
+}
+
+type noopExporter struct{}
+
+func (noopExporter) Export(*SpanData) {}
+
+func (noopExporter) Close() error { return nil }
+
+// writerExporter writes spans as JSON lines
+type writerExporter struct {
+	sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// NewWriterExporter creates exporter writing spans as JSON lines to w, closer is closed on Close if specified
+func NewWriterExporter(w io.Writer, closer io.Closer) Exporter {
+	return &writerExporter{enc: json.NewEncoder(w), closer: closer}
+}
+
+func (e *writerExporter) Export(span *SpanData) {
+	e.Lock()
+	defer e.Unlock()
+	_ = e.enc.Encode(span)
+}
+
+func (e *writerExporter) Close() error {
+	e.Lock()
+	defer e.Unlock()
+	if e.closer != nil {
+		return e.closer.Close()
+	}
+	return nil
+}
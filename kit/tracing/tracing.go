@@ -0,0 +1,208 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/mikhailbolshakov/decision/kit"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderTraceparent W3C trace context header
+	HeaderTraceparent = "traceparent"
+
+	StatusOk    = "ok"
+	StatusError = "error"
+
+	traceparentVersion = "00"
+	flagSampled        = "01"
+)
+
+// Config tracing configuration
+type Config struct {
+	Exporter string // Exporter none, stdout or file; none by default
+	Path     string // Path file spans are written to by file exporter
+	Service  string // Service service name spans are marked with
+}
+
+// SpanData finished span passed to exporter
+type SpanData struct {
+	TraceId      string    `json:"traceId"`
+	SpanId       string    `json:"spanId"`
+	ParentSpanId string    `json:"parentSpanId,omitempty"`
+	Name         string    `json:"name"`
+	Service      string    `json:"service,omitempty"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	DurationMs   float64   `json:"durationMs"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	Attributes   kit.KV    `json:"attributes,omitempty"`
+}
+
+// Span operation being traced
+type Span struct {
+	sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+type tracer struct {
+	sync.RWMutex
+	exporter Exporter
+	service  string
+}
+
+var t = &tracer{exporter: noopExporter{}}
+
+// Init sets up exporter, spans started before are exported by the previous exporter
+func Init(cfg *Config) error {
+	exporter, err := NewExporter(cfg)
+	if err != nil {
+		return err
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.exporter = exporter
+	if cfg != nil {
+		t.service = cfg.Service
+	}
+	return nil
+}
+
+// Close closes exporter, no spans are exported afterwards
+func Close() error {
+	t.Lock()
+	defer t.Unlock()
+	exporter := t.exporter
+	t.exporter = noopExporter{}
+	return exporter.Close()
+}
+
+// StartSpan starts a span as a child of the span specified in request context
+// the returned context carries the new span, so that logs and nested spans refer to it
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// request context is copied not to affect the parent context
+	rCtx := kit.NewRequestCtx()
+	if r, ok := kit.Request(ctx); ok && r != nil {
+		c := *r
+		rCtx = &c
+	}
+
+	s := &Span{data: SpanData{
+		TraceId:      rCtx.GetTraceId(),
+		ParentSpanId: rCtx.GetSpanId(),
+		SpanId:       newId(8),
+		Name:         name,
+		Start:        time.Now(),
+		Status:       StatusOk,
+	}}
+	if s.data.TraceId == "" {
+		s.data.TraceId = newId(16)
+	}
+	return rCtx.WithTrace(s.data.TraceId, s.data.SpanId).ToContext(ctx), s
+}
+
+// TraceId returns span's trace id
+func (s *Span) TraceId() string {
+	return s.data.TraceId
+}
+
+// SpanId returns span id
+func (s *Span) SpanId() string {
+	return s.data.SpanId
+}
+
+// SetAttr sets span attribute
+func (s *Span) SetAttr(key string, value interface{}) *Span {
+	s.Lock()
+	defer s.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = kit.KV{}
+	}
+	s.data.Attributes[key] = value
+	return s
+}
+
+// SetError marks span as failed, nil error is ignored
+func (s *Span) SetError(err error) *Span {
+	if err == nil {
+		return s
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.data.Status = StatusError
+	s.data.Error = err.Error()
+	return s
+}
+
+// End finishes span and exports it, subsequent calls are ignored
+func (s *Span) End() {
+	s.Lock()
+	if s.ended {
+		s.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	s.data.DurationMs = float64(s.data.End.Sub(s.data.Start).Microseconds()) / 1000
+	t.RLock()
+	s.data.Service = t.service
+	exporter := t.exporter
+	t.RUnlock()
+	data := s.data
+	s.Unlock()
+	exporter.Export(&data)
+}
+
+// ParseTraceparent parses W3C traceparent header
+func ParseTraceparent(header string) (traceId, parentSpanId string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", "", false
+	}
+	// version 00 has exactly 4 parts, future versions may add more
+	if parts[0] == traceparentVersion && len(parts) != 4 {
+		return "", "", false
+	}
+	if !isHex(parts[0]) || !validId(parts[1], 16) || !validId(parts[2], 8) || len(parts[3]) != 2 || !isHex(parts[3]) {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// Traceparent formats W3C traceparent header of the current span in context, empty if there is no span
+func Traceparent(ctx context.Context) string {
+	r, ok := kit.Request(ctx)
+	if !ok || r == nil || r.GetTraceId() == "" || r.GetSpanId() == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", traceparentVersion, r.GetTraceId(), r.GetSpanId(), flagSampled)
+}
+
+func newId(bytes int) string {
+	b := make([]byte, bytes)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validId checks id is lowercase hex of the given size in bytes and not all zeros
+func validId(id string, bytes int) bool {
+	return len(id) == bytes*2 && isHex(id) && strings.Trim(id, "0") != ""
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var logger = kit.InitLogger(&kit.LogConfig{Level: kit.InfoLevel})
+var logf = func() kit.CLogger {
+	return kit.L(logger)
+}
+
+type tracingTestSuite struct {
+	kit.Suite
+	buf *bytes.Buffer
+}
+
+func (s *tracingTestSuite) SetupSuite() {
+	s.Suite.Init(logf)
+}
+
+func (s *tracingTestSuite) SetupTest() {
+	s.buf = &bytes.Buffer{}
+	t.Lock()
+	t.exporter = NewWriterExporter(s.buf, nil)
+	t.service = "test"
+	t.Unlock()
+}
+
+func (s *tracingTestSuite) TearDownTest() {
+	s.NoError(Close())
+}
+
+func TestTracingSuite(t *testing.T) {
+	suite.Run(t, new(tracingTestSuite))
+}
+
+func (s *tracingTestSuite) exported() []*SpanData {
+	var spans []*SpanData
+	for _, line := range strings.Split(strings.TrimSpace(s.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		span := &SpanData{}
+		s.NoError(json.Unmarshal([]byte(line), span))
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+func (s *tracingTestSuite) Test_ParseTraceparent() {
+	traceId, spanId, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	s.True(ok)
+	s.Equal("4bf92f3577b34da6a3ce929d0e0e4736", traceId)
+	s.Equal("00f067aa0ba902b7", spanId)
+
+	// future versions may have additional fields
+	_, _, ok = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-ext")
+	s.True(ok)
+
+	for _, h := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-ext",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+	} {
+		_, _, ok := ParseTraceparent(h)
+		s.False(ok, h)
+	}
+}
+
+func (s *tracingTestSuite) Test_StartSpan_NewTrace() {
+	ctx, span := StartSpan(context.Background(), "root")
+	s.Len(span.TraceId(), 32)
+	s.Len(span.SpanId(), 16)
+	r, ok := kit.Request(ctx)
+	s.True(ok)
+	s.Equal(span.TraceId(), r.GetTraceId())
+	s.Equal(span.SpanId(), r.GetSpanId())
+	s.Equal("00-"+span.TraceId()+"-"+span.SpanId()+"-01", Traceparent(ctx))
+}
+
+func (s *tracingTestSuite) Test_StartSpan_Child() {
+	parentCtx := kit.NewRequestCtx().WithTrace("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7").ToContext(context.Background())
+
+	ctx, span := StartSpan(parentCtx, "child")
+	span.SetAttr("key", "value").SetError(errors.New("failed"))
+	span.End()
+	// subsequent ends are ignored
+	span.End()
+
+	s.Equal("4bf92f3577b34da6a3ce929d0e0e4736", span.TraceId())
+	s.NotEqual("00f067aa0ba902b7", span.SpanId())
+	// parent context isn't affected
+	r, _ := kit.Request(parentCtx)
+	s.Equal("00f067aa0ba902b7", r.GetSpanId())
+	r, _ = kit.Request(ctx)
+	s.Equal(span.SpanId(), r.GetSpanId())
+
+	spans := s.exported()
+	s.Len(spans, 1)
+	s.Equal("child", spans[0].Name)
+	s.Equal("test", spans[0].Service)
+	s.Equal("00f067aa0ba902b7", spans[0].ParentSpanId)
+	s.Equal(StatusError, spans[0].Status)
+	s.Equal("failed", spans[0].Error)
+	s.Equal("value", spans[0].Attributes["key"])
+	s.False(spans[0].End.Before(spans[0].Start))
+}
+
+func (s *tracingTestSuite) Test_Traceparent_NoSpan() {
+	s.Empty(Traceparent(context.Background()))
+	s.Empty(Traceparent(kit.NewRequestCtx().ToContext(context.Background())))
+}
+
+func (s *tracingTestSuite) Test_Close_NoExport() {
+	s.NoError(Close())
+	_, span := StartSpan(s.Ctx, "span")
+	span.End()
+	s.Empty(s.buf.String())
+}
+
+func (s *tracingTestSuite) Test_NewExporter() {
+	e, err := NewExporter(nil)
+	s.NoError(err)
+	s.IsType(noopExporter{}, e)
+	e, err = NewExporter(&Config{Exporter: ExporterStdout})
+	s.NoError(err)
+	s.IsType(&writerExporter{}, e)
+
+	_, err = NewExporter(&Config{Exporter: "jaeger"})
+	s.AssertAppErr(err, ErrCodeTracingExporterInvalid)
+	_, err = NewExporter(&Config{Exporter: ExporterFile})
+	s.AssertAppErr(err, ErrCodeTracingFilePathEmpty)
+	_, err = NewExporter(&Config{Exporter: ExporterFile, Path: filepath.Join(s.T().TempDir(), "absent", "spans.log")})
+	s.AssertAppErr(err, ErrCodeTracingFileOpen)
+}
+
+func (s *tracingTestSuite) Test_FileExporter() {
+	path := filepath.Join(s.T().TempDir(), "spans.log")
+	s.NoError(Init(&Config{Exporter: ExporterFile, Path: path, Service: "svc"}))
+	_, span := StartSpan(s.Ctx, "span")
+	span.End()
+	s.NoError(Close())
+
+	b, err := os.ReadFile(path)
+	s.NoError(err)
+	span2 := &SpanData{}
+	s.NoError(json.Unmarshal(b, span2))
+	s.Equal("span", span2.Name)
+	s.Equal("svc", span2.Service)
+	s.Equal(StatusOk, span2.Status)
+}
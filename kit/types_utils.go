@@ -122,6 +122,10 @@ func IntPtr(i int) *int {
 	return &i
 }
 
+func Int64Ptr(i int64) *int64 {
+	return &i
+}
+
 func UInt32Ptr(i uint32) *uint32 {
 	return &i
 }
@@ -14,6 +14,82 @@ type DecisionService struct {
 	mock.Mock
 }
 
+// AnalyzeSensitivity provides a mock function with given fields: ctx, problem, params
+func (_m *DecisionService) AnalyzeSensitivity(ctx context.Context, problem *domain.Problem, params *domain.SensitivityParams) (*domain.SensitivityResult, error) {
+	ret := _m.Called(ctx, problem, params)
+
+	var r0 *domain.SensitivityResult
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Problem, *domain.SensitivityParams) *domain.SensitivityResult); ok {
+		r0 = rf(ctx, problem, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SensitivityResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Problem, *domain.SensitivityParams) error); ok {
+		r1 = rf(ctx, problem, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Close provides a mock function with given fields: ctx
+func (_m *DecisionService) Close(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDecision provides a mock function with given fields: ctx, decisionId
+func (_m *DecisionService) GetDecision(ctx context.Context, decisionId string) (*domain.Decision, error) {
+	ret := _m.Called(ctx, decisionId)
+
+	var r0 *domain.Decision
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Decision); ok {
+		r0 = rf(ctx, decisionId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Decision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, decisionId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMethods provides a mock function with given fields: ctx
+func (_m *DecisionService) GetMethods(ctx context.Context) []*domain.MethodDescription {
+	ret := _m.Called(ctx)
+
+	var r0 []*domain.MethodDescription
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.MethodDescription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.MethodDescription)
+		}
+	}
+
+	return r0
+}
+
 // MakeDecision provides a mock function with given fields: ctx, userId, problem
 func (_m *DecisionService) MakeDecision(ctx context.Context, userId string, problem *domain.Problem) (*domain.Decision, error) {
 	ret := _m.Called(ctx, userId, problem)
@@ -37,6 +113,29 @@ func (_m *DecisionService) MakeDecision(ctx context.Context, userId string, prob
 	return r0, r1
 }
 
+// MakeDecisionByProblem provides a mock function with given fields: ctx, userId, problemId
+func (_m *DecisionService) MakeDecisionByProblem(ctx context.Context, userId string, problemId string) (*domain.Decision, error) {
+	ret := _m.Called(ctx, userId, problemId)
+
+	var r0 *domain.Decision
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Decision); ok {
+		r0 = rf(ctx, userId, problemId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Decision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userId, problemId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewDecisionService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -9,6 +9,20 @@ type Migration struct {
 	mock.Mock
 }
 
+// Check provides a mock function with given fields:
+func (_m *Migration) Check() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Up provides a mock function with given fields:
 func (_m *Migration) Up() error {
 	ret := _m.Called()
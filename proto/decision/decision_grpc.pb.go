@@ -0,0 +1,182 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: decision/decision.proto
+
+package decision
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DecisionServiceClient is the client API for DecisionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DecisionServiceClient interface {
+	// MakeDecision makes a decision on the problem
+	// if user_id is specified, the problem and the decision are stored
+	MakeDecision(ctx context.Context, in *MakeDecisionRequest, opts ...grpc.CallOption) (*Decision, error)
+	// EvaluateBatch makes decisions on a stream of problems
+	// a result is sent for each request, failure of a request doesn't break the stream
+	EvaluateBatch(ctx context.Context, opts ...grpc.CallOption) (DecisionService_EvaluateBatchClient, error)
+}
+
+type decisionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDecisionServiceClient(cc grpc.ClientConnInterface) DecisionServiceClient {
+	return &decisionServiceClient{cc}
+}
+
+func (c *decisionServiceClient) MakeDecision(ctx context.Context, in *MakeDecisionRequest, opts ...grpc.CallOption) (*Decision, error) {
+	out := new(Decision)
+	err := c.cc.Invoke(ctx, "/decision.DecisionService/MakeDecision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decisionServiceClient) EvaluateBatch(ctx context.Context, opts ...grpc.CallOption) (DecisionService_EvaluateBatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DecisionService_ServiceDesc.Streams[0], "/decision.DecisionService/EvaluateBatch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &decisionServiceEvaluateBatchClient{stream}
+	return x, nil
+}
+
+type DecisionService_EvaluateBatchClient interface {
+	Send(*MakeDecisionRequest) error
+	Recv() (*BatchResult, error)
+	grpc.ClientStream
+}
+
+type decisionServiceEvaluateBatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *decisionServiceEvaluateBatchClient) Send(m *MakeDecisionRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *decisionServiceEvaluateBatchClient) Recv() (*BatchResult, error) {
+	m := new(BatchResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DecisionServiceServer is the server API for DecisionService service.
+// All implementations must embed UnimplementedDecisionServiceServer
+// for forward compatibility
+type DecisionServiceServer interface {
+	// MakeDecision makes a decision on the problem
+	// if user_id is specified, the problem and the decision are stored
+	MakeDecision(context.Context, *MakeDecisionRequest) (*Decision, error)
+	// EvaluateBatch makes decisions on a stream of problems
+	// a result is sent for each request, failure of a request doesn't break the stream
+	EvaluateBatch(DecisionService_EvaluateBatchServer) error
+	mustEmbedUnimplementedDecisionServiceServer()
+}
+
+// UnimplementedDecisionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDecisionServiceServer struct {
+}
+
+func (UnimplementedDecisionServiceServer) MakeDecision(context.Context, *MakeDecisionRequest) (*Decision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MakeDecision not implemented")
+}
+func (UnimplementedDecisionServiceServer) EvaluateBatch(DecisionService_EvaluateBatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method EvaluateBatch not implemented")
+}
+func (UnimplementedDecisionServiceServer) mustEmbedUnimplementedDecisionServiceServer() {}
+
+// UnsafeDecisionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DecisionServiceServer will
+// result in compilation errors.
+type UnsafeDecisionServiceServer interface {
+	mustEmbedUnimplementedDecisionServiceServer()
+}
+
+func RegisterDecisionServiceServer(s grpc.ServiceRegistrar, srv DecisionServiceServer) {
+	s.RegisterService(&DecisionService_ServiceDesc, srv)
+}
+
+func _DecisionService_MakeDecision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MakeDecisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecisionServiceServer).MakeDecision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/decision.DecisionService/MakeDecision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecisionServiceServer).MakeDecision(ctx, req.(*MakeDecisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DecisionService_EvaluateBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DecisionServiceServer).EvaluateBatch(&decisionServiceEvaluateBatchServer{stream})
+}
+
+type DecisionService_EvaluateBatchServer interface {
+	Send(*BatchResult) error
+	Recv() (*MakeDecisionRequest, error)
+	grpc.ServerStream
+}
+
+type decisionServiceEvaluateBatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *decisionServiceEvaluateBatchServer) Send(m *BatchResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *decisionServiceEvaluateBatchServer) Recv() (*MakeDecisionRequest, error) {
+	m := new(MakeDecisionRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DecisionService_ServiceDesc is the grpc.ServiceDesc for DecisionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DecisionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "decision.DecisionService",
+	HandlerType: (*DecisionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MakeDecision",
+			Handler:    _DecisionService_MakeDecision_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EvaluateBatch",
+			Handler:       _DecisionService_EvaluateBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "decision/decision.proto",
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/health"
+	"github.com/mikhailbolshakov/decision/kit/storages/pg"
+)
+
+// Adapter provides access to all storages of the service
+type Adapter interface {
+	kit.Adapter
+	health.Component
+	// GetProblemStorage returns problem storage
+	GetProblemStorage() domain.ProblemStorage
+	// GetDecisionStorage returns decision storage
+	GetDecisionStorage() domain.DecisionStorage
+	// GetGroupStorage returns group decision storage
+	GetGroupStorage() domain.GroupStorage
+	// GetTemplateStorage returns template storage
+	GetTemplateStorage() domain.TemplateStorage
+}
+
+type adapterImpl struct {
+	pg              *pg.Storage
+	mig             pg.Migration
+	problemStorage  *problemStorageImpl
+	decisionStorage *decisionStorageImpl
+	groupStorage    *groupStorageImpl
+	templateStorage *templateStorageImpl
+}
+
+func NewAdapter() Adapter {
+	a := &adapterImpl{}
+	a.problemStorage = newProblemStorage(a)
+	a.decisionStorage = newDecisionStorage(a)
+	a.groupStorage = newGroupStorage(a)
+	a.templateStorage = newTemplateStorage(a)
+	return a
+}
+
+func (a *adapterImpl) l() kit.CLogger {
+	return decision.L().Cmp("storage-adapter")
+}
+
+func (a *adapterImpl) Init(ctx context.Context, config interface{}) error {
+	l := a.l().C(ctx).Mth("init")
+
+	cfg, ok := config.(*pg.DbClusterConfig)
+	if !ok || cfg == nil || cfg.Master == nil {
+		return errors.ErrStorageInvalidConfig(ctx)
+	}
+
+	// open connection
+	var err error
+	a.pg, err = pg.Open(cfg.Master, decision.LF())
+	if err != nil {
+		return err
+	}
+
+	// apply migrations
+	if cfg.MigPath != "" {
+		db, err := a.pg.Instance.DB()
+		if err != nil {
+			return pg.ErrPostgresOpen(err)
+		}
+		a.mig = pg.NewMigration(db, cfg.MigPath, decision.LF())
+		if err := a.mig.Up(); err != nil {
+			return err
+		}
+	}
+
+	l.Inf("ok")
+
+	return nil
+}
+
+func (a *adapterImpl) Close(ctx context.Context) error {
+	if a.pg != nil {
+		a.pg.Close()
+	}
+	return nil
+}
+
+func (a *adapterImpl) RegisterHealthChecks(r health.Registry) {
+	r.Register("postgres", func(ctx context.Context) error {
+		return a.pg.Ping(ctx)
+	})
+	if a.mig != nil {
+		r.Register("migrations", func(ctx context.Context) error {
+			return a.mig.Check()
+		})
+	}
+}
+
+func (a *adapterImpl) GetProblemStorage() domain.ProblemStorage {
+	return a.problemStorage
+}
+
+func (a *adapterImpl) GetDecisionStorage() domain.DecisionStorage {
+	return a.decisionStorage
+}
+
+func (a *adapterImpl) GetGroupStorage() domain.GroupStorage {
+	return a.groupStorage
+}
+
+func (a *adapterImpl) GetTemplateStorage() domain.TemplateStorage {
+	return a.templateStorage
+}
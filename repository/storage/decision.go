@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+)
+
+type decisionStorageImpl struct {
+	a *adapterImpl
+}
+
+func newDecisionStorage(a *adapterImpl) *decisionStorageImpl {
+	return &decisionStorageImpl{a: a}
+}
+
+func (s *decisionStorageImpl) l() kit.CLogger {
+	return decision.L().Cmp("decision-storage")
+}
+
+func (s *decisionStorageImpl) CreateDecision(ctx context.Context, d *domain.Decision) error {
+	s.l().C(ctx).Mth("create").Dbg()
+
+	dto, err := s.toDecisionDto(d)
+	if err != nil {
+		return errors.ErrStorageDecisionMarshal(ctx, err)
+	}
+
+	if err := s.a.pg.Instance.WithContext(ctx).Create(dto).Error; err != nil {
+		return errors.ErrStorageDecisionCreate(ctx, err)
+	}
+	return nil
+}
+
+func (s *decisionStorageImpl) GetDecision(ctx context.Context, decisionId string) (*domain.Decision, error) {
+	s.l().C(ctx).Mth("get").Dbg()
+
+	dto := &decisionDto{}
+	res := s.a.pg.Instance.WithContext(ctx).Where("id = ?", decisionId).Limit(1).Find(dto)
+	if res.Error != nil {
+		return nil, errors.ErrStorageDecisionGet(ctx, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	r, err := s.toDecisionDomain(dto)
+	if err != nil {
+		return nil, errors.ErrStorageDecisionUnmarshal(ctx, err)
+	}
+	return r, nil
+}
+
+func (s *decisionStorageImpl) GetDecisionsByProblem(ctx context.Context, problemId string) ([]*domain.Decision, error) {
+	s.l().C(ctx).Mth("get-by-problem").Dbg()
+
+	var dtos []*decisionDto
+	if err := s.a.pg.Instance.WithContext(ctx).Where("problem_id = ?", problemId).Order("created_at desc").Find(&dtos).Error; err != nil {
+		return nil, errors.ErrStorageDecisionGet(ctx, err)
+	}
+
+	var r []*domain.Decision
+	for _, dto := range dtos {
+		d, err := s.toDecisionDomain(dto)
+		if err != nil {
+			return nil, errors.ErrStorageDecisionUnmarshal(ctx, err)
+		}
+		r = append(r, d)
+	}
+	return r, nil
+}
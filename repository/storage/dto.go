@@ -0,0 +1,957 @@
+package storage
+
+import (
+	"encoding/json"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/storages/pg"
+	"time"
+)
+
+const (
+	qualityKindPro = "pro"
+	qualityKindCon = "con"
+)
+
+type problemDto struct {
+	pg.GormDto
+	Id         string  `gorm:"column:id"`
+	UserId     string  `gorm:"column:user_id"`
+	Name       string  `gorm:"column:name"`
+	Method     string  `gorm:"column:method"`
+	ProsCons   *string `gorm:"column:pros_cons"`
+	Ahp        *string `gorm:"column:ahp"`
+	Topsis     *string `gorm:"column:topsis"`
+	Simulation *string `gorm:"column:simulation"`
+	Version    int     `gorm:"column:version"`
+}
+
+type problemVersionDto struct {
+	pg.GormDto
+	Id        string `gorm:"column:id"`
+	ProblemId string `gorm:"column:problem_id"`
+	Version   int    `gorm:"column:version"`
+	Snapshot  string `gorm:"column:snapshot"`
+}
+
+// problemSnapshot immutable copy of the problem stored with a version
+// json attributes keep values of the corresponding columns as is
+type problemSnapshot struct {
+	Name       string            `json:"name"`
+	Method     string            `json:"method,omitempty"`
+	ProsCons   *string           `json:"prosCons,omitempty"`
+	Ahp        *string           `json:"ahp,omitempty"`
+	Topsis     *string           `json:"topsis,omitempty"`
+	Simulation *string           `json:"simulation,omitempty"`
+	Options    []*optionSnapshot `json:"options"`
+}
+
+type optionSnapshot struct {
+	Id        string             `json:"id"`
+	Name      string             `json:"name"`
+	Fields    *string            `json:"fields,omitempty"`
+	Qualities []*qualitySnapshot `json:"qualities,omitempty"`
+}
+
+type qualitySnapshot struct {
+	Id              string  `json:"id"`
+	Kind            string  `json:"kind"`
+	Name            string  `json:"name"`
+	Importance      float64 `json:"importance"`
+	Probability     float64 `json:"probability"`
+	ImportanceDist  *string `json:"importanceDist,omitempty"`
+	ProbabilityDist *string `json:"probabilityDist,omitempty"`
+}
+
+type prosCons struct {
+	Scoring   string   `json:"scoring,omitempty"`
+	Smoothing *float64 `json:"smoothing,omitempty"`
+}
+
+type ahp struct {
+	Criteria            []string      `json:"criteria,omitempty"`
+	CriteriaComparisons [][]float64   `json:"criteriaComparisons"`
+	OptionComparisons   [][][]float64 `json:"optionComparisons"`
+}
+
+type optionDto struct {
+	pg.GormDto
+	Id        string  `gorm:"column:id"`
+	ProblemId string  `gorm:"column:problem_id"`
+	Name      string  `gorm:"column:name"`
+	Fields    *string `gorm:"column:fields"`
+	Ord       int     `gorm:"column:ord"`
+}
+
+type qualityDto struct {
+	pg.GormDto
+	Id              string  `gorm:"column:id"`
+	ProblemId       string  `gorm:"column:problem_id"`
+	OptionId        string  `gorm:"column:option_id"`
+	Kind            string  `gorm:"column:kind"`
+	Name            string  `gorm:"column:name"`
+	Importance      float64 `gorm:"column:importance"`
+	Probability     float64 `gorm:"column:probability"`
+	ImportanceDist  *string `gorm:"column:importance_dist"`
+	ProbabilityDist *string `gorm:"column:probability_dist"`
+	Ord             int     `gorm:"column:ord"`
+}
+
+type topsisCriterion struct {
+	Name      string  `json:"name,omitempty"`
+	Weight    float64 `json:"weight"`
+	Direction string  `json:"direction,omitempty"`
+}
+
+type topsis struct {
+	Criteria []*topsisCriterion `json:"criteria"`
+	Scores   [][]float64        `json:"scores"`
+}
+
+type distribution struct {
+	Type   string  `json:"type"`
+	Min    float64 `json:"min,omitempty"`
+	Max    float64 `json:"max,omitempty"`
+	Mode   float64 `json:"mode,omitempty"`
+	Mean   float64 `json:"mean,omitempty"`
+	StdDev float64 `json:"stdDev,omitempty"`
+	Alpha  float64 `json:"alpha,omitempty"`
+	Beta   float64 `json:"beta,omitempty"`
+}
+
+type simulation struct {
+	Iterations int    `json:"iterations,omitempty"`
+	Seed       *int64 `json:"seed,omitempty"`
+}
+
+type optionSimulation struct {
+	Mean            float64 `json:"mean"`
+	StdDev          float64 `json:"stdDev"`
+	P5              float64 `json:"p5"`
+	P25             float64 `json:"p25"`
+	P50             float64 `json:"p50"`
+	P75             float64 `json:"p75"`
+	P95             float64 `json:"p95"`
+	ProbabilityBest float64 `json:"probabilityBest"`
+}
+
+type simulationResult struct {
+	Iterations int                          `json:"iterations"`
+	Seed       int64                        `json:"seed"`
+	Options    map[string]*optionSimulation `json:"options"`
+}
+
+type decisionResult struct {
+	Method           string             `json:"method,omitempty"`
+	OptionsRating    map[string]float64 `json:"optionsRating"`
+	Scoring          string             `json:"scoring,omitempty"`
+	Smoothing        float64            `json:"smoothing,omitempty"`
+	ConsistencyRatio float64            `json:"cr,omitempty"`
+	Simulation       *simulationResult  `json:"simulation,omitempty"`
+	Group            *groupResult       `json:"group,omitempty"`
+	Explanation      *explanation       `json:"explanation,omitempty"`
+}
+
+type qualityContribution struct {
+	QualityId string  `json:"qualityId"`
+	Name      string  `json:"name,omitempty"`
+	Kind      string  `json:"kind"`
+	Weight    float64 `json:"weight"`
+	Share     float64 `json:"share"`
+}
+
+type optionExplanation struct {
+	OptionId      string                 `json:"optionId"`
+	Name          string                 `json:"name,omitempty"`
+	Rank          int                    `json:"rank"`
+	Rating        float64                `json:"rating"`
+	ProsTotal     float64                `json:"prosTotal,omitempty"`
+	ConsTotal     float64                `json:"consTotal,omitempty"`
+	Contributions []*qualityContribution `json:"contributions,omitempty"`
+}
+
+type explanation struct {
+	Ranking []*optionExplanation `json:"ranking"`
+	Margin  float64              `json:"margin"`
+	Summary string               `json:"summary,omitempty"`
+}
+
+type member struct {
+	UserId string  `json:"userId"`
+	Weight float64 `json:"weight"`
+}
+
+type qualitySpread struct {
+	QualityId         string  `json:"qualityId"`
+	ImportanceMin     float64 `json:"importanceMin"`
+	ImportanceMax     float64 `json:"importanceMax"`
+	ImportanceStdDev  float64 `json:"importanceStdDev"`
+	ProbabilityMin    float64 `json:"probabilityMin"`
+	ProbabilityMax    float64 `json:"probabilityMax"`
+	ProbabilityStdDev float64 `json:"probabilityStdDev"`
+}
+
+type groupResult struct {
+	Aggregation  string           `json:"aggregation"`
+	Participants []*member        `json:"participants"`
+	Spread       []*qualitySpread `json:"spread,omitempty"`
+}
+
+type memberDto struct {
+	pg.GormDto
+	Id        string  `gorm:"column:id"`
+	ProblemId string  `gorm:"column:problem_id"`
+	UserId    string  `gorm:"column:user_id"`
+	Weight    float64 `gorm:"column:weight"`
+}
+
+type assessmentDto struct {
+	pg.GormDto
+	Id          string  `gorm:"column:id"`
+	ProblemId   string  `gorm:"column:problem_id"`
+	UserId      string  `gorm:"column:user_id"`
+	QualityId   string  `gorm:"column:quality_id"`
+	Importance  float64 `gorm:"column:importance"`
+	Probability float64 `gorm:"column:probability"`
+}
+
+type decisionDto struct {
+	pg.GormDto
+	Id             string `gorm:"column:id"`
+	ProblemId      string `gorm:"column:problem_id"`
+	ProblemVersion int    `gorm:"column:problem_version"`
+	UserId         string `gorm:"column:user_id"`
+	Result         string `gorm:"column:result"`
+}
+
+type templateDto struct {
+	pg.GormDto
+	Id           string  `gorm:"column:id"`
+	UserId       *string `gorm:"column:user_id"`
+	Name         string  `gorm:"column:name"`
+	Description  *string `gorm:"column:description"`
+	Category     *string `gorm:"column:category"`
+	Criteria     string  `gorm:"column:criteria"`
+	OptionFields *string `gorm:"column:option_fields"`
+}
+
+type templateCriterion struct {
+	Name        string  `json:"name"`
+	Kind        string  `json:"kind"`
+	Importance  float64 `json:"importance"`
+	Probability float64 `json:"probability"`
+}
+
+type templateField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+func (problemDto) TableName() string {
+	return "decision.problems"
+}
+
+func (optionDto) TableName() string {
+	return "decision.options"
+}
+
+func (qualityDto) TableName() string {
+	return "decision.qualities"
+}
+
+func (problemVersionDto) TableName() string {
+	return "decision.problem_versions"
+}
+
+func (decisionDto) TableName() string {
+	return "decision.decisions"
+}
+
+func (memberDto) TableName() string {
+	return "decision.members"
+}
+
+func (assessmentDto) TableName() string {
+	return "decision.assessments"
+}
+
+func (templateDto) TableName() string {
+	return "decision.templates"
+}
+
+func (s *problemStorageImpl) toProblemDto(p *domain.Problem) (*problemDto, []*optionDto, []*qualityDto, error) {
+	pr := &problemDto{
+		GormDto: pg.GormDto{CreatedAt: timePtr(p.CreatedAt), UpdatedAt: timePtr(p.UpdatedAt)},
+		Id:      p.Id,
+		UserId:  p.UserId,
+		Name:    p.Name,
+		Method:  p.Method,
+	}
+	if p.ProsCons != nil {
+		pc, err := json.Marshal(&prosCons{Scoring: p.ProsCons.Scoring, Smoothing: p.ProsCons.Smoothing})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pr.ProsCons = kit.StringPtr(string(pc))
+	}
+	if p.Ahp != nil {
+		a, err := json.Marshal(&ahp{
+			Criteria:            p.Ahp.Criteria,
+			CriteriaComparisons: p.Ahp.CriteriaComparisons,
+			OptionComparisons:   p.Ahp.OptionComparisons,
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pr.Ahp = kit.StringPtr(string(a))
+	}
+	if p.Topsis != nil {
+		t := &topsis{Scores: p.Topsis.Scores}
+		for _, c := range p.Topsis.Criteria {
+			t.Criteria = append(t.Criteria, &topsisCriterion{Name: c.Name, Weight: c.Weight, Direction: c.Direction})
+		}
+		tj, err := json.Marshal(t)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pr.Topsis = kit.StringPtr(string(tj))
+	}
+	if p.Simulation != nil {
+		sj, err := json.Marshal(&simulation{Iterations: p.Simulation.Iterations, Seed: p.Simulation.Seed})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pr.Simulation = kit.StringPtr(string(sj))
+	}
+	var ops []*optionDto
+	var qs []*qualityDto
+	for i, op := range p.Options {
+		fields, err := s.toFieldsDto(op.Fields)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ops = append(ops, &optionDto{
+			GormDto:   pr.GormDto,
+			Id:        op.Id,
+			ProblemId: p.Id,
+			Name:      op.Name,
+			Fields:    fields,
+			Ord:       i,
+		})
+		opQs, err := s.toOptionQualitiesDto(pr, op)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		qs = append(qs, opQs...)
+	}
+	return pr, ops, qs, nil
+}
+
+// toOptionQualitiesDto converts both pros and cons of the option
+func (s *problemStorageImpl) toOptionQualitiesDto(pr *problemDto, op *domain.Option) ([]*qualityDto, error) {
+	pros, err := s.toQualitiesDto(pr, op.Id, qualityKindPro, op.Pros)
+	if err != nil {
+		return nil, err
+	}
+	cons, err := s.toQualitiesDto(pr, op.Id, qualityKindCon, op.Cons)
+	if err != nil {
+		return nil, err
+	}
+	return append(pros, cons...), nil
+}
+
+func (s *problemStorageImpl) toQualitiesDto(pr *problemDto, optionId, kind string, qualities []*domain.Quality) ([]*qualityDto, error) {
+	var r []*qualityDto
+	for i, q := range qualities {
+		impDist, err := s.toDistributionDto(q.ImportanceDist)
+		if err != nil {
+			return nil, err
+		}
+		probDist, err := s.toDistributionDto(q.ProbabilityDist)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, &qualityDto{
+			GormDto:         pr.GormDto,
+			Id:              q.Id,
+			ProblemId:       pr.Id,
+			OptionId:        optionId,
+			Kind:            kind,
+			Name:            q.Name,
+			Importance:      q.Importance,
+			Probability:     q.Probability,
+			ImportanceDist:  impDist,
+			ProbabilityDist: probDist,
+			Ord:             i,
+		})
+	}
+	return r, nil
+}
+
+func (s *problemStorageImpl) toDistributionDto(d *domain.Distribution) (*string, error) {
+	if d == nil {
+		return nil, nil
+	}
+	r, err := json.Marshal(&distribution{
+		Type:   d.Type,
+		Min:    d.Min,
+		Max:    d.Max,
+		Mode:   d.Mode,
+		Mean:   d.Mean,
+		StdDev: d.StdDev,
+		Alpha:  d.Alpha,
+		Beta:   d.Beta,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kit.StringPtr(string(r)), nil
+}
+
+func (s *problemStorageImpl) toDistributionDomain(d *string) (*domain.Distribution, error) {
+	if d == nil || *d == "" {
+		return nil, nil
+	}
+	r := &distribution{}
+	if err := json.Unmarshal([]byte(*d), r); err != nil {
+		return nil, err
+	}
+	return &domain.Distribution{
+		Type:   r.Type,
+		Min:    r.Min,
+		Max:    r.Max,
+		Mode:   r.Mode,
+		Mean:   r.Mean,
+		StdDev: r.StdDev,
+		Alpha:  r.Alpha,
+		Beta:   r.Beta,
+	}, nil
+}
+
+// toFieldsDto converts option fields to json, empty fields aren't stored
+func (s *problemStorageImpl) toFieldsDto(fields map[string]string) (*string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return kit.StringPtr(string(b)), nil
+}
+
+func (s *problemStorageImpl) toFieldsDomain(fields *string) (map[string]string, error) {
+	if fields == nil || *fields == "" {
+		return nil, nil
+	}
+	r := map[string]string{}
+	if err := json.Unmarshal([]byte(*fields), &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *problemStorageImpl) toProblemDomain(pr *problemDto, ops []*optionDto, qs []*qualityDto) (*domain.Problem, error) {
+	if pr == nil {
+		return nil, nil
+	}
+	r := &domain.Problem{
+		Id:        pr.Id,
+		UserId:    pr.UserId,
+		Name:      pr.Name,
+		Method:    pr.Method,
+		Version:   pr.Version,
+		CreatedAt: timeVal(pr.CreatedAt),
+		UpdatedAt: timeVal(pr.UpdatedAt),
+	}
+	if pr.ProsCons != nil && *pr.ProsCons != "" {
+		pc := &prosCons{}
+		if err := json.Unmarshal([]byte(*pr.ProsCons), pc); err != nil {
+			return nil, err
+		}
+		r.ProsCons = &domain.ProsCons{Scoring: pc.Scoring, Smoothing: pc.Smoothing}
+	}
+	if pr.Ahp != nil && *pr.Ahp != "" {
+		a := &ahp{}
+		if err := json.Unmarshal([]byte(*pr.Ahp), a); err != nil {
+			return nil, err
+		}
+		r.Ahp = &domain.Ahp{
+			Criteria:            a.Criteria,
+			CriteriaComparisons: a.CriteriaComparisons,
+			OptionComparisons:   a.OptionComparisons,
+		}
+	}
+	if pr.Topsis != nil && *pr.Topsis != "" {
+		t := &topsis{}
+		if err := json.Unmarshal([]byte(*pr.Topsis), t); err != nil {
+			return nil, err
+		}
+		r.Topsis = &domain.Topsis{Scores: t.Scores}
+		for _, c := range t.Criteria {
+			r.Topsis.Criteria = append(r.Topsis.Criteria, &domain.TopsisCriterion{Name: c.Name, Weight: c.Weight, Direction: c.Direction})
+		}
+	}
+	if pr.Simulation != nil && *pr.Simulation != "" {
+		sm := &simulation{}
+		if err := json.Unmarshal([]byte(*pr.Simulation), sm); err != nil {
+			return nil, err
+		}
+		r.Simulation = &domain.Simulation{Iterations: sm.Iterations, Seed: sm.Seed}
+	}
+	opMap := make(map[string]*domain.Option, len(ops))
+	for _, op := range ops {
+		o := &domain.Option{
+			Id:   op.Id,
+			Name: op.Name,
+		}
+		var err error
+		if o.Fields, err = s.toFieldsDomain(op.Fields); err != nil {
+			return nil, err
+		}
+		opMap[op.Id] = o
+		r.Options = append(r.Options, o)
+	}
+	for _, q := range qs {
+		o, ok := opMap[q.OptionId]
+		if !ok {
+			continue
+		}
+		dq := &domain.Quality{
+			Id:          q.Id,
+			Name:        q.Name,
+			Importance:  q.Importance,
+			Probability: q.Probability,
+		}
+		var err error
+		if dq.ImportanceDist, err = s.toDistributionDomain(q.ImportanceDist); err != nil {
+			return nil, err
+		}
+		if dq.ProbabilityDist, err = s.toDistributionDomain(q.ProbabilityDist); err != nil {
+			return nil, err
+		}
+		if q.Kind == qualityKindPro {
+			o.Pros = append(o.Pros, dq)
+		} else {
+			o.Cons = append(o.Cons, dq)
+		}
+	}
+	return r, nil
+}
+
+func (s *problemStorageImpl) toProblemSnapshot(pr *problemDto, ops []*optionDto, qs []*qualityDto) (string, error) {
+	r := &problemSnapshot{
+		Name:       pr.Name,
+		Method:     pr.Method,
+		ProsCons:   pr.ProsCons,
+		Ahp:        pr.Ahp,
+		Topsis:     pr.Topsis,
+		Simulation: pr.Simulation,
+	}
+	opMap := make(map[string]*optionSnapshot, len(ops))
+	for _, op := range ops {
+		o := &optionSnapshot{Id: op.Id, Name: op.Name, Fields: op.Fields}
+		opMap[op.Id] = o
+		r.Options = append(r.Options, o)
+	}
+	for _, q := range qs {
+		if o, ok := opMap[q.OptionId]; ok {
+			o.Qualities = append(o.Qualities, &qualitySnapshot{
+				Id:              q.Id,
+				Kind:            q.Kind,
+				Name:            q.Name,
+				Importance:      q.Importance,
+				Probability:     q.Probability,
+				ImportanceDist:  q.ImportanceDist,
+				ProbabilityDist: q.ProbabilityDist,
+			})
+		}
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// toProblemVersionDomain restores the problem from the version snapshot
+func (s *problemStorageImpl) toProblemVersionDomain(pr *problemDto, v *problemVersionDto) (*domain.Problem, error) {
+	sn := &problemSnapshot{}
+	if err := json.Unmarshal([]byte(v.Snapshot), sn); err != nil {
+		return nil, err
+	}
+	p := &problemDto{
+		GormDto:    pg.GormDto{CreatedAt: pr.CreatedAt, UpdatedAt: v.CreatedAt},
+		Id:         pr.Id,
+		UserId:     pr.UserId,
+		Name:       sn.Name,
+		Method:     sn.Method,
+		ProsCons:   sn.ProsCons,
+		Ahp:        sn.Ahp,
+		Topsis:     sn.Topsis,
+		Simulation: sn.Simulation,
+		Version:    v.Version,
+	}
+	var ops []*optionDto
+	var qs []*qualityDto
+	for _, op := range sn.Options {
+		ops = append(ops, &optionDto{Id: op.Id, ProblemId: pr.Id, Name: op.Name, Fields: op.Fields})
+		for _, q := range op.Qualities {
+			qs = append(qs, &qualityDto{
+				Id:              q.Id,
+				ProblemId:       pr.Id,
+				OptionId:        op.Id,
+				Kind:            q.Kind,
+				Name:            q.Name,
+				Importance:      q.Importance,
+				Probability:     q.Probability,
+				ImportanceDist:  q.ImportanceDist,
+				ProbabilityDist: q.ProbabilityDist,
+			})
+		}
+	}
+	return s.toProblemDomain(p, ops, qs)
+}
+
+func (s *problemStorageImpl) toProblemVersionsDomain(vs []*problemVersionDto) []*domain.ProblemVersion {
+	r := make([]*domain.ProblemVersion, 0, len(vs))
+	for _, v := range vs {
+		r = append(r, &domain.ProblemVersion{
+			ProblemId: v.ProblemId,
+			Version:   v.Version,
+			CreatedAt: timeVal(v.CreatedAt),
+		})
+	}
+	return r
+}
+
+func (s *decisionStorageImpl) toDecisionDto(d *domain.Decision) (*decisionDto, error) {
+	res, err := json.Marshal(&decisionResult{
+		Method:           d.Result.Method,
+		OptionsRating:    d.Result.OptionsRating,
+		Scoring:          d.Result.Scoring,
+		Smoothing:        d.Result.Smoothing,
+		ConsistencyRatio: d.Result.ConsistencyRatio,
+		Simulation:       s.toSimulationResultDto(d.Result.Simulation),
+		Group:            s.toGroupResultDto(d.Result.Group),
+		Explanation:      s.toExplanationDto(d.Result.Explanation),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &decisionDto{
+		GormDto:        pg.GormDto{CreatedAt: timePtr(d.CreatedAt), UpdatedAt: timePtr(d.CreatedAt)},
+		Id:             d.Id,
+		ProblemId:      d.ProblemId,
+		ProblemVersion: d.ProblemVersion,
+		UserId:         d.UserId,
+		Result:         string(res),
+	}, nil
+}
+
+func (s *decisionStorageImpl) toDecisionDomain(d *decisionDto) (*domain.Decision, error) {
+	if d == nil {
+		return nil, nil
+	}
+	res := &decisionResult{}
+	if d.Result != "" {
+		if err := json.Unmarshal([]byte(d.Result), res); err != nil {
+			return nil, err
+		}
+	}
+	return &domain.Decision{
+		Id:             d.Id,
+		ProblemId:      d.ProblemId,
+		ProblemVersion: d.ProblemVersion,
+		UserId:         d.UserId,
+		Result: domain.DecisionResult{
+			Method:           res.Method,
+			OptionsRating:    res.OptionsRating,
+			Scoring:          res.Scoring,
+			Smoothing:        res.Smoothing,
+			ConsistencyRatio: res.ConsistencyRatio,
+			Simulation:       s.toSimulationResultDomain(res.Simulation),
+			Group:            s.toGroupResultDomain(res.Group),
+			Explanation:      s.toExplanationDomain(res.Explanation),
+		},
+		CreatedAt: timeVal(d.CreatedAt),
+	}, nil
+}
+
+func (s *decisionStorageImpl) toSimulationResultDto(sr *domain.SimulationResult) *simulationResult {
+	if sr == nil {
+		return nil
+	}
+	r := &simulationResult{
+		Iterations: sr.Iterations,
+		Seed:       sr.Seed,
+		Options:    make(map[string]*optionSimulation, len(sr.Options)),
+	}
+	for id, o := range sr.Options {
+		r.Options[id] = &optionSimulation{
+			Mean:            o.Mean,
+			StdDev:          o.StdDev,
+			P5:              o.P5,
+			P25:             o.P25,
+			P50:             o.P50,
+			P75:             o.P75,
+			P95:             o.P95,
+			ProbabilityBest: o.ProbabilityBest,
+		}
+	}
+	return r
+}
+
+func (s *decisionStorageImpl) toSimulationResultDomain(sr *simulationResult) *domain.SimulationResult {
+	if sr == nil {
+		return nil
+	}
+	r := &domain.SimulationResult{
+		Iterations: sr.Iterations,
+		Seed:       sr.Seed,
+		Options:    make(map[string]*domain.OptionSimulation, len(sr.Options)),
+	}
+	for id, o := range sr.Options {
+		r.Options[id] = &domain.OptionSimulation{
+			Mean:            o.Mean,
+			StdDev:          o.StdDev,
+			P5:              o.P5,
+			P25:             o.P25,
+			P50:             o.P50,
+			P75:             o.P75,
+			P95:             o.P95,
+			ProbabilityBest: o.ProbabilityBest,
+		}
+	}
+	return r
+}
+
+func (s *decisionStorageImpl) toGroupResultDto(g *domain.GroupResult) *groupResult {
+	if g == nil {
+		return nil
+	}
+	r := &groupResult{Aggregation: g.Aggregation}
+	for _, m := range g.Participants {
+		r.Participants = append(r.Participants, &member{UserId: m.UserId, Weight: m.Weight})
+	}
+	for _, sp := range g.Spread {
+		r.Spread = append(r.Spread, &qualitySpread{
+			QualityId:         sp.QualityId,
+			ImportanceMin:     sp.ImportanceMin,
+			ImportanceMax:     sp.ImportanceMax,
+			ImportanceStdDev:  sp.ImportanceStdDev,
+			ProbabilityMin:    sp.ProbabilityMin,
+			ProbabilityMax:    sp.ProbabilityMax,
+			ProbabilityStdDev: sp.ProbabilityStdDev,
+		})
+	}
+	return r
+}
+
+func (s *decisionStorageImpl) toGroupResultDomain(g *groupResult) *domain.GroupResult {
+	if g == nil {
+		return nil
+	}
+	r := &domain.GroupResult{Aggregation: g.Aggregation}
+	for _, m := range g.Participants {
+		r.Participants = append(r.Participants, &domain.Member{UserId: m.UserId, Weight: m.Weight})
+	}
+	for _, sp := range g.Spread {
+		r.Spread = append(r.Spread, &domain.QualitySpread{
+			QualityId:         sp.QualityId,
+			ImportanceMin:     sp.ImportanceMin,
+			ImportanceMax:     sp.ImportanceMax,
+			ImportanceStdDev:  sp.ImportanceStdDev,
+			ProbabilityMin:    sp.ProbabilityMin,
+			ProbabilityMax:    sp.ProbabilityMax,
+			ProbabilityStdDev: sp.ProbabilityStdDev,
+		})
+	}
+	return r
+}
+
+func (s *decisionStorageImpl) toExplanationDto(e *domain.Explanation) *explanation {
+	if e == nil {
+		return nil
+	}
+	r := &explanation{Margin: e.Margin, Summary: e.Summary}
+	for _, oe := range e.Ranking {
+		o := &optionExplanation{
+			OptionId:  oe.OptionId,
+			Name:      oe.Name,
+			Rank:      oe.Rank,
+			Rating:    oe.Rating,
+			ProsTotal: oe.ProsTotal,
+			ConsTotal: oe.ConsTotal,
+		}
+		for _, c := range oe.Contributions {
+			o.Contributions = append(o.Contributions, &qualityContribution{
+				QualityId: c.QualityId,
+				Name:      c.Name,
+				Kind:      c.Kind,
+				Weight:    c.Weight,
+				Share:     c.Share,
+			})
+		}
+		r.Ranking = append(r.Ranking, o)
+	}
+	return r
+}
+
+func (s *decisionStorageImpl) toExplanationDomain(e *explanation) *domain.Explanation {
+	if e == nil {
+		return nil
+	}
+	r := &domain.Explanation{Margin: e.Margin, Summary: e.Summary}
+	for _, oe := range e.Ranking {
+		o := &domain.OptionExplanation{
+			OptionId:  oe.OptionId,
+			Name:      oe.Name,
+			Rank:      oe.Rank,
+			Rating:    oe.Rating,
+			ProsTotal: oe.ProsTotal,
+			ConsTotal: oe.ConsTotal,
+		}
+		for _, c := range oe.Contributions {
+			o.Contributions = append(o.Contributions, &domain.QualityContribution{
+				QualityId: c.QualityId,
+				Name:      c.Name,
+				Kind:      c.Kind,
+				Weight:    c.Weight,
+				Share:     c.Share,
+			})
+		}
+		r.Ranking = append(r.Ranking, o)
+	}
+	return r
+}
+
+func (s *groupStorageImpl) toMemberDto(problemId string, m *domain.Member) *memberDto {
+	now := kit.Now()
+	return &memberDto{
+		GormDto:   pg.GormDto{CreatedAt: &now, UpdatedAt: &now},
+		Id:        kit.NewId(),
+		ProblemId: problemId,
+		UserId:    m.UserId,
+		Weight:    m.Weight,
+	}
+}
+
+func (s *groupStorageImpl) toMemberDomain(m *memberDto) *domain.Member {
+	return &domain.Member{
+		UserId: m.UserId,
+		Weight: m.Weight,
+	}
+}
+
+func (s *groupStorageImpl) toAssessmentDto(problemId, userId string, a *domain.Assessment) *assessmentDto {
+	now := kit.Now()
+	return &assessmentDto{
+		GormDto:     pg.GormDto{CreatedAt: &now, UpdatedAt: &now},
+		Id:          kit.NewId(),
+		ProblemId:   problemId,
+		UserId:      userId,
+		QualityId:   a.QualityId,
+		Importance:  a.Importance,
+		Probability: a.Probability,
+	}
+}
+
+func (s *groupStorageImpl) toAssessmentDomain(a *assessmentDto) *domain.Assessment {
+	return &domain.Assessment{
+		UserId:      a.UserId,
+		QualityId:   a.QualityId,
+		Importance:  a.Importance,
+		Probability: a.Probability,
+	}
+}
+
+func (s *templateStorageImpl) toTemplateDto(t *domain.Template) (*templateDto, error) {
+	r := &templateDto{
+		GormDto:     pg.GormDto{CreatedAt: timePtr(t.CreatedAt), UpdatedAt: timePtr(t.UpdatedAt)},
+		Id:          t.Id,
+		UserId:      nilIfEmpty(t.UserId),
+		Name:        t.Name,
+		Description: nilIfEmpty(t.Description),
+		Category:    nilIfEmpty(t.Category),
+	}
+	criteria := make([]*templateCriterion, 0, len(t.Criteria))
+	for _, c := range t.Criteria {
+		criteria = append(criteria, &templateCriterion{Name: c.Name, Kind: c.Kind, Importance: c.Importance, Probability: c.Probability})
+	}
+	cj, err := json.Marshal(criteria)
+	if err != nil {
+		return nil, err
+	}
+	r.Criteria = string(cj)
+	if len(t.OptionFields) > 0 {
+		fields := make([]*templateField, 0, len(t.OptionFields))
+		for _, f := range t.OptionFields {
+			fields = append(fields, &templateField{Name: f.Name, Type: f.Type, Required: f.Required})
+		}
+		fj, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		r.OptionFields = kit.StringPtr(string(fj))
+	}
+	return r, nil
+}
+
+func (s *templateStorageImpl) toTemplateDomain(t *templateDto) (*domain.Template, error) {
+	r := &domain.Template{
+		Id:          t.Id,
+		UserId:      valOrEmpty(t.UserId),
+		Name:        t.Name,
+		Description: valOrEmpty(t.Description),
+		Category:    valOrEmpty(t.Category),
+		CreatedAt:   timeVal(t.CreatedAt),
+		UpdatedAt:   timeVal(t.UpdatedAt),
+	}
+	var criteria []*templateCriterion
+	if err := json.Unmarshal([]byte(t.Criteria), &criteria); err != nil {
+		return nil, err
+	}
+	for _, c := range criteria {
+		r.Criteria = append(r.Criteria, &domain.TemplateCriterion{Name: c.Name, Kind: c.Kind, Importance: c.Importance, Probability: c.Probability})
+	}
+	if t.OptionFields != nil && *t.OptionFields != "" {
+		var fields []*templateField
+		if err := json.Unmarshal([]byte(*t.OptionFields), &fields); err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			r.OptionFields = append(r.OptionFields, &domain.TemplateField{Name: f.Name, Type: f.Type, Required: f.Required})
+		}
+	}
+	return r, nil
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func timeVal(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func valOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"gorm.io/gorm"
+)
+
+type groupStorageImpl struct {
+	a *adapterImpl
+}
+
+func newGroupStorage(a *adapterImpl) *groupStorageImpl {
+	return &groupStorageImpl{a: a}
+}
+
+func (s *groupStorageImpl) l() kit.CLogger {
+	return decision.L().Cmp("group-storage")
+}
+
+func (s *groupStorageImpl) SetMembers(ctx context.Context, problemId string, members []*domain.Member) error {
+	s.l().C(ctx).Mth("set-members").Dbg()
+
+	err := s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("problem_id = ?", problemId).Delete(&memberDto{}).Error; err != nil {
+			return err
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		dtos := make([]*memberDto, 0, len(members))
+		for _, m := range members {
+			dtos = append(dtos, s.toMemberDto(problemId, m))
+		}
+		return tx.Create(dtos).Error
+	})
+	if err != nil {
+		return errors.ErrStorageMembersSet(ctx, err)
+	}
+	return nil
+}
+
+func (s *groupStorageImpl) GetMembers(ctx context.Context, problemId string) ([]*domain.Member, error) {
+	s.l().C(ctx).Mth("get-members").Dbg()
+
+	var dtos []*memberDto
+	if err := s.a.pg.Instance.WithContext(ctx).Where("problem_id = ?", problemId).Order("created_at").Find(&dtos).Error; err != nil {
+		return nil, errors.ErrStorageMembersGet(ctx, err)
+	}
+	r := make([]*domain.Member, 0, len(dtos))
+	for _, dto := range dtos {
+		r = append(r, s.toMemberDomain(dto))
+	}
+	return r, nil
+}
+
+func (s *groupStorageImpl) SetAssessments(ctx context.Context, problemId, userId string, assessments []*domain.Assessment) error {
+	s.l().C(ctx).Mth("set-assessments").Dbg()
+
+	err := s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("problem_id = ? and user_id = ?", problemId, userId).Delete(&assessmentDto{}).Error; err != nil {
+			return err
+		}
+		if len(assessments) == 0 {
+			return nil
+		}
+		dtos := make([]*assessmentDto, 0, len(assessments))
+		for _, a := range assessments {
+			dtos = append(dtos, s.toAssessmentDto(problemId, userId, a))
+		}
+		return tx.Create(dtos).Error
+	})
+	if err != nil {
+		return errors.ErrStorageAssessmentsSet(ctx, err)
+	}
+	return nil
+}
+
+func (s *groupStorageImpl) GetAssessments(ctx context.Context, problemId string) ([]*domain.Assessment, error) {
+	s.l().C(ctx).Mth("get-assessments").Dbg()
+
+	var dtos []*assessmentDto
+	if err := s.a.pg.Instance.WithContext(ctx).Where("problem_id = ?", problemId).Order("user_id").Find(&dtos).Error; err != nil {
+		return nil, errors.ErrStorageAssessmentsGet(ctx, err)
+	}
+	r := make([]*domain.Assessment, 0, len(dtos))
+	for _, dto := range dtos {
+		r = append(r, s.toAssessmentDomain(dto))
+	}
+	return r, nil
+}
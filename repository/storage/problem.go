@@ -0,0 +1,574 @@
+package storage
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/mikhailbolshakov/decision/kit/storages/pg"
+	"gorm.io/gorm"
+)
+
+type problemStorageImpl struct {
+	a *adapterImpl
+}
+
+func newProblemStorage(a *adapterImpl) *problemStorageImpl {
+	return &problemStorageImpl{a: a}
+}
+
+func (s *problemStorageImpl) l() kit.CLogger {
+	return decision.L().Cmp("problem-storage")
+}
+
+func (s *problemStorageImpl) CreateProblem(ctx context.Context, p *domain.Problem) error {
+	s.l().C(ctx).Mth("create").Dbg()
+
+	pr, ops, qs, err := s.toProblemDto(p)
+	if err != nil {
+		return errors.ErrStorageProblemMarshal(ctx, err)
+	}
+
+	err = s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pr).Error; err != nil {
+			return err
+		}
+		if err := s.createOptions(tx, ops, qs); err != nil {
+			return err
+		}
+		p.Version, err = s.createVersion(tx, pr.Id)
+		return err
+	})
+	if err != nil {
+		return errors.ErrStorageProblemCreate(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) UpdateProblem(ctx context.Context, p *domain.Problem) error {
+	s.l().C(ctx).Mth("update").Dbg()
+
+	pr, ops, qs, err := s.toProblemDto(p)
+	if err != nil {
+		return errors.ErrStorageProblemMarshal(ctx, err)
+	}
+
+	err = s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		upd := map[string]interface{}{"name": pr.Name, "method": pr.Method, "pros_cons": pr.ProsCons, "ahp": pr.Ahp, "topsis": pr.Topsis, "simulation": pr.Simulation, "updated_at": pr.UpdatedAt}
+		if err := tx.Model(pr).Updates(upd).Error; err != nil {
+			return err
+		}
+		if err := s.mergeOptions(tx, pr.Id, ops, qs); err != nil {
+			return err
+		}
+		p.Version, err = s.createVersion(tx, pr.Id)
+		return err
+	})
+	if err != nil {
+		return errors.ErrStorageProblemUpdate(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) GetProblem(ctx context.Context, problemId string) (*domain.Problem, error) {
+	s.l().C(ctx).Mth("get").Dbg()
+
+	pr, ops, qs, err := s.load(s.a.pg.Instance.WithContext(ctx), problemId)
+	if err != nil {
+		return nil, errors.ErrStorageProblemGet(ctx, err)
+	}
+	if pr == nil {
+		return nil, nil
+	}
+
+	r, err := s.toProblemDomain(pr, ops, qs)
+	if err != nil {
+		return nil, errors.ErrStorageProblemUnmarshal(ctx, err)
+	}
+	return r, nil
+}
+
+func (s *problemStorageImpl) ProblemExists(ctx context.Context, problemId string) (bool, error) {
+	s.l().C(ctx).Mth("exists").Dbg()
+
+	var count int64
+	if err := s.a.pg.Instance.WithContext(ctx).Unscoped().Model(&problemDto{}).Where("id = ?", problemId).Count(&count).Error; err != nil {
+		return false, errors.ErrStorageProblemGet(ctx, err)
+	}
+	return count > 0, nil
+}
+
+func (s *problemStorageImpl) GetForeignIds(ctx context.Context, problemId string, optionIds, qualityIds []string) ([]string, error) {
+	s.l().C(ctx).Mth("get-foreign-ids").Dbg()
+
+	db := s.a.pg.Instance.WithContext(ctx).Unscoped()
+	var r []string
+	for _, t := range []struct {
+		model interface{}
+		ids   []string
+	}{{&optionDto{}, optionIds}, {&qualityDto{}, qualityIds}} {
+		if len(t.ids) == 0 {
+			continue
+		}
+		var ids []string
+		if err := db.Model(t.model).Where("id in ? and problem_id <> ?", t.ids, problemId).Pluck("id", &ids).Error; err != nil {
+			return nil, errors.ErrStorageProblemGet(ctx, err)
+		}
+		r = append(r, ids...)
+	}
+	return r, nil
+}
+
+func (s *problemStorageImpl) SearchProblems(ctx context.Context, criteria *domain.ProblemSearchCriteria) (*domain.ProblemSearchResponse, error) {
+	s.l().C(ctx).Mth("search").Dbg()
+
+	query := s.a.pg.Instance.WithContext(ctx).Model(&problemDto{})
+	if criteria.UserId != "" {
+		query = query.Where("user_id = ?", criteria.UserId)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.ErrStorageProblemSearch(ctx, err)
+	}
+
+	// sorting
+	for _, sort := range criteria.SortBy {
+		if col, ok := problemSortColumns[sort.Field]; ok {
+			query = query.Order(orderClause(col, sort))
+		}
+	}
+	if len(criteria.SortBy) == 0 {
+		query = query.Order("created_at desc")
+	}
+
+	// paging
+	if criteria.Size > 0 {
+		query = query.Limit(criteria.Size)
+		if criteria.Index > 1 {
+			query = query.Offset((criteria.Index - 1) * criteria.Size)
+		}
+	}
+
+	var dtos []*problemDto
+	if err := query.Find(&dtos).Error; err != nil {
+		return nil, errors.ErrStorageProblemSearch(ctx, err)
+	}
+
+	r := &domain.ProblemSearchResponse{
+		PagingResponse: kit.PagingResponse{
+			Total: int(total),
+			Index: criteria.Index,
+		},
+	}
+	for _, dto := range dtos {
+		p, err := s.toProblemDomain(dto, nil, nil)
+		if err != nil {
+			return nil, errors.ErrStorageProblemUnmarshal(ctx, err)
+		}
+		r.Problems = append(r.Problems, p)
+	}
+	return r, nil
+}
+
+func (s *problemStorageImpl) DeleteProblem(ctx context.Context, problemId string) error {
+	s.l().C(ctx).Mth("delete").Dbg()
+
+	err := s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("problem_id = ?", problemId).Delete(&qualityDto{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("problem_id = ?", problemId).Delete(&optionDto{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", problemId).Delete(&problemDto{}).Error
+	})
+	if err != nil {
+		return errors.ErrStorageProblemDelete(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) CreateOption(ctx context.Context, problemId string, option *domain.Option) error {
+	s.l().C(ctx).Mth("create-option").Dbg()
+
+	err := s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// new option goes last
+		var cnt int64
+		if err := tx.Unscoped().Model(&optionDto{}).Where("problem_id = ?", problemId).Count(&cnt).Error; err != nil {
+			return err
+		}
+		fields, err := s.toFieldsDto(option.Fields)
+		if err != nil {
+			return err
+		}
+		pr := &problemDto{Id: problemId}
+		op := &optionDto{
+			Id:        option.Id,
+			ProblemId: problemId,
+			Name:      option.Name,
+			Fields:    fields,
+			Ord:       int(cnt),
+		}
+		qs, err := s.toOptionQualitiesDto(pr, option)
+		if err != nil {
+			return err
+		}
+		if err := s.createOptions(tx, []*optionDto{op}, qs); err != nil {
+			return err
+		}
+		_, err = s.createVersion(tx, problemId)
+		return err
+	})
+	if err != nil {
+		return errors.ErrStorageOptionCreate(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) UpdateOption(ctx context.Context, option *domain.Option) error {
+	s.l().C(ctx).Mth("update-option").Dbg()
+
+	err := s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		fields, err := s.toFieldsDto(option.Fields)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&optionDto{Id: option.Id}).Updates(map[string]interface{}{"name": option.Name, "fields": fields}).Error; err != nil {
+			return err
+		}
+		return s.createVersionOf(tx, &optionDto{}, option.Id)
+	})
+	if err != nil {
+		return errors.ErrStorageOptionUpdate(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) DeleteOption(ctx context.Context, problem *domain.Problem, optionId string) error {
+	s.l().C(ctx).Mth("delete-option").Dbg()
+
+	pr, _, _, err := s.toProblemDto(problem)
+	if err != nil {
+		return errors.ErrStorageProblemMarshal(ctx, err)
+	}
+
+	err = s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&problemDto{Id: problem.Id}).Updates(map[string]interface{}{"ahp": pr.Ahp, "topsis": pr.Topsis}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("option_id = ?", optionId).Delete(&qualityDto{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", optionId).Delete(&optionDto{}).Error; err != nil {
+			return err
+		}
+		return s.createVersionOf(tx, &optionDto{}, optionId)
+	})
+	if err != nil {
+		return errors.ErrStorageOptionDelete(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) CreateQuality(ctx context.Context, problemId, optionId, kind string, quality *domain.Quality) error {
+	s.l().C(ctx).Mth("create-quality").Dbg()
+
+	err := s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// new quality goes last
+		var cnt int64
+		if err := tx.Unscoped().Model(&qualityDto{}).Where("option_id = ? and kind = ?", optionId, kind).Count(&cnt).Error; err != nil {
+			return err
+		}
+		qs, err := s.toQualitiesDto(&problemDto{Id: problemId}, optionId, kind, []*domain.Quality{quality})
+		if err != nil {
+			return err
+		}
+		qs[0].Ord = int(cnt)
+		if err := tx.Create(qs[0]).Error; err != nil {
+			return err
+		}
+		_, err = s.createVersion(tx, problemId)
+		return err
+	})
+	if err != nil {
+		return errors.ErrStorageQualityCreate(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) UpdateQuality(ctx context.Context, quality *domain.Quality) error {
+	s.l().C(ctx).Mth("update-quality").Dbg()
+
+	impDist, err := s.toDistributionDto(quality.ImportanceDist)
+	if err != nil {
+		return errors.ErrStorageQualityUpdate(ctx, err)
+	}
+	probDist, err := s.toDistributionDto(quality.ProbabilityDist)
+	if err != nil {
+		return errors.ErrStorageQualityUpdate(ctx, err)
+	}
+
+	err = s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&qualityDto{Id: quality.Id}).Updates(map[string]interface{}{
+			"name":             quality.Name,
+			"importance":       quality.Importance,
+			"probability":      quality.Probability,
+			"importance_dist":  impDist,
+			"probability_dist": probDist,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return s.createVersionOf(tx, &qualityDto{}, quality.Id)
+	})
+	if err != nil {
+		return errors.ErrStorageQualityUpdate(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) DeleteQuality(ctx context.Context, qualityId string) error {
+	s.l().C(ctx).Mth("delete-quality").Dbg()
+
+	err := s.a.pg.Instance.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", qualityId).Delete(&qualityDto{}).Error; err != nil {
+			return err
+		}
+		return s.createVersionOf(tx, &qualityDto{}, qualityId)
+	})
+	if err != nil {
+		return errors.ErrStorageQualityDelete(ctx, err)
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) GetProblemVersions(ctx context.Context, problemId string) ([]*domain.ProblemVersion, error) {
+	s.l().C(ctx).Mth("get-versions").Dbg()
+
+	var dtos []*problemVersionDto
+	if err := s.a.pg.Instance.WithContext(ctx).Where("problem_id = ?", problemId).Order("version").Find(&dtos).Error; err != nil {
+		return nil, errors.ErrStorageProblemVersionGet(ctx, err)
+	}
+	return s.toProblemVersionsDomain(dtos), nil
+}
+
+func (s *problemStorageImpl) GetProblemVersion(ctx context.Context, problemId string, version int) (*domain.Problem, error) {
+	s.l().C(ctx).Mth("get-version").Dbg()
+
+	db := s.a.pg.Instance.WithContext(ctx)
+
+	pr := &problemDto{}
+	res := db.Where("id = ?", problemId).Limit(1).Find(pr)
+	if res.Error != nil {
+		return nil, errors.ErrStorageProblemVersionGet(ctx, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	v := &problemVersionDto{}
+	res = db.Where("problem_id = ? and version = ?", problemId, version).Limit(1).Find(v)
+	if res.Error != nil {
+		return nil, errors.ErrStorageProblemVersionGet(ctx, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	r, err := s.toProblemVersionDomain(pr, v)
+	if err != nil {
+		return nil, errors.ErrStorageProblemUnmarshal(ctx, err)
+	}
+	return r, nil
+}
+
+// load retrieves the problem with options and qualities, returns nil if not found
+func (s *problemStorageImpl) load(db *gorm.DB, problemId string) (*problemDto, []*optionDto, []*qualityDto, error) {
+	pr := &problemDto{}
+	res := db.Where("id = ?", problemId).Limit(1).Find(pr)
+	if res.Error != nil {
+		return nil, nil, nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	var ops []*optionDto
+	if err := db.Where("problem_id = ?", problemId).Order("ord").Find(&ops).Error; err != nil {
+		return nil, nil, nil, err
+	}
+
+	var qs []*qualityDto
+	if err := db.Where("problem_id = ?", problemId).Order("ord").Find(&qs).Error; err != nil {
+		return nil, nil, nil, err
+	}
+	return pr, ops, qs, nil
+}
+
+// createVersion increments the problem version and stores a snapshot of the current state
+// it must be called within the transaction changing the problem, so that the snapshot is consistent
+func (s *problemStorageImpl) createVersion(tx *gorm.DB, problemId string) (int, error) {
+	now := kit.Now()
+	// the row stays locked until the transaction ends, so concurrent changes get sequential versions
+	upd := map[string]interface{}{"version": gorm.Expr("version + 1"), "updated_at": now}
+	if err := tx.Model(&problemDto{}).Where("id = ?", problemId).Updates(upd).Error; err != nil {
+		return 0, err
+	}
+	pr, ops, qs, err := s.load(tx, problemId)
+	if err != nil {
+		return 0, err
+	}
+	if pr == nil {
+		return 0, gorm.ErrRecordNotFound
+	}
+	snapshot, err := s.toProblemSnapshot(pr, ops, qs)
+	if err != nil {
+		return 0, err
+	}
+	v := &problemVersionDto{
+		GormDto:   pg.GormDto{CreatedAt: &now, UpdatedAt: &now},
+		Id:        kit.NewId(),
+		ProblemId: problemId,
+		Version:   pr.Version,
+		Snapshot:  snapshot,
+	}
+	if err := tx.Create(v).Error; err != nil {
+		return 0, err
+	}
+	return pr.Version, nil
+}
+
+// createVersionOf creates a new version of the problem the option or quality belongs to
+func (s *problemStorageImpl) createVersionOf(tx *gorm.DB, model interface{}, id string) error {
+	var problemIds []string
+	if err := tx.Unscoped().Model(model).Where("id = ?", id).Pluck("problem_id", &problemIds).Error; err != nil {
+		return err
+	}
+	if len(problemIds) == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	_, err := s.createVersion(tx, problemIds[0])
+	return err
+}
+
+// mergeOptions updates stored options and qualities of the problem by id, creates new ones and deletes the rest
+// qualities are kept in place, since group assessments refer to them
+func (s *problemStorageImpl) mergeOptions(tx *gorm.DB, problemId string, ops []*optionDto, qs []*qualityDto) error {
+	var storedOps, storedQs []string
+	if err := tx.Unscoped().Model(&optionDto{}).Where("problem_id = ?", problemId).Pluck("id", &storedOps).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&qualityDto{}).Where("problem_id = ?", problemId).Pluck("id", &storedQs).Error; err != nil {
+		return err
+	}
+	removedOps, removedQs := idSet(storedOps), idSet(storedQs)
+
+	var newOps []*optionDto
+	for _, op := range ops {
+		if _, ok := removedOps[op.Id]; !ok {
+			newOps = append(newOps, op)
+			continue
+		}
+		delete(removedOps, op.Id)
+		upd := map[string]interface{}{"name": op.Name, "fields": op.Fields, "ord": op.Ord, "updated_at": op.UpdatedAt, "deleted_at": nil}
+		if err := tx.Unscoped().Model(&optionDto{}).Where("id = ?", op.Id).Updates(upd).Error; err != nil {
+			return err
+		}
+	}
+	var newQs []*qualityDto
+	for _, q := range qs {
+		if _, ok := removedQs[q.Id]; !ok {
+			newQs = append(newQs, q)
+			continue
+		}
+		delete(removedQs, q.Id)
+		upd := map[string]interface{}{
+			"option_id":        q.OptionId,
+			"kind":             q.Kind,
+			"name":             q.Name,
+			"importance":       q.Importance,
+			"probability":      q.Probability,
+			"importance_dist":  q.ImportanceDist,
+			"probability_dist": q.ProbabilityDist,
+			"ord":              q.Ord,
+			"updated_at":       q.UpdatedAt,
+			"deleted_at":       nil,
+		}
+		if err := tx.Unscoped().Model(&qualityDto{}).Where("id = ?", q.Id).Updates(upd).Error; err != nil {
+			return err
+		}
+	}
+	if err := s.createOptions(tx, newOps, newQs); err != nil {
+		return err
+	}
+
+	// assessments of removed qualities make no sense anymore
+	if len(removedQs) > 0 {
+		ids := idSetKeys(removedQs)
+		if err := tx.Unscoped().Where("quality_id in ?", ids).Delete(&assessmentDto{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("id in ?", ids).Delete(&qualityDto{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(removedOps) > 0 {
+		if err := tx.Unscoped().Where("id in ?", idSetKeys(removedOps)).Delete(&optionDto{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *problemStorageImpl) createOptions(tx *gorm.DB, ops []*optionDto, qs []*qualityDto) error {
+	if len(ops) > 0 {
+		if err := tx.Create(ops).Error; err != nil {
+			return err
+		}
+	}
+	if len(qs) > 0 {
+		if err := tx.Create(qs).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func idSet(ids []string) map[string]struct{} {
+	r := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		r[id] = struct{}{}
+	}
+	return r
+}
+
+func idSetKeys(ids map[string]struct{}) []string {
+	r := make([]string, 0, len(ids))
+	for id := range ids {
+		r = append(r, id)
+	}
+	return r
+}
+
+// problemSortColumns maps allowed sort fields to columns
+var problemSortColumns = map[string]string{
+	"name":      "name",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+}
+
+func orderClause(column string, sort *kit.SortRequest) string {
+	r := column
+	if !sort.Asc {
+		r += " desc"
+	}
+	switch sort.Missing {
+	case kit.SortRequestMissingFirst:
+		r += " nulls first"
+	case kit.SortRequestMissingLast:
+		r += " nulls last"
+	}
+	return r
+}
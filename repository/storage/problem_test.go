@@ -0,0 +1,130 @@
+//go:build integration
+
+package storage
+
+import (
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/kit"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type problemStorageTestSuite struct {
+	kit.Suite
+	adapter Adapter
+}
+
+func (s *problemStorageTestSuite) SetupSuite() {
+	s.Suite.Init(decision.LF())
+	cfg, err := decision.LoadConfig()
+	if err != nil {
+		s.T().Fatal(err)
+	}
+	s.adapter = NewAdapter()
+	if err := s.adapter.Init(s.Ctx, cfg.Storages.Database); err != nil {
+		s.T().Fatal(err)
+	}
+}
+
+func (s *problemStorageTestSuite) TearDownSuite() {
+	_ = s.adapter.Close(s.Ctx)
+}
+
+func TestProblemStorageSuite(t *testing.T) {
+	suite.Run(t, new(problemStorageTestSuite))
+}
+
+func (s *problemStorageTestSuite) problem() *domain.Problem {
+	return &domain.Problem{
+		Id:        kit.NewId(),
+		UserId:    kit.NewId(),
+		Name:      "problem",
+		Method:    domain.MethodProsCons,
+		CreatedAt: kit.Now(),
+		UpdatedAt: kit.Now(),
+		Options: []*domain.Option{
+			{
+				Id:   kit.NewId(),
+				Name: "first",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 10, Probability: 1}},
+				Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 5, Probability: 1}},
+			},
+			{
+				Id:   kit.NewId(),
+				Name: "second",
+				Pros: []*domain.Quality{{Id: kit.NewId(), Name: "pro", Importance: 5, Probability: 1}},
+			},
+		},
+	}
+}
+
+func (s *problemStorageTestSuite) Test_UpdateProblem_WithAssessments() {
+	problems, groups := s.adapter.GetProblemStorage(), s.adapter.GetGroupStorage()
+
+	p := s.problem()
+	s.NoError(problems.CreateProblem(s.Ctx, p))
+
+	kept, removed := p.Options[0].Pros[0], p.Options[0].Cons[0]
+	memberId := kit.NewId()
+	s.NoError(groups.SetAssessments(s.Ctx, p.Id, memberId, []*domain.Assessment{
+		{UserId: memberId, QualityId: kept.Id, Importance: 8, Probability: 1},
+		{UserId: memberId, QualityId: removed.Id, Importance: 3, Probability: 1},
+	}))
+
+	// update a quality, remove a quality and an option, add a new option
+	kept.Importance = 9
+	p.Options[0].Cons = nil
+	p.Options = []*domain.Option{p.Options[0], {Id: kit.NewId(), Name: "third", Cons: []*domain.Quality{{Id: kit.NewId(), Name: "con", Importance: 1, Probability: 1}}}}
+	p.UpdatedAt = kit.Now()
+	s.NoError(problems.UpdateProblem(s.Ctx, p))
+	s.Equal(2, p.Version)
+
+	stored, err := problems.GetProblem(s.Ctx, p.Id)
+	s.NoError(err)
+	s.Len(stored.Options, 2)
+	s.Equal(p.Options[0].Id, stored.Options[0].Id)
+	s.Equal(kept.Id, stored.Options[0].Pros[0].Id)
+	s.Equal(9.0, stored.Options[0].Pros[0].Importance)
+	s.Empty(stored.Options[0].Cons)
+	s.Equal("third", stored.Options[1].Name)
+
+	// assessments of the removed quality are deleted
+	assessments, err := groups.GetAssessments(s.Ctx, p.Id)
+	s.NoError(err)
+	s.Len(assessments, 1)
+	s.Equal(kept.Id, assessments[0].QualityId)
+
+	// the problem can be updated again
+	s.NoError(problems.UpdateProblem(s.Ctx, p))
+	s.Equal(3, p.Version)
+}
+
+func (s *problemStorageTestSuite) Test_ForeignIds_DeletedProblem() {
+	problems := s.adapter.GetProblemStorage()
+
+	p, other := s.problem(), s.problem()
+	s.NoError(problems.CreateProblem(s.Ctx, p))
+	s.NoError(problems.CreateProblem(s.Ctx, other))
+
+	// ids of the problem itself and new ones aren't foreign
+	ids, err := problems.GetForeignIds(s.Ctx, p.Id, []string{p.Options[0].Id, kit.NewId()}, []string{p.Options[0].Pros[0].Id})
+	s.NoError(err)
+	s.Empty(ids)
+
+	ids, err = problems.GetForeignIds(s.Ctx, p.Id, []string{other.Options[0].Id}, []string{other.Options[1].Pros[0].Id})
+	s.NoError(err)
+	s.ElementsMatch([]string{other.Options[0].Id, other.Options[1].Pros[0].Id}, ids)
+
+	// a deleted problem still exists, though it isn't retrieved
+	s.NoError(problems.DeleteProblem(s.Ctx, other.Id))
+	stored, err := problems.GetProblem(s.Ctx, other.Id)
+	s.NoError(err)
+	s.Nil(stored)
+	exists, err := problems.ProblemExists(s.Ctx, other.Id)
+	s.NoError(err)
+	s.True(exists)
+	exists, err = problems.ProblemExists(s.Ctx, kit.NewId())
+	s.NoError(err)
+	s.False(exists)
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"github.com/mikhailbolshakov/decision"
+	domain "github.com/mikhailbolshakov/decision/domain/decision"
+	"github.com/mikhailbolshakov/decision/errors"
+	"github.com/mikhailbolshakov/decision/kit"
+)
+
+type templateStorageImpl struct {
+	a *adapterImpl
+}
+
+func newTemplateStorage(a *adapterImpl) *templateStorageImpl {
+	return &templateStorageImpl{a: a}
+}
+
+func (s *templateStorageImpl) l() kit.CLogger {
+	return decision.L().Cmp("template-storage")
+}
+
+func (s *templateStorageImpl) CreateTemplate(ctx context.Context, template *domain.Template) error {
+	s.l().C(ctx).Mth("create").Dbg()
+
+	dto, err := s.toTemplateDto(template)
+	if err != nil {
+		return errors.ErrStorageTemplateCreate(ctx, err)
+	}
+	if err := s.a.pg.Instance.WithContext(ctx).Create(dto).Error; err != nil {
+		return errors.ErrStorageTemplateCreate(ctx, err)
+	}
+	return nil
+}
+
+func (s *templateStorageImpl) UpdateTemplate(ctx context.Context, template *domain.Template) error {
+	s.l().C(ctx).Mth("update").Dbg()
+
+	dto, err := s.toTemplateDto(template)
+	if err != nil {
+		return errors.ErrStorageTemplateUpdate(ctx, err)
+	}
+	err = s.a.pg.Instance.WithContext(ctx).Model(&templateDto{Id: template.Id}).Updates(map[string]interface{}{
+		"name":          dto.Name,
+		"description":   dto.Description,
+		"category":      dto.Category,
+		"criteria":      dto.Criteria,
+		"option_fields": dto.OptionFields,
+		"updated_at":    dto.UpdatedAt,
+	}).Error
+	if err != nil {
+		return errors.ErrStorageTemplateUpdate(ctx, err)
+	}
+	return nil
+}
+
+func (s *templateStorageImpl) GetTemplate(ctx context.Context, templateId string) (*domain.Template, error) {
+	s.l().C(ctx).Mth("get").Dbg()
+
+	dto := &templateDto{}
+	res := s.a.pg.Instance.WithContext(ctx).Where("id = ?", templateId).Limit(1).Find(dto)
+	if res.Error != nil {
+		return nil, errors.ErrStorageTemplateGet(ctx, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	r, err := s.toTemplateDomain(dto)
+	if err != nil {
+		return nil, errors.ErrStorageTemplateGet(ctx, err)
+	}
+	return r, nil
+}
+
+func (s *templateStorageImpl) SearchTemplates(ctx context.Context, criteria *domain.TemplateSearchCriteria) (*domain.TemplateSearchResponse, error) {
+	s.l().C(ctx).Mth("search").Dbg()
+
+	query := s.a.pg.Instance.WithContext(ctx).Model(&templateDto{})
+	if criteria.UserId != "" {
+		query = query.Where("user_id is null or user_id = ?", criteria.UserId)
+	} else {
+		query = query.Where("user_id is null")
+	}
+	if criteria.Category != "" {
+		query = query.Where("category = ?", criteria.Category)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.ErrStorageTemplateSearch(ctx, err)
+	}
+
+	// system templates go first
+	query = query.Order("user_id nulls first").Order("name")
+
+	// paging
+	if criteria.Size > 0 {
+		query = query.Limit(criteria.Size)
+		if criteria.Index > 1 {
+			query = query.Offset((criteria.Index - 1) * criteria.Size)
+		}
+	}
+
+	var dtos []*templateDto
+	if err := query.Find(&dtos).Error; err != nil {
+		return nil, errors.ErrStorageTemplateSearch(ctx, err)
+	}
+
+	r := &domain.TemplateSearchResponse{
+		PagingResponse: kit.PagingResponse{
+			Total: int(total),
+			Index: criteria.Index,
+		},
+	}
+	for _, dto := range dtos {
+		t, err := s.toTemplateDomain(dto)
+		if err != nil {
+			return nil, errors.ErrStorageTemplateSearch(ctx, err)
+		}
+		r.Templates = append(r.Templates, t)
+	}
+	return r, nil
+}
+
+func (s *templateStorageImpl) DeleteTemplate(ctx context.Context, templateId string) error {
+	s.l().C(ctx).Mth("delete").Dbg()
+
+	if err := s.a.pg.Instance.WithContext(ctx).Where("id = ?", templateId).Delete(&templateDto{}).Error; err != nil {
+		return errors.ErrStorageTemplateDelete(ctx, err)
+	}
+	return nil
+}